@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/krittawatcode/books/domain"
+)
+
+// APIKeyHeader carries the API key of service clients
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator verifies the API key sent in the X-API-Key
+// header or as Authorization: ApiKey <key>
+type APIKeyAuthenticator struct {
+	APIKeyUseCase domain.APIKeyUseCase
+}
+
+func NewAPIKeyAuthenticator(au domain.APIKeyUseCase) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{APIKeyUseCase: au}
+}
+
+// Authenticate returns the principal of the API key sent with r, or nil
+// when r carries no API key
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*domain.Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, "ApiKey") {
+			return nil, nil
+		}
+		key = strings.TrimSpace(value)
+	}
+
+	apiKey, err := a.APIKeyUseCase.AuthenticateAPIKey(r.Context(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Principal{
+		Subject: "api-key:" + apiKey.ID.String(),
+		Method:  domain.AuthMethodAPIKey,
+		Scopes:  apiKey.Scopes,
+	}, nil
+}
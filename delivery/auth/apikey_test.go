@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	id := uuid.New()
+
+	headers := map[string]http.Header{
+		"X-API-Key":             {"X-Api-Key": {"bk_valid"}},
+		"Authorization: ApiKey": {"Authorization": {"ApiKey bk_valid"}},
+	}
+
+	for name, header := range headers {
+		t.Run(name, func(t *testing.T) {
+			mockAPIKeyUseCase := new(appmock.MockAPIKeyUseCase)
+			mockAPIKeyUseCase.On("AuthenticateAPIKey", mock.Anything, "bk_valid").Return(&domain.APIKey{ID: id, Scopes: []string{domain.ScopeBooksRead}}, nil)
+
+			req, _ := http.NewRequest(http.MethodGet, "/books/", nil)
+			req.Header = header
+
+			p, err := NewAPIKeyAuthenticator(mockAPIKeyUseCase).Authenticate(req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, domain.AuthMethodAPIKey, p.Method)
+			assert.Equal(t, []string{domain.ScopeBooksRead}, p.Scopes)
+			mockAPIKeyUseCase.AssertExpectations(t)
+		})
+	}
+
+	t.Run("No API key", func(t *testing.T) {
+		mockAPIKeyUseCase := new(appmock.MockAPIKeyUseCase)
+
+		req, _ := http.NewRequest(http.MethodGet, "/books/", nil)
+		req.Header.Set("Authorization", "Bearer token")
+
+		p, err := NewAPIKeyAuthenticator(mockAPIKeyUseCase).Authenticate(req)
+
+		assert.NoError(t, err)
+		assert.Nil(t, p)
+		mockAPIKeyUseCase.AssertNotCalled(t, "AuthenticateAPIKey")
+	})
+
+	t.Run("Invalid API key", func(t *testing.T) {
+		mockAPIKeyUseCase := new(appmock.MockAPIKeyUseCase)
+		mockAPIKeyUseCase.On("AuthenticateAPIKey", mock.Anything, "bk_invalid").Return(&domain.APIKey{}, apperror.NewAuthorization("invalid API key"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/books/", nil)
+		req.Header.Set(APIKeyHeader, "bk_invalid")
+
+		p, err := NewAPIKeyAuthenticator(mockAPIKeyUseCase).Authenticate(req)
+
+		assert.Nil(t, p)
+		assert.Equal(t, http.StatusUnauthorized, apperror.Status(err))
+	})
+}
@@ -0,0 +1,206 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrJWKSUnavailable is returned when the key set cannot be fetched
+var ErrJWKSUnavailable = errors.New("JWKS unavailable")
+
+// JWKS verifies tokens with the keys published at a JWKS URL,
+// the keys are cached and refetched after TTL or for an unknown key ID.
+// While the URL cannot be fetched the cached keys are kept, and the
+// fetch is retried at most once per MinRefreshInterval
+type JWKS struct {
+	URL                string
+	TTL                time.Duration // how long fetched keys are trusted
+	MinRefreshInterval time.Duration // throttles refetches for unknown key IDs and failed fetches
+
+	client   *http.Client
+	fetching sync.Mutex // held for a fetch, so concurrent callers wait for one fetch
+
+	mu          sync.Mutex // guards the fields below, never held for a fetch
+	keys        []jwk
+	fetchedAt   time.Time // of the last successful fetch
+	attemptedAt time.Time // of the last fetch, failed or not
+	err         error     // of the last fetch
+}
+
+// jwk is a parsed JSON Web Key
+type jwk struct {
+	kid string
+	alg string
+	key interface{}
+}
+
+type jwkJSON struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// NewJWKS fetches keys from url with client, http.DefaultClient when nil
+func NewJWKS(url string, client *http.Client, ttl time.Duration) *JWKS {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &JWKS{
+		URL:                url,
+		TTL:                ttl,
+		MinRefreshInterval: 10 * time.Second,
+		client:             client,
+	}
+}
+
+func (j *JWKS) Key(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	alg := token.Method.Alg()
+
+	j.mu.Lock()
+	key, found := j.find(kid, alg)
+	attempted := j.attemptedAt
+	// refetch stale keys, or for an unknown key ID as the issuer may have
+	// rotated its keys
+	stale := j.fetchedAt.IsZero() || time.Since(j.fetchedAt) > j.TTL
+	refresh := (stale || !found) && (attempted.IsZero() || time.Since(attempted) > j.MinRefreshInterval)
+	fetched, lastErr := !j.fetchedAt.IsZero(), j.err
+	j.mu.Unlock()
+
+	if refresh {
+		if err := j.refresh(ctx, attempted); err != nil && !found {
+			return nil, err
+		}
+		j.mu.Lock()
+		key, found = j.find(kid, alg)
+		j.mu.Unlock()
+	} else if !found && !fetched {
+		// no key was ever fetched, the last fetch failed
+		return nil, lastErr
+	}
+
+	if !found {
+		return nil, ErrNoKey
+	}
+	return key, nil
+}
+
+// refresh fetches the keys unless another caller did since attempted,
+// the cached keys are kept when the fetch fails
+func (j *JWKS) refresh(ctx context.Context, attempted time.Time) error {
+	j.fetching.Lock()
+	defer j.fetching.Unlock()
+
+	j.mu.Lock()
+	if !j.attemptedAt.Equal(attempted) {
+		err := j.err
+		j.mu.Unlock()
+		return err
+	}
+	j.mu.Unlock()
+
+	keys, err := j.fetch(ctx)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.attemptedAt = time.Now()
+	j.err = err
+	if err == nil {
+		j.keys = keys
+		j.fetchedAt = j.attemptedAt
+	}
+	return err
+}
+
+// find returns the key with kid for alg, tokens without a kid match
+// when exactly one key supports alg, j.mu must be held
+func (j *JWKS) find(kid string, alg string) (interface{}, bool) {
+	var found []interface{}
+	for _, k := range j.keys {
+		if k.alg != alg {
+			continue
+		}
+		if kid != "" && k.kid == kid {
+			return k.key, true
+		}
+		found = append(found, k.key)
+	}
+
+	if kid == "" && len(found) == 1 {
+		return found[0], true
+	}
+	return nil, false
+}
+
+// fetch reads the key set at URL, skipping the keys which are not for
+// signatures or not supported
+func (j *JWKS) fetch(ctx context.Context) ([]jwk, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrJWKSUnavailable, err)
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrJWKSUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s returned %d", ErrJWKSUnavailable, j.URL, resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwkJSON `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrJWKSUnavailable, err)
+	}
+
+	keys := []jwk{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if parsed, ok := parseJWK(k); ok {
+			keys = append(keys, parsed)
+		}
+	}
+
+	return keys, nil
+}
+
+// parseJWK supports RSA keys for RS256, anything else is skipped.
+// Symmetric keys are skipped too: a key set is public, so anyone who
+// reads it could sign tokens with them
+func parseJWK(k jwkJSON) (jwk, bool) {
+	if k.Kty != "RSA" || (k.Alg != "" && k.Alg != jwt.SigningMethodRS256.Alg()) {
+		return jwk{}, false
+	}
+
+	n, errN := base64.RawURLEncoding.DecodeString(k.N)
+	e, errE := base64.RawURLEncoding.DecodeString(k.E)
+	if errN != nil || errE != nil || len(n) == 0 || len(e) == 0 {
+		return jwk{}, false
+	}
+	key := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}
+	return jwk{kid: k.Kid, alg: jwt.SigningMethodRS256.Alg(), key: key}, true
+}
@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"alg": "RS256",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	first, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	second, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var fetches int32
+	var published atomic.Value
+	published.Store([]map[string]string{rsaJWK("first", &first.PublicKey)})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": published.Load()})
+	}))
+	defer server.Close()
+
+	jwks := NewJWKS(server.URL, server.Client(), time.Hour)
+	jwks.MinRefreshInterval = 0
+	authn := NewJWTAuthenticator(jwks, JWTOptions{Issuer: "https://issuer.test", Audience: "books"})
+
+	t.Run("Known key", func(t *testing.T) {
+		p, err := authn.Authenticate(bearer(sign(t, jwt.SigningMethodRS256, first, validClaims(), "first")))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-1", p.Subject)
+	})
+
+	t.Run("Cached", func(t *testing.T) {
+		before := atomic.LoadInt32(&fetches)
+
+		_, err := authn.Authenticate(bearer(sign(t, jwt.SigningMethodRS256, first, validClaims(), "first")))
+
+		assert.NoError(t, err)
+		assert.Equal(t, before, atomic.LoadInt32(&fetches))
+	})
+
+	t.Run("Rotated key", func(t *testing.T) {
+		published.Store([]map[string]string{rsaJWK("first", &first.PublicKey), rsaJWK("second", &second.PublicKey)})
+
+		p, err := authn.Authenticate(bearer(sign(t, jwt.SigningMethodRS256, second, validClaims(), "second")))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-1", p.Subject)
+	})
+
+	t.Run("Unknown key", func(t *testing.T) {
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		p, err := authn.Authenticate(bearer(sign(t, jwt.SigningMethodRS256, other, validClaims(), "other")))
+
+		assert.Nil(t, p)
+		assert.Equal(t, http.StatusUnauthorized, apperror.Status(err))
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer down.Close()
+
+		authn := NewJWTAuthenticator(NewJWKS(down.URL, down.Client(), time.Hour), JWTOptions{})
+
+		p, err := authn.Authenticate(bearer(sign(t, jwt.SigningMethodRS256, first, validClaims(), "first")))
+
+		assert.Nil(t, p)
+		assert.Equal(t, http.StatusServiceUnavailable, apperror.Status(err))
+	})
+}
+
+func TestJWKS_Unavailable(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var fetches int32
+	var down atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{rsaJWK("first", &key.PublicKey)}})
+	}))
+	defer server.Close()
+
+	t.Run("Cached keys are kept", func(t *testing.T) {
+		atomic.StoreInt32(&fetches, 0)
+		down.Store(false)
+		// stale on every use
+		jwks := NewJWKS(server.URL, server.Client(), 0)
+		jwks.MinRefreshInterval = 0
+		authn := NewJWTAuthenticator(jwks, JWTOptions{})
+
+		_, err := authn.Authenticate(bearer(sign(t, jwt.SigningMethodRS256, key, validClaims(), "first")))
+		require.NoError(t, err)
+
+		down.Store(true)
+		p, err := authn.Authenticate(bearer(sign(t, jwt.SigningMethodRS256, key, validClaims(), "first")))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-1", p.Subject)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+
+		jwks.MinRefreshInterval = time.Hour
+		_, err = authn.Authenticate(bearer(sign(t, jwt.SigningMethodRS256, key, validClaims(), "first")))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&fetches), "failed fetch retried within MinRefreshInterval")
+	})
+
+	t.Run("Failed fetch is throttled", func(t *testing.T) {
+		atomic.StoreInt32(&fetches, 0)
+		down.Store(true)
+		authn := NewJWTAuthenticator(NewJWKS(server.URL, server.Client(), time.Hour), JWTOptions{})
+
+		for i := 0; i < 2; i++ {
+			p, err := authn.Authenticate(bearer(sign(t, jwt.SigningMethodRS256, key, validClaims(), "first")))
+
+			assert.Nil(t, p)
+			assert.Equal(t, http.StatusServiceUnavailable, apperror.Status(err))
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	})
+}
+
+func TestParseJWK(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		key  jwkJSON
+		ok   bool
+	}{
+		{name: "RSA", key: jwkJSON{Kty: "RSA", Alg: "RS256", N: rsaJWK("", &key.PublicKey)["n"], E: rsaJWK("", &key.PublicKey)["e"]}, ok: true},
+		{name: "RSA for another algorithm", key: jwkJSON{Kty: "RSA", Alg: "RS512", N: rsaJWK("", &key.PublicKey)["n"], E: rsaJWK("", &key.PublicKey)["e"]}},
+		{name: "Symmetric", key: jwkJSON{Kty: "oct", Alg: "HS256"}},
+		{name: "Malformed", key: jwkJSON{Kty: "RSA", N: "!", E: "AQAB"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := parseJWK(tt.key)
+
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+// JWTOptions holds the claims every token must satisfy
+type JWTOptions struct {
+	Issuer   string        // required iss, when set
+	Audience string        // required aud, when set
+	Leeway   time.Duration // clock skew allowed on exp, nbf and iat
+}
+
+// JWTAuthenticator verifies HS256 and RS256 bearer tokens
+type JWTAuthenticator struct {
+	keys   KeySource
+	parser *jwt.Parser
+}
+
+func NewJWTAuthenticator(keys KeySource, opts JWTOptions) *JWTAuthenticator {
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(opts.Leeway),
+	}
+	if opts.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(opts.Issuer))
+	}
+	if opts.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(opts.Audience))
+	}
+
+	return &JWTAuthenticator{
+		keys:   keys,
+		parser: jwt.NewParser(parserOpts...),
+	}
+}
+
+// Authenticate returns the principal of the bearer token sent with r,
+// or nil when r carries no bearer token
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*domain.Principal, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, nil
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(strings.TrimSpace(token), claims, func(t *jwt.Token) (interface{}, error) {
+		return a.keys.Key(r.Context(), t)
+	})
+	if errors.Is(err, ErrJWKSUnavailable) {
+		return nil, apperror.NewServiceUnavailable().WithCause(err)
+	}
+	if err != nil {
+		// the reason is for the logs, it would help forge a token
+		slog.InfoContext(r.Context(), "bearer token rejected", "error", err)
+		return nil, apperror.NewAuthorization("invalid bearer token")
+	}
+
+	sub, _ := claims.GetSubject()
+
+	return &domain.Principal{
+		Subject: sub,
+		Method:  domain.AuthMethodJWT,
+		Roles:   stringList(claims["roles"]),
+		Scopes:  scopes(claims),
+		Claims:  claims,
+	}, nil
+}
+
+// scopes reads the space separated scope claim, or the scp list
+func scopes(claims jwt.MapClaims) []string {
+	if s, ok := claims["scope"].(string); ok {
+		return strings.Fields(s)
+	}
+	return stringList(claims["scp"])
+}
+
+func stringList(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, s := range v {
+			if s, ok := s.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	default:
+		return nil
+	}
+}
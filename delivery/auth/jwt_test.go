@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var hmacSecret = []byte("test-secret")
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.MapClaims, kid string) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return s
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "user-1",
+		"iss":   "https://issuer.test",
+		"aud":   "books",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"editor"},
+		"scope": "books:read books:write",
+	}
+}
+
+func bearer(token string) *http.Request {
+	req, _ := http.NewRequest(http.MethodGet, "/books/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func writeRSAPublicKey(t *testing.T, key *rsa.PrivateKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "public.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	return path
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	secretPath := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secretPath, append(hmacSecret, '\n'), 0o600))
+
+	hmacKeys, err := LoadHMACKeyFile(secretPath)
+	require.NoError(t, err)
+	rsaKeys, err := LoadRSAPublicKeyFile(writeRSAPublicKey(t, rsaKey))
+	require.NoError(t, err)
+
+	authn := NewJWTAuthenticator(Keys{hmacKeys, rsaKeys}, JWTOptions{
+		Issuer:   "https://issuer.test",
+		Audience: "books",
+	})
+
+	t.Run("HS256", func(t *testing.T) {
+		p, err := authn.Authenticate(bearer(sign(t, jwt.SigningMethodHS256, hmacSecret, validClaims(), "")))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-1", p.Subject)
+		assert.Equal(t, []string{"editor"}, p.Roles)
+		assert.Equal(t, []string{"books:read", "books:write"}, p.Scopes)
+		assert.Equal(t, "https://issuer.test", p.Claims["iss"])
+	})
+
+	t.Run("RS256", func(t *testing.T) {
+		p, err := authn.Authenticate(bearer(sign(t, jwt.SigningMethodRS256, rsaKey, validClaims(), "")))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-1", p.Subject)
+	})
+
+	t.Run("No bearer token", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/books/", nil)
+		req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+
+		p, err := authn.Authenticate(req)
+
+		assert.NoError(t, err)
+		assert.Nil(t, p)
+	})
+
+	invalid := map[string]func() string{
+		"Expired": func() string {
+			claims := validClaims()
+			claims["exp"] = time.Now().Add(-time.Minute).Unix()
+			return sign(t, jwt.SigningMethodHS256, hmacSecret, claims, "")
+		},
+		"Without expiry": func() string {
+			claims := validClaims()
+			delete(claims, "exp")
+			return sign(t, jwt.SigningMethodHS256, hmacSecret, claims, "")
+		},
+		"Wrong issuer": func() string {
+			claims := validClaims()
+			claims["iss"] = "https://other.test"
+			return sign(t, jwt.SigningMethodHS256, hmacSecret, claims, "")
+		},
+		"Wrong audience": func() string {
+			claims := validClaims()
+			claims["aud"] = "other"
+			return sign(t, jwt.SigningMethodHS256, hmacSecret, claims, "")
+		},
+		"Wrong secret": func() string {
+			return sign(t, jwt.SigningMethodHS256, []byte("other"), validClaims(), "")
+		},
+		"Unsupported algorithm": func() string {
+			return sign(t, jwt.SigningMethodHS512, hmacSecret, validClaims(), "")
+		},
+		"Malformed": func() string {
+			return "not.a.token"
+		},
+	}
+
+	for name, token := range invalid {
+		t.Run(name, func(t *testing.T) {
+			p, err := authn.Authenticate(bearer(token()))
+
+			assert.Nil(t, p)
+			assert.Equal(t, http.StatusUnauthorized, apperror.Status(err))
+			// the reason is logged, not sent
+			assert.EqualError(t, err, apperror.NewAuthorization("invalid bearer token").Error())
+		})
+	}
+}
+
+func TestLoadKeyFiles(t *testing.T) {
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := LoadHMACKeyFile(filepath.Join(t.TempDir(), "missing"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Empty secret", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "secret")
+		require.NoError(t, os.WriteFile(path, []byte("\n"), 0o600))
+
+		_, err := LoadHMACKeyFile(path)
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid PEM", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "public.pem")
+		require.NoError(t, os.WriteFile(path, []byte("not a key"), 0o600))
+
+		_, err := LoadRSAPublicKeyFile(path)
+		assert.Error(t, err)
+	})
+
+	t.Run("Key for another algorithm", func(t *testing.T) {
+		_, err := NewHMACKey(hmacSecret).Key(context.Background(), jwt.New(jwt.SigningMethodRS256))
+		assert.True(t, errors.Is(err, ErrNoKey))
+	})
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrNoKey is returned by a KeySource which has no key for a token
+var ErrNoKey = errors.New("no key for token")
+
+// KeySource provides the keys verifying tokens
+type KeySource interface {
+	// Key returns the key verifying token, or ErrNoKey when the source
+	// has no key for the algorithm and key ID of token
+	Key(ctx context.Context, token *jwt.Token) (interface{}, error)
+}
+
+// staticKey holds a single key for a single algorithm
+type staticKey struct {
+	alg string
+	key interface{}
+}
+
+func (k *staticKey) Key(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	if token.Method.Alg() != k.alg {
+		return nil, ErrNoKey
+	}
+	return k.key, nil
+}
+
+// NewHMACKey verifies HS256 tokens with secret
+func NewHMACKey(secret []byte) KeySource {
+	return &staticKey{alg: jwt.SigningMethodHS256.Alg(), key: secret}
+}
+
+// LoadHMACKeyFile verifies HS256 tokens with the secret stored in path
+func LoadHMACKeyFile(path string) (KeySource, error) {
+	secret, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read HMAC secret: %w", err)
+	}
+
+	secret = bytes.TrimSpace(secret)
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("HMAC secret %s is empty", path)
+	}
+
+	return NewHMACKey(secret), nil
+}
+
+// LoadRSAPublicKeyFile verifies RS256 tokens with the PEM encoded
+// public key stored in path
+func LoadRSAPublicKeyFile(path string) (KeySource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read RSA public key: %w", err)
+	}
+
+	key, err := jwt.ParseRSAPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse RSA public key %s: %w", path, err)
+	}
+
+	return &staticKey{alg: jwt.SigningMethodRS256.Alg(), key: key}, nil
+}
+
+// Keys combines sources, the first one with a key for the token wins
+type Keys []KeySource
+
+func (ks Keys) Key(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	for _, s := range ks {
+		key, err := s.Key(ctx, token)
+		if errors.Is(err, ErrNoKey) {
+			continue
+		}
+		return key, err
+	}
+
+	return nil, ErrNoKey
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/middleware"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+// APIKeysPath is where the API key admin routes are mounted
+const APIKeysPath = "/admin/api-keys"
+
+type APIKeyHandler struct {
+	APIKeyUseCase domain.APIKeyUseCase
+}
+
+// issueAPIKeyRequest is the body of POST /admin/api-keys
+type issueAPIKeyRequest struct {
+	Name   string   `binding:"required" json:"name"`
+	Scopes []string `binding:"required,min=1,dive,oneof=books:read books:write books:delete" json:"scopes"`
+}
+
+// NewAPIKeyHandler registers the API key admin routes, mw should
+// restrict them to administrators
+func NewAPIKeyHandler(router *gin.Engine, au domain.APIKeyUseCase, timeout time.Duration, mw ...gin.HandlerFunc) *APIKeyHandler {
+	handler := &APIKeyHandler{
+		APIKeyUseCase: au,
+	}
+
+	g := router.Group(APIKeysPath)
+	g.Use(middleware.Timeout(timeout, apperror.NewServiceUnavailable()))
+	g.Use(mw...)
+	g.GET("/", handler.FetchAPIKeys)
+	g.POST("/", handler.IssueAPIKey)
+	g.DELETE("/:id", handler.RevokeAPIKey)
+
+	return handler
+}
+
+func (h *APIKeyHandler) FetchAPIKeys(c *gin.Context) {
+	keys, err := h.APIKeyUseCase.FetchAPIKeys(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, keys)
+}
+
+func (h *APIKeyHandler) IssueAPIKey(c *gin.Context) {
+	var req issueAPIKeyRequest
+	if ok := bindData(c, &req); !ok {
+		return
+	}
+
+	key, err := h.APIKeyUseCase.IssueAPIKey(c.Request.Context(), req.Name, req.Scopes)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, key)
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.APIKeyUseCase.RevokeAPIKey(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil)
+}
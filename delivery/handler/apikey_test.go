@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/delivery/middleware"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAPIKeyHandler(t *testing.T) {
+	// setup
+	gin.SetMode(gin.TestMode)
+
+	id := uuid.New()
+	apiKey := domain.APIKey{
+		ID:        id,
+		Name:      "import",
+		Prefix:    "bk_abcdef",
+		Scopes:    []string{domain.ScopeBooksRead},
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		setup  func(m *appmock.MockAPIKeyUseCase)
+		code   int
+	}{
+		{
+			name:   "FetchAPIKeys - Success",
+			method: "GET",
+			path:   "/admin/api-keys/",
+			setup: func(m *appmock.MockAPIKeyUseCase) {
+				m.On("FetchAPIKeys", mock.Anything).Return(&[]domain.APIKey{apiKey}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "IssueAPIKey - Success",
+			method: "POST",
+			path:   "/admin/api-keys/",
+			body:   `{"name":"import","scopes":["books:read"]}`,
+			setup: func(m *appmock.MockAPIKeyUseCase) {
+				m.On("IssueAPIKey", mock.Anything, "import", []string{domain.ScopeBooksRead}).Return(&domain.IssuedAPIKey{APIKey: apiKey, Key: "bk_abcdefsecret"}, nil)
+			},
+			code: http.StatusCreated,
+		},
+		{
+			name:   "IssueAPIKey - Unknown scope",
+			method: "POST",
+			path:   "/admin/api-keys/",
+			body:   `{"name":"import","scopes":["books:everything"]}`,
+			setup:  func(m *appmock.MockAPIKeyUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "RevokeAPIKey - Success",
+			method: "DELETE",
+			path:   "/admin/api-keys/" + id.String(),
+			setup: func(m *appmock.MockAPIKeyUseCase) {
+				m.On("RevokeAPIKey", mock.Anything, id.String()).Return(nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "RevokeAPIKey - Not found",
+			method: "DELETE",
+			path:   "/admin/api-keys/" + id.String(),
+			setup: func(m *appmock.MockAPIKeyUseCase) {
+				m.On("RevokeAPIKey", mock.Anything, id.String()).Return(apperror.NewNotFound("APIKey", "ID", id.String()))
+			},
+			code: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPIKeyUseCase := new(appmock.MockAPIKeyUseCase)
+			tt.setup(mockAPIKeyUseCase)
+
+			// validated against the OpenAPI document like the book routes
+			router := gin.New()
+			validator := middleware.OpenAPIValidator(NewOpenAPIDocument("/books"), middleware.OpenAPIOptions{ValidateResponses: true})
+			NewAPIKeyHandler(router, mockAPIKeyUseCase, time.Second, validator)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code, w.Body.String())
+			mockAPIKeyUseCase.AssertExpectations(t)
+		})
+	}
+
+	t.Run("IssueAPIKey - The key is returned once", func(t *testing.T) {
+		mockAPIKeyUseCase := new(appmock.MockAPIKeyUseCase)
+		mockAPIKeyUseCase.On("IssueAPIKey", mock.Anything, "import", []string{domain.ScopeBooksRead}).Return(&domain.IssuedAPIKey{APIKey: apiKey, Key: "bk_abcdefsecret"}, nil)
+		mockAPIKeyUseCase.On("FetchAPIKeys", mock.Anything).Return(&[]domain.APIKey{apiKey}, nil)
+
+		router := gin.New()
+		NewAPIKeyHandler(router, mockAPIKeyUseCase, time.Second)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/admin/api-keys/", bytes.NewBufferString(`{"name":"import","scopes":["books:read"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		var issued struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &issued)
+		assert.Equal(t, "bk_abcdefsecret", issued.Data["key"])
+		assert.NotContains(t, issued.Data, "Hash")
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/admin/api-keys/", nil)
+		router.ServeHTTP(w, req)
+
+		assert.NotContains(t, w.Body.String(), "bk_abcdefsecret")
+	})
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/delivery/middleware"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+// AuditPath is where the audit log is served
+const AuditPath = "/audit"
+
+type AuditHandler struct {
+	AuditUseCase domain.AuditUseCase
+}
+
+// NewAuditHandler registers the audit log route, mw should authenticate
+// the callers
+func NewAuditHandler(router *gin.Engine, au domain.AuditUseCase, timeout time.Duration, mw ...gin.HandlerFunc) *AuditHandler {
+	handler := &AuditHandler{
+		AuditUseCase: au,
+	}
+
+	g := router.Group(AuditPath)
+	g.Use(middleware.Timeout(timeout, apperror.NewServiceUnavailable()))
+	g.Use(mw...)
+	g.GET("/", handler.FetchAuditEntries)
+
+	return handler
+}
+
+func (h *AuditHandler) FetchAuditEntries(c *gin.Context) {
+	filter, err := auditFilter(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	entries, err := h.AuditUseCase.FetchAuditEntries(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, entries)
+}
+
+// auditFilter reads the book_id, actor, from and to query params of
+// GET /audit, from and to are RFC 3339 times
+func auditFilter(c *gin.Context) (domain.AuditFilter, error) {
+	filter := domain.AuditFilter{
+		BookID: c.Query("book_id"),
+		Actor:  c.Query("actor"),
+	}
+
+	var fields []apperror.FieldError
+	invalid := func(name string, value string, format string) {
+		fields = append(fields, apperror.NewFieldError("query", name, "/"+name, "format", value, apperror.Params{"param": format}))
+	}
+
+	if filter.BookID != "" {
+		if _, err := uuid.Parse(filter.BookID); err != nil {
+			invalid("book_id", filter.BookID, "uuid")
+		}
+	}
+	for _, p := range []struct {
+		name string
+		t    *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		v := c.Query(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			invalid(p.name, v, "date-time")
+			continue
+		}
+		*p.t = t
+	}
+
+	if len(fields) > 0 {
+		return filter, apperror.NewValidation(fields)
+	}
+	return filter, nil
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/delivery/middleware"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuditHandler(t *testing.T) {
+	// setup
+	gin.SetMode(gin.TestMode)
+
+	bookID := uuid.New()
+	entry := domain.AuditEntry{
+		ID:         uuid.New(),
+		Time:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Action:     domain.AuditActionUpdate,
+		BookID:     bookID,
+		Actor:      "alice",
+		AuthMethod: domain.AuthMethodJWT,
+		RequestID:  "req-1",
+		Changes:    []domain.FieldChange{{Field: "title", Before: "Go", After: "Go 2"}},
+	}
+
+	tests := []struct {
+		name     string
+		validate bool
+		query    string
+		setup    func(m *appmock.MockAuditUseCase)
+		code     int
+	}{
+		{
+			name:     "Success",
+			validate: true,
+			setup: func(m *appmock.MockAuditUseCase) {
+				m.On("FetchAuditEntries", mock.Anything, domain.AuditFilter{}).Return(&[]domain.AuditEntry{entry}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:     "Filtered",
+			validate: true,
+			query:    "?book_id=" + bookID.String() + "&actor=alice&from=2024-01-01T00:00:00Z&to=2024-01-03T07:00:00%2B07:00",
+			setup: func(m *appmock.MockAuditUseCase) {
+				m.On("FetchAuditEntries", mock.Anything, mock.MatchedBy(func(f domain.AuditFilter) bool {
+					return f.BookID == bookID.String() && f.Actor == "alice" &&
+						f.From.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) &&
+						f.To.Equal(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC))
+				})).Return(&[]domain.AuditEntry{entry}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:  "Invalid time",
+			query: "?from=yesterday",
+			setup: func(m *appmock.MockAuditUseCase) {},
+			code:  http.StatusBadRequest,
+		},
+		{
+			name:  "Invalid book ID",
+			query: "?book_id=1",
+			setup: func(m *appmock.MockAuditUseCase) {},
+			code:  http.StatusBadRequest,
+		},
+		{
+			name: "Forbidden",
+			setup: func(m *appmock.MockAuditUseCase) {
+				m.On("FetchAuditEntries", mock.Anything, domain.AuditFilter{}).Return((*[]domain.AuditEntry)(nil), apperror.NewForbidden("the audit:read permission is required"))
+			},
+			code: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuditUseCase := new(appmock.MockAuditUseCase)
+			tt.setup(mockAuditUseCase)
+
+			router := gin.New()
+			var mw []gin.HandlerFunc
+			if tt.validate {
+				mw = append(mw, middleware.OpenAPIValidator(NewOpenAPIDocument("/books"), middleware.OpenAPIOptions{ValidateResponses: true}))
+			}
+			NewAuditHandler(router, mockAuditUseCase, time.Second, mw...)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/audit/"+tt.query, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code, w.Body.String())
+			mockAuditUseCase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain"
+)
+
+// AuthorsPath is where the authors are served
+const AuthorsPath = "/authors"
+
+type AuthorHandler struct {
+	AuthorUseCase domain.AuthorUseCase
+	RouteMiddleware
+}
+
+// NewAuthorHandler registers the author routes, with the read and write
+// middleware of opts as for the books
+func NewAuthorHandler(router *gin.Engine, au domain.AuthorUseCase, timeout time.Duration, opts ...Option) *AuthorHandler {
+	handler := &AuthorHandler{
+		AuthorUseCase:   au,
+		RouteMiddleware: newRouteMiddleware(opts),
+	}
+
+	read, write := handler.groups(router, AuthorsPath, timeout)
+	// setup routes
+	read.GET("/", handler.FetchAuthors)
+	write.POST("/", handler.CreateAuthor)
+	write.POST("/migrate", handler.MigrateAuthors)
+	read.GET("/:id", handler.GetAuthorByID)
+	write.PUT("/:id", handler.UpdateAuthor)
+	write.DELETE("/:id", handler.DeleteAuthor)
+	read.GET("/:id/books", handler.FetchAuthorBooks)
+
+	return handler
+}
+
+// FetchAuthors returns the authors by name, those whose name contains
+// the name query param when given
+func (h *AuthorHandler) FetchAuthors(c *gin.Context) {
+	authors, err := h.AuthorUseCase.FetchAuthors(c.Request.Context(), domain.AuthorFilter{Name: c.Query("name")})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, authors)
+}
+
+func (h *AuthorHandler) CreateAuthor(c *gin.Context) {
+	var author domain.Author
+	if ok := bindData(c, &author); !ok {
+		return
+	}
+
+	err := h.AuthorUseCase.CreateAuthor(c.Request.Context(), &author)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, author)
+}
+
+func (h *AuthorHandler) GetAuthorByID(c *gin.Context) {
+	id := c.Param("id")
+
+	author, err := h.AuthorUseCase.GetAuthorByID(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, author)
+}
+
+func (h *AuthorHandler) UpdateAuthor(c *gin.Context) {
+	var author domain.Author
+	if ok := bindData(c, &author); !ok {
+		return
+	}
+
+	id := c.Param("id")
+	err := h.AuthorUseCase.UpdateAuthor(c.Request.Context(), id, &author)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, author)
+}
+
+func (h *AuthorHandler) DeleteAuthor(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.AuthorUseCase.DeleteAuthor(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil)
+}
+
+func (h *AuthorHandler) FetchAuthorBooks(c *gin.Context) {
+	id := c.Param("id")
+
+	books, err := h.AuthorUseCase.FetchAuthorBooks(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, books)
+}
+
+// MigrateAuthors links the books without contributors to the authors
+// named by their author string
+func (h *AuthorHandler) MigrateAuthors(c *gin.Context) {
+	migration, err := h.AuthorUseCase.MigrateAuthors(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, migration)
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/delivery/middleware"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthorHandler(t *testing.T) {
+	// setup
+	gin.SetMode(gin.TestMode)
+
+	author := domain.Author{ID: uuid.New(), Name: "Jane Austen", Bio: "English novelist"}
+	id := author.ID.String()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		setup  func(m *appmock.MockAuthorUseCase)
+		code   int
+		want   string // a fragment of the response body
+	}{
+		{
+			name:   "Fetch",
+			method: http.MethodGet,
+			path:   "/authors/?name=austen",
+			setup: func(m *appmock.MockAuthorUseCase) {
+				m.On("FetchAuthors", mock.Anything, domain.AuthorFilter{Name: "austen"}).Return(&[]domain.Author{author}, nil)
+			},
+			want: `"data":[{"id":"` + id + `","name":"Jane Austen"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			path:   "/authors/",
+			body:   `{"name":"Jane Austen","bio":"English novelist"}`,
+			setup: func(m *appmock.MockAuthorUseCase) {
+				m.On("CreateAuthor", mock.Anything, mock.AnythingOfType("*domain.Author")).Return(nil)
+			},
+			want: `"name":"Jane Austen","bio":"English novelist"`,
+			code: http.StatusCreated,
+		},
+		{
+			name:   "Create without a name",
+			method: http.MethodPost,
+			path:   "/authors/",
+			body:   `{"bio":"English novelist"}`,
+			setup:  func(m *appmock.MockAuthorUseCase) {},
+			want:   `"error_code":"invalid_fields"`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "Create a duplicate",
+			method: http.MethodPost,
+			path:   "/authors/",
+			body:   `{"name":"jane austen"}`,
+			setup: func(m *appmock.MockAuthorUseCase) {
+				m.On("CreateAuthor", mock.Anything, mock.AnythingOfType("*domain.Author")).Return(apperror.NewConflict("author", "Jane Austen"))
+			},
+			want: `"error_code":"conflict"`,
+			code: http.StatusConflict,
+		},
+		{
+			name:   "Get",
+			method: http.MethodGet,
+			path:   "/authors/" + id,
+			setup: func(m *appmock.MockAuthorUseCase) {
+				m.On("GetAuthorByID", mock.Anything, id).Return(&author, nil)
+			},
+			want: `"data":{"id":"` + id + `","name":"Jane Austen"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Get unknown",
+			method: http.MethodGet,
+			path:   "/authors/" + id,
+			setup: func(m *appmock.MockAuthorUseCase) {
+				m.On("GetAuthorByID", mock.Anything, id).Return((*domain.Author)(nil), apperror.NewNotFound("Author", "ID", id))
+			},
+			want: `"error_code":"not_found"`,
+			code: http.StatusNotFound,
+		},
+		{
+			name:   "Update",
+			method: http.MethodPut,
+			path:   "/authors/" + id,
+			body:   `{"name":"Jane Austen"}`,
+			setup: func(m *appmock.MockAuthorUseCase) {
+				m.On("UpdateAuthor", mock.Anything, id, mock.AnythingOfType("*domain.Author")).Return(nil)
+			},
+			want: `"name":"Jane Austen"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Delete linked to books",
+			method: http.MethodDelete,
+			path:   "/authors/" + id,
+			setup: func(m *appmock.MockAuthorUseCase) {
+				m.On("DeleteAuthor", mock.Anything, id).Return(apperror.NewConflictCode("books_of_author", apperror.Params{"author": "Jane Austen"}))
+			},
+			want: `"error_code":"books_of_author"`,
+			code: http.StatusConflict,
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			path:   "/authors/" + id,
+			setup: func(m *appmock.MockAuthorUseCase) {
+				m.On("DeleteAuthor", mock.Anything, id).Return(nil)
+			},
+			want: `"data":null`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Books",
+			method: http.MethodGet,
+			path:   "/authors/" + id + "/books",
+			setup: func(m *appmock.MockAuthorUseCase) {
+				books := []domain.Book{{
+					ID:              uuid.New(),
+					Title:           "Emma",
+					Author:          "Jane Austen",
+					PublicationDate: "1815-12-23",
+					Contributors:    []domain.Contributor{{AuthorID: author.ID, Role: domain.ContributorAuthor}},
+				}}
+				m.On("FetchAuthorBooks", mock.Anything, id).Return(&books, nil)
+			},
+			want: `"title":"Emma"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Migrate",
+			method: http.MethodPost,
+			path:   "/authors/migrate",
+			setup: func(m *appmock.MockAuthorUseCase) {
+				m.On("MigrateAuthors", mock.Anything).Return(&domain.AuthorMigration{
+					Books:   2,
+					Authors: 3,
+					Failed:  []domain.AuthorMigrationFailure{{BookID: uuid.New(), Error: "invalid book"}},
+				}, nil)
+			},
+			want: `"data":{"books":2,"authors":3`,
+			code: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthorUseCase := new(appmock.MockAuthorUseCase)
+			tt.setup(mockAuthorUseCase)
+
+			router := gin.New()
+			validator := middleware.OpenAPIValidator(NewOpenAPIDocument("/books"), middleware.OpenAPIOptions{ValidateResponses: true})
+			NewAuthorHandler(router, mockAuthorUseCase, time.Second, WithMiddleware(validator))
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code, w.Body.String())
+			assert.Contains(t, w.Body.String(), tt.want)
+			mockAuthorUseCase.AssertExpectations(t)
+		})
+	}
+}
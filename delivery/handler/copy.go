@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+// CopiesPath is where the copies are looked up by barcode, they are
+// otherwise served under the book they are copies of
+const CopiesPath = "/copies"
+
+type CopyHandler struct {
+	CopyUseCase domain.CopyUseCase
+	RouteMiddleware
+}
+
+// NewCopyHandler registers the copy routes under the book routes at
+// booksPath, and the barcode lookup at CopiesPath, with the read and
+// write middleware of opts as for the books
+func NewCopyHandler(router *gin.Engine, cu domain.CopyUseCase, booksPath string, timeout time.Duration, opts ...Option) *CopyHandler {
+	handler := &CopyHandler{
+		CopyUseCase:     cu,
+		RouteMiddleware: newRouteMiddleware(opts),
+	}
+
+	read, write := handler.groups(router, booksPath+"/:id/copies", timeout)
+	// setup routes
+	read.GET("/", handler.FetchCopies)
+	write.POST("/", handler.CreateCopy)
+	read.GET("/:copy_id", handler.GetCopyByID)
+	write.PUT("/:copy_id", handler.UpdateCopy)
+	write.DELETE("/:copy_id", handler.DeleteCopy)
+
+	read, _ = handler.groups(router, CopiesPath, timeout)
+	read.GET("/barcode/:code", handler.GetCopyByBarcode)
+
+	return handler
+}
+
+// FetchCopies returns the copies of the book by barcode, those in the
+// status query param when given
+func (h *CopyHandler) FetchCopies(c *gin.Context) {
+	status := domain.CopyStatus(c.Query("status"))
+	if status != "" && !status.Valid() {
+		response.Error(c, apperror.NewValidation([]apperror.FieldError{
+			apperror.NewFieldError("query", "status", "/status", "oneof", string(status), apperror.Params{"param": "available on_loan lost repair"}),
+		}))
+		return
+	}
+
+	copies, err := h.CopyUseCase.FetchCopies(c.Request.Context(), c.Param("id"), domain.CopyFilter{Status: status})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, copies)
+}
+
+func (h *CopyHandler) CreateCopy(c *gin.Context) {
+	var cp domain.Copy
+	if ok := bindData(c, &cp); !ok {
+		return
+	}
+
+	err := h.CopyUseCase.CreateCopy(c.Request.Context(), c.Param("id"), &cp)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, cp)
+}
+
+func (h *CopyHandler) GetCopyByID(c *gin.Context) {
+	cp, err := h.CopyUseCase.GetCopyByID(c.Request.Context(), c.Param("id"), c.Param("copy_id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, cp)
+}
+
+// GetCopyByBarcode returns the copy with the barcode of the path,
+// whichever book it is a copy of
+func (h *CopyHandler) GetCopyByBarcode(c *gin.Context) {
+	cp, err := h.CopyUseCase.GetCopyByBarcode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, cp)
+}
+
+func (h *CopyHandler) UpdateCopy(c *gin.Context) {
+	var cp domain.Copy
+	if ok := bindData(c, &cp); !ok {
+		return
+	}
+
+	err := h.CopyUseCase.UpdateCopy(c.Request.Context(), c.Param("id"), c.Param("copy_id"), &cp)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, cp)
+}
+
+func (h *CopyHandler) DeleteCopy(c *gin.Context) {
+	err := h.CopyUseCase.DeleteCopy(c.Request.Context(), c.Param("id"), c.Param("copy_id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil)
+}
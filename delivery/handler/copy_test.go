@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/delivery/middleware"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCopyHandler(t *testing.T) {
+	// setup
+	gin.SetMode(gin.TestMode)
+
+	bookID := uuid.NewString()
+	cp := domain.Copy{ID: uuid.New(), BookID: uuid.MustParse(bookID), Barcode: "B001", Location: "2F-A12", AcquisitionDate: "2024-03-01", Status: domain.CopyAvailable}
+	id := cp.ID.String()
+	path := "/books/" + bookID + "/copies/"
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		setup  func(m *appmock.MockCopyUseCase)
+		code   int
+		want   string // a fragment of the response body
+	}{
+		{
+			name:   "Fetch",
+			method: http.MethodGet,
+			path:   path,
+			setup: func(m *appmock.MockCopyUseCase) {
+				m.On("FetchCopies", mock.Anything, bookID, domain.CopyFilter{}).Return(&[]domain.Copy{cp}, nil)
+			},
+			want: `"data":[{"id":"` + id + `","book_id":"` + bookID + `","barcode":"B001"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Fetch by status",
+			method: http.MethodGet,
+			path:   path + "?status=on_loan",
+			setup: func(m *appmock.MockCopyUseCase) {
+				m.On("FetchCopies", mock.Anything, bookID, domain.CopyFilter{Status: domain.CopyOnLoan}).Return(&[]domain.Copy{}, nil)
+			},
+			want: `"data":[]`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Fetch by unknown status",
+			method: http.MethodGet,
+			path:   path + "?status=borrowed",
+			setup:  func(m *appmock.MockCopyUseCase) {},
+			want:   `"error_code":"invalid_fields"`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "Fetch of unknown book",
+			method: http.MethodGet,
+			path:   path,
+			setup: func(m *appmock.MockCopyUseCase) {
+				m.On("FetchCopies", mock.Anything, bookID, domain.CopyFilter{}).Return((*[]domain.Copy)(nil), apperror.NewNotFound("Book", "ID", bookID))
+			},
+			want: `"error_code":"not_found"`,
+			code: http.StatusNotFound,
+		},
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			path:   path,
+			body:   `{"barcode":"B001","location":"2F-A12","condition":"good","acquisition_date":"2024-03-01"}`,
+			setup: func(m *appmock.MockCopyUseCase) {
+				m.On("CreateCopy", mock.Anything, bookID, mock.AnythingOfType("*domain.Copy")).Return(nil).Run(func(args mock.Arguments) {
+					c := args.Get(2).(*domain.Copy)
+					c.ID, c.BookID, c.Status = cp.ID, cp.BookID, domain.CopyAvailable
+				})
+			},
+			want: `"data":{"id":"` + id + `","book_id":"` + bookID + `","barcode":"B001"`,
+			code: http.StatusCreated,
+		},
+		{
+			name:   "Create without a barcode",
+			method: http.MethodPost,
+			path:   path,
+			body:   `{"location":"2F-A12"}`,
+			setup:  func(m *appmock.MockCopyUseCase) {},
+			want:   `"error_code":"invalid_fields"`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "Create with an unknown status",
+			method: http.MethodPost,
+			path:   path,
+			body:   `{"barcode":"B001","status":"borrowed"}`,
+			setup:  func(m *appmock.MockCopyUseCase) {},
+			want:   `"error_code":"invalid_fields"`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "Create with a malformed acquisition date",
+			method: http.MethodPost,
+			path:   path,
+			body:   `{"barcode":"B001","acquisition_date":"01/03/2024"}`,
+			setup:  func(m *appmock.MockCopyUseCase) {},
+			want:   `"error_code":"invalid_fields"`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "Create a duplicate barcode",
+			method: http.MethodPost,
+			path:   path,
+			body:   `{"barcode":"b001"}`,
+			setup: func(m *appmock.MockCopyUseCase) {
+				m.On("CreateCopy", mock.Anything, bookID, mock.AnythingOfType("*domain.Copy")).Return(apperror.NewConflictCode("duplicate_barcode", apperror.Params{"barcode": `"B001"`}))
+			},
+			want: `"error_code":"duplicate_barcode"`,
+			code: http.StatusConflict,
+		},
+		{
+			name:   "Get",
+			method: http.MethodGet,
+			path:   path + id,
+			setup: func(m *appmock.MockCopyUseCase) {
+				m.On("GetCopyByID", mock.Anything, bookID, id).Return(&cp, nil)
+			},
+			want: `"data":{"id":"` + id + `","book_id":"` + bookID + `","barcode":"B001"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Get by barcode",
+			method: http.MethodGet,
+			path:   "/copies/barcode/B001",
+			setup: func(m *appmock.MockCopyUseCase) {
+				m.On("GetCopyByBarcode", mock.Anything, "B001").Return(&cp, nil)
+			},
+			want: `"barcode":"B001","location":"2F-A12"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Get by unknown barcode",
+			method: http.MethodGet,
+			path:   "/copies/barcode/B404",
+			setup: func(m *appmock.MockCopyUseCase) {
+				m.On("GetCopyByBarcode", mock.Anything, "B404").Return((*domain.Copy)(nil), apperror.NewNotFound("Copy", "barcode", "B404"))
+			},
+			want: `"error_code":"not_found"`,
+			code: http.StatusNotFound,
+		},
+		{
+			name:   "Update",
+			method: http.MethodPut,
+			path:   path + id,
+			body:   `{"barcode":"B001","status":"on_loan"}`,
+			setup: func(m *appmock.MockCopyUseCase) {
+				m.On("UpdateCopy", mock.Anything, bookID, id, mock.MatchedBy(func(c *domain.Copy) bool {
+					return c.Status == domain.CopyOnLoan
+				})).Return(nil)
+			},
+			want: `"status":"on_loan"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			path:   path + id,
+			setup: func(m *appmock.MockCopyUseCase) {
+				m.On("DeleteCopy", mock.Anything, bookID, id).Return(nil)
+			},
+			want: `"data":null`,
+			code: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCopyUseCase := new(appmock.MockCopyUseCase)
+			tt.setup(mockCopyUseCase)
+
+			router := gin.New()
+			validator := middleware.OpenAPIValidator(NewOpenAPIDocument("/books"), middleware.OpenAPIOptions{ValidateResponses: true})
+			NewCopyHandler(router, mockCopyUseCase, "/books", time.Second, WithMiddleware(validator))
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code, w.Body.String())
+			assert.Contains(t, w.Body.String(), tt.want)
+			mockCopyUseCase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/openapi"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain/apperror"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+const (
+	specPath = "/openapi.json"
+	docsPath = "/docs"
+)
+
+// swaggerInitializer replaces the petstore initializer bundled with swagger-ui
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "` + specPath + `",
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+type DocsHandler struct {
+	Document *openapi.Document
+}
+
+// NewDocsHandler serves doc at /openapi.json and the bundled Swagger UI at /docs
+func NewDocsHandler(router *gin.Engine, doc *openapi.Document) *DocsHandler {
+	handler := &DocsHandler{
+		Document: doc,
+	}
+
+	router.GET(specPath, handler.Spec)
+	router.GET(docsPath+"/*filepath", handler.SwaggerUI)
+
+	return handler
+}
+
+func (h *DocsHandler) Spec(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Document)
+}
+
+func (h *DocsHandler) SwaggerUI(c *gin.Context) {
+	file := strings.TrimPrefix(c.Param("filepath"), "/")
+
+	switch file {
+	case "":
+		c.Redirect(http.StatusMovedPermanently, docsPath+"/index.html")
+	case "swagger-initializer.js":
+		c.Data(http.StatusOK, "application/javascript; charset=utf-8", []byte(swaggerInitializer))
+	default:
+		data, err := fs.ReadFile(swaggerFiles.FS, file)
+		if err != nil {
+			response.Error(c, apperror.NewNotFound("file", "path", file))
+			return
+		}
+
+		c.Data(http.StatusOK, mime.TypeByExtension(path.Ext(file)), data)
+	}
+}
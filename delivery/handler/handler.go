@@ -2,10 +2,13 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/krittawatcode/books/delivery/middleware"
+	"github.com/krittawatcode/books/delivery/response"
 	"github.com/krittawatcode/books/domain"
 	"github.com/krittawatcode/books/domain/apperror"
 )
@@ -15,38 +18,106 @@ type BookHandler struct {
 	BookUseCase     domain.BookUseCase
 	Path            string // path for book routes
 	TimeoutDuration time.Duration
+	RouteMiddleware
 }
 
-func NewBookHandler(router *gin.Engine, bu domain.BookUseCase, path string, timeout time.Duration) *BookHandler {
+// RouteMiddleware is the middleware a catalog handler runs on its routes
+type RouteMiddleware struct {
+	Middleware      []gin.HandlerFunc // run on every route after the timeout
+	ReadMiddleware  []gin.HandlerFunc // run on the read routes before Middleware
+	WriteMiddleware []gin.HandlerFunc // run on the write routes before Middleware
+}
+
+// Option configures optional parts of the catalog handlers, the books
+// and the entities books refer to
+type Option func(*RouteMiddleware)
+
+// WithMiddleware adds middleware to every catalog route
+func WithMiddleware(mw ...gin.HandlerFunc) Option {
+	return func(m *RouteMiddleware) {
+		m.Middleware = append(m.Middleware, mw...)
+	}
+}
+
+// WithReadMiddleware adds middleware to the routes reading the catalog,
+// eg. middleware.OptionalAuth
+func WithReadMiddleware(mw ...gin.HandlerFunc) Option {
+	return func(m *RouteMiddleware) {
+		m.ReadMiddleware = append(m.ReadMiddleware, mw...)
+	}
+}
+
+// WithWriteMiddleware adds middleware to the routes changing the
+// catalog, eg. middleware.RequireAuth
+func WithWriteMiddleware(mw ...gin.HandlerFunc) Option {
+	return func(m *RouteMiddleware) {
+		m.WriteMiddleware = append(m.WriteMiddleware, mw...)
+	}
+}
+
+func newRouteMiddleware(opts []Option) RouteMiddleware {
+	var m RouteMiddleware
+	for _, opt := range opts {
+		opt(&m)
+	}
+	return m
+}
+
+// groups mounts the read and write route groups at path
+func (m RouteMiddleware) groups(router *gin.Engine, path string, timeout time.Duration) (read *gin.RouterGroup, write *gin.RouterGroup) {
+	g := router.Group(path)
+	// setup middleware
+	g.Use(middleware.Timeout(timeout, apperror.NewServiceUnavailable()))
+	// authenticate before validating, so anonymous callers learn nothing
+	read = g.Group("", m.ReadMiddleware...)
+	read.Use(m.Middleware...)
+	write = g.Group("", m.WriteMiddleware...)
+	write.Use(m.Middleware...)
+
+	return read, write
+}
+
+func NewBookHandler(router *gin.Engine, bu domain.BookUseCase, path string, timeout time.Duration, opts ...Option) *BookHandler {
 	handler := &BookHandler{
 		Router:          router,
 		BookUseCase:     bu,
 		Path:            path,
 		TimeoutDuration: timeout,
+		RouteMiddleware: newRouteMiddleware(opts),
 	}
 
-	// Create an books group
-	g := router.Group(path)
-	// setup middleware
-	g.Use(middleware.Timeout(timeout, apperror.NewServiceUnavailable()))
+	read, write := handler.groups(router, path, timeout)
 	// setup routes
-	g.GET("/", handler.FetchBooks)
-	g.POST("/", handler.CreateBook)
-	g.GET("/:id", handler.GetBookByID)
-	g.PUT("/:id", handler.UpdateBook)
-	g.DELETE("/:id", handler.DeleteBook)
+	read.GET("/", handler.FetchBooks)
+	write.POST("/", handler.CreateBook)
+	write.GET("/trash", handler.FetchTrash)
+	read.GET("/isbn/:isbn", handler.GetBookByISBN)
+	read.GET("/:id", handler.GetBookByID)
+	write.PUT("/:id", handler.UpdateBook)
+	write.DELETE("/:id", handler.DeleteBook)
+	read.GET("/:id/history", handler.FetchBookHistory)
+	write.POST("/:id/revert/:revision", handler.RevertBook)
+	write.POST("/:id/restore", handler.RestoreBook)
 
 	return handler
 }
 
+// FetchBooks returns the books matching every filter of the query,
+// see bookFilter
 func (h *BookHandler) FetchBooks(c *gin.Context) {
-	books, err := h.BookUseCase.FetchBooks(c.Request.Context())
+	filter, err := bookFilter(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	books, err := h.BookUseCase.FetchBooks(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(apperror.Status(err), errorResponse{response: response{Status: statusFail, Code: codeFail}, Error: err.Error()})
+		response.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, successResponse{response: response{Status: statusSuccess, Code: codeSuccess}, Data: books})
+	response.Success(c, http.StatusOK, books)
 }
 
 func (h *BookHandler) CreateBook(c *gin.Context) {
@@ -57,23 +128,60 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 
 	err := h.BookUseCase.CreateBook(c.Request.Context(), &book)
 	if err != nil {
-		c.JSON(apperror.Status(err), errorResponse{response: response{Status: statusFail, Code: codeFail}, Error: err.Error()})
+		response.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, successResponse{response: response{Status: statusSuccess, Code: codeSuccess}, Data: book})
+	response.Success(c, http.StatusCreated, book)
 }
 
+// GetBookByID returns the book as it is, or as it was at the RFC 3339
+// time of the as_of query param
 func (h *BookHandler) GetBookByID(c *gin.Context) {
 	id := c.Param("id")
 
-	book, err := h.BookUseCase.GetBookByID(c.Request.Context(), id)
+	var (
+		book *domain.Book
+		err  error
+	)
+	if asOf := c.Query("as_of"); asOf != "" {
+		at, perr := time.Parse(time.RFC3339, asOf)
+		if perr != nil {
+			response.Error(c, apperror.NewValidation([]apperror.FieldError{
+				apperror.NewFieldError("query", "as_of", "/as_of", "format", asOf, apperror.Params{"param": "date-time"}),
+			}))
+			return
+		}
+		book, err = h.BookUseCase.GetBookAsOf(c.Request.Context(), id, at)
+	} else {
+		book, err = h.BookUseCase.GetBookByID(c.Request.Context(), id)
+	}
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, book)
+}
+
+// GetBookByISBN returns the book with the ISBN-10 or ISBN-13 of the
+// path, in any form NormalizeISBN accepts
+func (h *BookHandler) GetBookByISBN(c *gin.Context) {
+	isbn := c.Param("isbn")
+	if !domain.ValidISBN(isbn) {
+		response.Error(c, apperror.NewValidation([]apperror.FieldError{
+			apperror.NewFieldError("path", "isbn", "/isbn", "isbn", isbn, apperror.Params{}),
+		}))
+		return
+	}
+
+	book, err := h.BookUseCase.GetBookByISBN(c.Request.Context(), isbn)
 	if err != nil {
-		c.JSON(apperror.Status(err), errorResponse{response: response{Status: statusFail, Code: codeFail}, Error: err.Error()})
+		response.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, successResponse{response: response{Status: statusSuccess, Code: codeSuccess}, Data: book})
+	response.Success(c, http.StatusOK, book)
 }
 
 func (h *BookHandler) UpdateBook(c *gin.Context) {
@@ -85,11 +193,11 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 	id := c.Param("id")
 	err := h.BookUseCase.UpdateBook(c.Request.Context(), id, &book)
 	if err != nil {
-		c.JSON(apperror.Status(err), errorResponse{response: response{Status: statusFail, Code: codeFail}, Error: err.Error()})
+		response.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, successResponse{response: response{Status: statusSuccess, Code: codeSuccess}, Data: book})
+	response.Success(c, http.StatusOK, book)
 }
 
 func (h *BookHandler) DeleteBook(c *gin.Context) {
@@ -97,9 +205,110 @@ func (h *BookHandler) DeleteBook(c *gin.Context) {
 
 	err := h.BookUseCase.DeleteBook(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(apperror.Status(err), errorResponse{response: response{Status: statusFail, Code: codeFail}, Error: err.Error()})
+		response.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, successResponse{response: response{Status: statusSuccess, Code: codeSuccess}})
+	response.Success(c, http.StatusOK, nil)
+}
+
+func (h *BookHandler) FetchBookHistory(c *gin.Context) {
+	id := c.Param("id")
+
+	history, err := h.BookUseCase.FetchBookHistory(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, history)
+}
+
+func (h *BookHandler) RevertBook(c *gin.Context) {
+	id := c.Param("id")
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision < 1 {
+		response.Error(c, apperror.NewValidation([]apperror.FieldError{
+			apperror.NewFieldError("path", "revision", "/revision", "min", c.Param("revision"), apperror.Params{"param": "1"}),
+		}))
+		return
+	}
+
+	book, err := h.BookUseCase.RevertBook(c.Request.Context(), id, revision)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, book)
+}
+
+func (h *BookHandler) FetchTrash(c *gin.Context) {
+	trash, err := h.BookUseCase.FetchTrash(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, trash)
+}
+
+func (h *BookHandler) RestoreBook(c *gin.Context) {
+	id := c.Param("id")
+
+	book, err := h.BookUseCase.RestoreBook(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, book)
+}
+
+// bookFilter parses the title, author, author_id, isbn, publisher,
+// publisher_id, series_id, work_id, language, format, genre, subject,
+// published_after and published_before query params
+func bookFilter(c *gin.Context) (domain.BookFilter, error) {
+	filter := domain.BookFilter{
+		Title:           c.Query("title"),
+		Author:          c.Query("author"),
+		ISBN:            c.Query("isbn"),
+		Publisher:       c.Query("publisher"),
+		Language:        c.Query("language"),
+		Format:          domain.BookFormat(c.Query("format")),
+		Genre:           c.Query("genre"),
+		Subject:         c.Query("subject"),
+		PublishedAfter:  domain.PublicationDate(c.Query("published_after")),
+		PublishedBefore: domain.PublicationDate(c.Query("published_before")),
+	}
+
+	var fields []apperror.FieldError
+	for _, p := range []struct {
+		name string
+		id   *uuid.UUID
+	}{{"author_id", &filter.AuthorID}, {"publisher_id", &filter.PublisherID}, {"series_id", &filter.SeriesID}, {"work_id", &filter.WorkID}} {
+		if value := c.Query(p.name); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				fields = append(fields, apperror.NewFieldError("query", p.name, "/"+p.name, "format", value, apperror.Params{"param": "uuid"}))
+			}
+			*p.id = id
+		}
+	}
+	if filter.Format != "" && !filter.Format.Valid() {
+		fields = append(fields, apperror.NewFieldError("query", "format", "/format", "oneof", string(filter.Format), apperror.Params{"param": "hardcover paperback ebook audio"}))
+	}
+	for _, p := range []struct {
+		name string
+		date domain.PublicationDate
+	}{{"published_after", filter.PublishedAfter}, {"published_before", filter.PublishedBefore}} {
+		if p.date != "" && !p.date.Valid() {
+			fields = append(fields, apperror.NewFieldError("query", p.name, "/"+p.name, "publication_date", string(p.date), apperror.Params{"min": domain.MinPublicationYear}))
+		}
+	}
+
+	if len(fields) > 0 {
+		return filter, apperror.NewValidation(fields)
+	}
+	return filter, nil
 }
@@ -2,13 +2,19 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/krittawatcode/books/delivery/auth"
+	"github.com/krittawatcode/books/delivery/middleware"
+	"github.com/krittawatcode/books/delivery/response"
 	"github.com/krittawatcode/books/domain"
 	"github.com/krittawatcode/books/domain/apperror"
 	"github.com/krittawatcode/books/domain/appmock"
@@ -28,7 +34,7 @@ func TestBookHandler_FetchBooks(t *testing.T) {
 		c.Request, _ = http.NewRequest("GET", "/books", nil)
 
 		mockBookUseCase := new(appmock.MockBookUseCase)
-		mockBookUseCase.On("FetchBooks", mock.Anything).Return(&[]domain.Book{}, apperror.NewNotFound("Book", "ID", ""))
+		mockBookUseCase.On("FetchBooks", mock.Anything, mock.Anything).Return(&[]domain.Book{}, apperror.NewNotFound("Book", "ID", ""))
 
 		h := &BookHandler{
 			BookUseCase: mockBookUseCase,
@@ -47,12 +53,12 @@ func TestBookHandler_FetchBooks(t *testing.T) {
 		c.Request, _ = http.NewRequest("GET", "/books", nil)
 
 		mockBooks := []domain.Book{
-			{ID: uuid.New(), Title: "Book 1", Author: "Author 1", PublicationYear: "2021"},
-			{ID: uuid.New(), Title: "Book 2", Author: "Author 2", PublicationYear: "2022"},
-			{ID: uuid.New(), Title: "Book 3", Author: "Author 3", PublicationYear: "2023"},
+			{ID: uuid.New(), Title: "Book 1", Author: "Author 1", PublicationDate: "2021"},
+			{ID: uuid.New(), Title: "Book 2", Author: "Author 2", PublicationDate: "2022"},
+			{ID: uuid.New(), Title: "Book 3", Author: "Author 3", PublicationDate: "2023"},
 		}
 		mockBookUseCase := new(appmock.MockBookUseCase)
-		mockBookUseCase.On("FetchBooks", mock.Anything).Return(&mockBooks, nil)
+		mockBookUseCase.On("FetchBooks", mock.Anything, mock.Anything).Return(&mockBooks, nil)
 
 		h := &BookHandler{
 			BookUseCase: mockBookUseCase,
@@ -62,6 +68,45 @@ func TestBookHandler_FetchBooks(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
+
+	t.Run("Filtered", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request, _ = http.NewRequest("GET", "/books?title=go&language=en&format=ebook&genre=Programming", nil)
+
+		mockBookUseCase := new(appmock.MockBookUseCase)
+		filter := domain.BookFilter{Title: "go", Language: "en", Format: domain.FormatEbook, Genre: "Programming"}
+		mockBookUseCase.On("FetchBooks", mock.Anything, filter).Return(&[]domain.Book{}, nil)
+
+		h := &BookHandler{
+			BookUseCase: mockBookUseCase,
+		}
+
+		h.FetchBooks(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockBookUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request, _ = http.NewRequest("GET", "/books?format=scroll", nil)
+
+		mockBookUseCase := new(appmock.MockBookUseCase)
+
+		h := &BookHandler{
+			BookUseCase: mockBookUseCase,
+		}
+
+		h.FetchBooks(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"format"`)
+		mockBookUseCase.AssertNotCalled(t, "FetchBooks")
+	})
 }
 
 func TestBookHandler_CreateBook(t *testing.T) {
@@ -76,7 +121,7 @@ func TestBookHandler_CreateBook(t *testing.T) {
 		newBook := &domain.Book{
 			Title:           "New Book",
 			Author:          "New Author",
-			PublicationYear: "2022",
+			PublicationDate: "2022",
 		}
 
 		// Convert the new book to JSON
@@ -106,7 +151,7 @@ func TestBookHandler_CreateBook(t *testing.T) {
 		newBook := &domain.Book{
 			Title:           "New Book",
 			Author:          "New Author",
-			PublicationYear: "2022",
+			PublicationDate: "2022",
 		}
 
 		// Convert the new book to JSON
@@ -163,7 +208,7 @@ func TestBookHandler_GetBookByID(t *testing.T) {
 			ID:              uuid.New(),
 			Title:           "Book 1",
 			Author:          "Author 1",
-			PublicationYear: "2021",
+			PublicationDate: "2021",
 		}
 
 		mockBookUseCase := new(appmock.MockBookUseCase)
@@ -192,7 +237,7 @@ func TestBookHandler_UpdateBook(t *testing.T) {
 			ID:              uuid.New(),
 			Title:           "Updated Book",
 			Author:          "Updated Author",
-			PublicationYear: "2022",
+			PublicationDate: "2022",
 		}
 
 		// Convert the book to JSON
@@ -223,7 +268,7 @@ func TestBookHandler_UpdateBook(t *testing.T) {
 			ID:              uuid.New(),
 			Title:           "Updated Book",
 			Author:          "Updated Author",
-			PublicationYear: "2022",
+			PublicationDate: "2022",
 		}
 
 		// Convert the book to JSON
@@ -288,3 +333,579 @@ func TestBookHandler_DeleteBook(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 }
+
+// newContractRouter routes through NewBookHandler with requests and responses
+// validated against the OpenAPI document, so contract breaks fail with a 500
+func newContractRouter(bu domain.BookUseCase) *gin.Engine {
+	router := gin.New()
+	validator := middleware.OpenAPIValidator(NewOpenAPIDocument("/books"), middleware.OpenAPIOptions{ValidateResponses: true})
+	NewBookHandler(router, bu, "/books", time.Second, WithMiddleware(validator))
+
+	return router
+}
+
+func TestBookHandler_FetchBooks_PublicationDate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockBookUseCase := new(appmock.MockBookUseCase)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/books/?published_after=1200", nil)
+	newContractRouter(mockBookUseCase).ServeHTTP(w, req)
+
+	var body struct {
+		Details []apperror.FieldError `json:"details"`
+	}
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, []apperror.FieldError{
+		{In: "query", Field: "published_after", Pointer: "/published_after", Rule: "publication_date", Message: "published_after must be a year, month or day in the form YYYY, YYYY-MM or YYYY-MM-DD, from 1450 on", Value: "1200"},
+	}, body.Details)
+	mockBookUseCase.AssertNotCalled(t, "FetchBooks", mock.Anything, mock.Anything)
+}
+
+func TestBookHandler_GetBookByISBN(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Invalid check digit", func(t *testing.T) {
+		mockBookUseCase := new(appmock.MockBookUseCase)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/books/isbn/9780134190441", nil)
+		newContractRouter(mockBookUseCase).ServeHTTP(w, req)
+
+		var body struct {
+			Details []apperror.FieldError `json:"details"`
+		}
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, []apperror.FieldError{
+			{In: "path", Field: "isbn", Pointer: "/isbn", Rule: "isbn", Message: "isbn must be an ISBN-10 or ISBN-13 with a valid check digit", Value: "9780134190441"},
+		}, body.Details)
+		mockBookUseCase.AssertNotCalled(t, "GetBookByISBN", mock.Anything, mock.Anything)
+	})
+}
+
+func TestBookHandler_Contract(t *testing.T) {
+	// setup
+	gin.SetMode(gin.TestMode)
+
+	id := uuid.New()
+	book := &domain.Book{ID: id, Title: "Book 1", Author: "Author 1", PublicationDate: "2021"}
+	bookJSON, _ := json.Marshal(book)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   []byte
+		accept string
+		setup  func(m *appmock.MockBookUseCase)
+		code   int
+	}{
+		{
+			name:   "FetchBooks - Success",
+			method: "GET",
+			path:   "/books/",
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("FetchBooks", mock.Anything, mock.Anything).Return(&[]domain.Book{*book}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "FetchBooks - Error",
+			method: "GET",
+			path:   "/books/",
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("FetchBooks", mock.Anything, mock.Anything).Return(&[]domain.Book{}, apperror.NewNotFound("Book", "ID", ""))
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name:   "CreateBook - Success",
+			method: "POST",
+			path:   "/books/",
+			body:   bookJSON,
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("CreateBook", mock.Anything, mock.AnythingOfType("*domain.Book")).Return(nil)
+			},
+			code: http.StatusCreated,
+		},
+		{
+			name:   "FetchBooks - Unknown format",
+			method: "GET",
+			path:   "/books/?format=scroll",
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "FetchBooks - Published between",
+			method: "GET",
+			path:   "/books/?published_after=1989&published_before=2000-01",
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("FetchBooks", mock.Anything, domain.BookFilter{PublishedAfter: "1989", PublishedBefore: "2000-01"}).Return(&[]domain.Book{*book}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "FetchBooks - By author",
+			method: "GET",
+			path:   "/books/?author_id=" + book.ID.String(),
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("FetchBooks", mock.Anything, domain.BookFilter{AuthorID: book.ID}).Return(&[]domain.Book{*book}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "FetchBooks - By publisher",
+			method: "GET",
+			path:   "/books/?publisher_id=" + book.ID.String(),
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("FetchBooks", mock.Anything, domain.BookFilter{PublisherID: book.ID}).Return(&[]domain.Book{*book}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "FetchBooks - By work",
+			method: "GET",
+			path:   "/books/?work_id=" + book.ID.String(),
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("FetchBooks", mock.Anything, domain.BookFilter{WorkID: book.ID}).Return(&[]domain.Book{*book}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "FetchBooks - Invalid work_id",
+			method: "GET",
+			path:   "/books/?work_id=dune",
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "FetchBooks - Invalid author_id",
+			method: "GET",
+			path:   "/books/?author_id=austen",
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "CreateBook - Contributor role",
+			method: "POST",
+			path:   "/books/",
+			body:   []byte(`{"title":"Go","author":"Rob","publication_year":"2015","contributors":[{"author_id":"` + book.ID.String() + `","role":"narrator"}]}`),
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "FetchBooks - Invalid published_after",
+			method: "GET",
+			path:   "/books/?published_after=1999-13",
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "CreateBook - Full publication date",
+			method: "POST",
+			path:   "/books/",
+			body:   []byte(`{"title":"Go","author":"Rob","publication_year":"2015-10-26"}`),
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("CreateBook", mock.Anything, mock.MatchedBy(func(b *domain.Book) bool {
+					return b.PublicationDate == "2015-10-26"
+				})).Return(nil)
+			},
+			code: http.StatusCreated,
+		},
+		{
+			name:   "CreateBook - Malformed publication date",
+			method: "POST",
+			path:   "/books/",
+			body:   []byte(`{"title":"Go","author":"Rob","publication_year":"abc"}`),
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "CreateBook - Bibliographic fields",
+			method: "POST",
+			path:   "/books/",
+			body: []byte(`{"title":"The Go Programming Language","author":"Alan Donovan","publication_year":"2015",
+				"isbn":"978-0-13-419044-0","publisher":"Addison-Wesley","language":"en-US","pages":380,
+				"description":"An introduction to Go","genres":["Programming"],"subjects":["Go (Computer program language)"],
+				"edition":"1st","format":"paperback"}`),
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("CreateBook", mock.Anything, mock.MatchedBy(func(b *domain.Book) bool {
+					return b.ISBN == "978-0-13-419044-0" && b.Language == "en-US" && b.Pages == 380 &&
+						b.Format == domain.FormatPaperback && len(b.Genres) == 1 && len(b.Subjects) == 1
+				})).Return(nil)
+			},
+			code: http.StatusCreated,
+		},
+		{
+			name:   "CreateBook - Invalid language",
+			method: "POST",
+			path:   "/books/",
+			body:   []byte(`{"title":"Go","author":"Rob","publication_year":"2015","language":"not a language"}`),
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "CreateBook - Invalid format and pages",
+			method: "POST",
+			path:   "/books/",
+			body:   []byte(`{"title":"Go","author":"Rob","publication_year":"2015","format":"scroll","pages":0}`),
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "CreateBook - Conflict",
+			method: "POST",
+			path:   "/books/",
+			body:   bookJSON,
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("CreateBook", mock.Anything, mock.AnythingOfType("*domain.Book")).Return(apperror.NewConflict("book", "title"))
+			},
+			code: http.StatusConflict,
+		},
+		{
+			name:   "CreateBook - Invalid body",
+			method: "POST",
+			path:   "/books/",
+			body:   []byte(`{"title":""}`),
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "GetBookByID - Success",
+			method: "GET",
+			path:   "/books/" + id.String(),
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("GetBookByID", mock.Anything, id.String()).Return(book, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "GetBookByID - Series neighbours",
+			method: "GET",
+			path:   "/books/" + id.String(),
+			setup: func(m *appmock.MockBookUseCase) {
+				inSeries := *book
+				inSeries.Series = []domain.SeriesEntry{{
+					SeriesID: uuid.New(),
+					Position: 2.5,
+					Previous: &domain.SeriesNeighbour{ID: uuid.New(), Title: "Book 2", Position: 2},
+					Next:     &domain.SeriesNeighbour{ID: uuid.New(), Title: "Book 3", Position: 3},
+				}}
+				m.On("GetBookByID", mock.Anything, id.String()).Return(&inSeries, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "CreateBook - Negative series position",
+			method: "POST",
+			path:   "/books/",
+			body:   []byte(`{"title":"Go","author":"Rob","publication_year":"2015","series":[{"series_id":"` + id.String() + `","position":-1}]}`),
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "GetBookByID - Problem Details",
+			method: "GET",
+			path:   "/books/" + id.String(),
+			accept: response.ProblemContentType,
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("GetBookByID", mock.Anything, id.String()).Return(&domain.Book{}, apperror.NewNotFound("Book", "ID", id.String()))
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name:   "CreateBook - Invalid body (Problem Details)",
+			method: "POST",
+			path:   "/books/",
+			body:   []byte(`{"title":""}`),
+			accept: response.ProblemContentType,
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "GetBookByISBN - Success",
+			method: "GET",
+			path:   "/books/isbn/978-0-13-419044-0",
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("GetBookByISBN", mock.Anything, "978-0-13-419044-0").Return(&domain.Book{ID: id, Title: "Book 1", Author: "Author 1", PublicationDate: "2021", ISBN: "9780134190440"}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "GetBookByISBN - Not found",
+			method: "GET",
+			path:   "/books/isbn/9780134190440",
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("GetBookByISBN", mock.Anything, "9780134190440").Return((*domain.Book)(nil), apperror.NewNotFound("Book", "ISBN", "9780134190440"))
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name:   "GetBookByISBN - Invalid check digit",
+			method: "GET",
+			path:   "/books/isbn/9780134190441",
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "CreateBook - Invalid ISBN",
+			method: "POST",
+			path:   "/books/",
+			body:   []byte(`{"title":"Go","author":"Rob","publication_year":"2015","isbn":"0-13-419044-1"}`),
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "GetBookByID - Invalid ID",
+			method: "GET",
+			path:   "/books/1",
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "UpdateBook - Success",
+			method: "PUT",
+			path:   "/books/" + id.String(),
+			body:   bookJSON,
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("UpdateBook", mock.Anything, id.String(), mock.AnythingOfType("*domain.Book")).Return(nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "UpdateBook - Not found",
+			method: "PUT",
+			path:   "/books/" + id.String(),
+			body:   bookJSON,
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("UpdateBook", mock.Anything, id.String(), mock.AnythingOfType("*domain.Book")).Return(apperror.NewNotFound("Book", "ID", id.String()))
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name:   "DeleteBook - Forbidden",
+			method: "DELETE",
+			path:   "/books/" + id.String(),
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("DeleteBook", mock.Anything, id.String()).Return(apperror.NewForbidden("the books:delete permission is required"))
+			},
+			code: http.StatusForbidden,
+		},
+		{
+			name:   "DeleteBook - Active copies",
+			method: "DELETE",
+			path:   "/books/" + id.String(),
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("DeleteBook", mock.Anything, id.String()).Return(apperror.NewConflictCode("active_copies", apperror.Params{"book_id": id}))
+			},
+			code: http.StatusConflict,
+		},
+		{
+			name:   "GetBookByID - Copy counts",
+			method: "GET",
+			path:   "/books/" + id.String(),
+			setup: func(m *appmock.MockBookUseCase) {
+				counted := *book
+				counted.Copies = &domain.CopyCounts{Available: 1, Total: 3}
+				m.On("GetBookByID", mock.Anything, id.String()).Return(&counted, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "DeleteBook - Success",
+			method: "DELETE",
+			path:   "/books/" + id.String(),
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("DeleteBook", mock.Anything, id.String()).Return(nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "GetBookByID - As of",
+			method: "GET",
+			path:   "/books/" + id.String() + "?as_of=2024-01-02T03:04:05Z",
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("GetBookAsOf", mock.Anything, id.String(), time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)).Return(book, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "GetBookByID - Invalid as of",
+			method: "GET",
+			path:   "/books/" + id.String() + "?as_of=yesterday",
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "FetchTrash - Success",
+			method: "GET",
+			path:   "/books/trash",
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("FetchTrash", mock.Anything).Return(&[]domain.TrashedBook{
+					{Book: *book, DeletedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), DeletedBy: "alice"},
+				}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "RestoreBook - Success",
+			method: "POST",
+			path:   "/books/" + id.String() + "/restore",
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("RestoreBook", mock.Anything, id.String()).Return(book, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "RestoreBook - Conflict",
+			method: "POST",
+			path:   "/books/" + id.String() + "/restore",
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("RestoreBook", mock.Anything, id.String()).Return((*domain.Book)(nil), apperror.NewConflict("book", "title, author, publication year and edition"))
+			},
+			code: http.StatusConflict,
+		},
+		{
+			name:   "FetchBookHistory - Success",
+			method: "GET",
+			path:   "/books/" + id.String() + "/history",
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("FetchBookHistory", mock.Anything, id.String()).Return(&[]domain.BookRevision{
+					{Revision: 1, Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Action: domain.AuditActionCreate, Book: *book},
+					{Revision: 2, Time: time.Date(2024, 1, 3, 3, 4, 5, 0, time.UTC), Action: domain.AuditActionDelete, Book: *book},
+				}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "RevertBook - Success",
+			method: "POST",
+			path:   "/books/" + id.String() + "/revert/1",
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("RevertBook", mock.Anything, id.String(), 1).Return(book, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "RevertBook - Invalid revision",
+			method: "POST",
+			path:   "/books/" + id.String() + "/revert/0",
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "RevertBook - Not found",
+			method: "POST",
+			path:   "/books/" + id.String() + "/revert/9",
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("RevertBook", mock.Anything, id.String(), 9).Return((*domain.Book)(nil), apperror.NewNotFound("BookRevision", "revision", "9"))
+			},
+			code: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBookUseCase := new(appmock.MockBookUseCase)
+			tt.setup(mockBookUseCase)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBuffer(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			newContractRouter(mockBookUseCase).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code, w.Body.String())
+			mockBookUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBookHandler_Auth(t *testing.T) {
+	// setup
+	gin.SetMode(gin.TestMode)
+
+	secret := []byte("test-secret")
+	authn := auth.NewJWTAuthenticator(auth.NewHMACKey(secret), auth.JWTOptions{Issuer: "https://issuer.test"})
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "user-1",
+		"iss": "https://issuer.test",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString(secret)
+
+	book := &domain.Book{Title: "Book 1", Author: "Author 1", PublicationDate: "2021"}
+	bookJSON, _ := json.Marshal(book)
+
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		setup  func(m *appmock.MockBookUseCase)
+		code   int
+	}{
+		{
+			name:   "Read without token",
+			method: "GET",
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("FetchBooks", mock.Anything, mock.Anything).Return(&[]domain.Book{*book}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "Read with invalid token",
+			method: "GET",
+			token:  "invalid",
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "Write without token",
+			method: "POST",
+			setup:  func(m *appmock.MockBookUseCase) {},
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "Write with token",
+			method: "POST",
+			token:  token,
+			setup: func(m *appmock.MockBookUseCase) {
+				m.On("CreateBook", mock.MatchedBy(func(ctx context.Context) bool {
+					p, ok := domain.PrincipalFromContext(ctx)
+					return ok && p.Subject == "user-1"
+				}), mock.AnythingOfType("*domain.Book")).Return(nil)
+			},
+			code: http.StatusCreated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBookUseCase := new(appmock.MockBookUseCase)
+			tt.setup(mockBookUseCase)
+
+			router := gin.New()
+			NewBookHandler(router, mockBookUseCase, "/books", time.Second,
+				WithReadMiddleware(middleware.OptionalAuth(authn)),
+				WithWriteMiddleware(middleware.RequireAuth(authn)),
+			)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, "/books/", bytes.NewBuffer(bookJSON))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code, w.Body.String())
+			mockBookUseCase.AssertExpectations(t)
+		})
+	}
+}
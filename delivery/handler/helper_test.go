@@ -1,13 +1,17 @@
 package handler
 
 import (
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/middleware"
 	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -26,7 +30,27 @@ func TestBindData(t *testing.T) {
 		assert.True(t, result)
 		assert.Equal(t, "Prach", book.Author)
 		assert.Equal(t, "100x", book.Title)
-		assert.Equal(t, "2021", book.PublicationYear)
+		assert.Equal(t, domain.PublicationDate("2021"), book.PublicationDate)
+	})
+
+	t.Run("Request body too large", func(t *testing.T) {
+		router := gin.New()
+		router.Use(middleware.BodyLimit(16, nil))
+		router.POST("/test", func(c *gin.Context) {
+			var book domain.Book
+			bindData(c, &book)
+		})
+
+		w := httptest.NewRecorder()
+		// hide the length, so the limit is only hit while decoding
+		reqBody := struct{ io.Reader }{strings.NewReader(`{"title":"100x","author":"Prach", "publication_year":"2021"}`)}
+		req := httptest.NewRequest(http.MethodPost, "/test", reqBody)
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), `"error_code":"payload_too_large"`)
 	})
 
 	t.Run("Invalid request body - non-json", func(t *testing.T) {
@@ -44,6 +68,108 @@ func TestBindData(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
+	t.Run("Invalid request body - missing fields", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		reqBody := strings.NewReader(`{"title":"","author":"Prach"}`)
+		c.Request = httptest.NewRequest(http.MethodPost, "/test", reqBody)
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		var book domain.Book
+		result := bindData(c, &book)
+
+		var body struct {
+			Details []apperror.FieldError `json:"details"`
+		}
+		assert.False(t, result)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, []apperror.FieldError{
+			{In: "body", Field: "title", Pointer: "/title", Rule: "required", Message: "title is required", Value: ""},
+			{In: "body", Field: "publication_year", Pointer: "/publication_year", Rule: "required", Message: "publication_year is required", Value: ""},
+		}, body.Details)
+	})
+
+	t.Run("ISBN with hyphens", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		reqBody := strings.NewReader(`{"title":"100x","author":"Prach","publication_year":"2021","isbn":"0-8044-2957-X"}`)
+		c.Request = httptest.NewRequest(http.MethodPost, "/test", reqBody)
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		var book domain.Book
+		result := bindData(c, &book)
+
+		assert.True(t, result)
+		assert.Equal(t, "0-8044-2957-X", book.ISBN)
+	})
+
+	t.Run("Invalid request body - ISBN check digit", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		reqBody := strings.NewReader(`{"title":"100x","author":"Prach","publication_year":"2021","isbn":"978-0-13-419044-1"}`)
+		c.Request = httptest.NewRequest(http.MethodPost, "/test", reqBody)
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		var book domain.Book
+		result := bindData(c, &book)
+
+		var body struct {
+			Details []apperror.FieldError `json:"details"`
+		}
+		assert.False(t, result)
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, []apperror.FieldError{
+			{In: "body", Field: "isbn", Pointer: "/isbn", Rule: "isbn", Message: "isbn must be an ISBN-10 or ISBN-13 with a valid check digit", Value: "978-0-13-419044-1"},
+		}, body.Details)
+	})
+
+	t.Run("Invalid request body - publication date", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		reqBody := strings.NewReader(`{"title":"100x","author":"Prach","publication_year":"1200"}`)
+		c.Request = httptest.NewRequest(http.MethodPost, "/test", reqBody)
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		var book domain.Book
+		result := bindData(c, &book)
+
+		var body struct {
+			Details []apperror.FieldError `json:"details"`
+		}
+		assert.False(t, result)
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, []apperror.FieldError{
+			{In: "body", Field: "publication_year", Pointer: "/publication_year", Rule: "publication_date", Message: "publication_year must be a year, month or day in the form YYYY, YYYY-MM or YYYY-MM-DD, from 1450 on", Value: "1200"},
+		}, body.Details)
+	})
+
+	t.Run("Invalid request body - wrong type", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		reqBody := strings.NewReader(`{"title":"100x","author":"Prach","publication_year":2021}`)
+		c.Request = httptest.NewRequest(http.MethodPost, "/test", reqBody)
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		var book domain.Book
+		result := bindData(c, &book)
+
+		var body struct {
+			Details []apperror.FieldError `json:"details"`
+		}
+		assert.False(t, result)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, []apperror.FieldError{
+			{In: "body", Field: "publication_year", Pointer: "/publication_year", Rule: "type", Message: "publication_year must be of type string", Value: "number"},
+		}, body.Details)
+	})
+
 	t.Run("Invalid request body - incorrect json structure", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
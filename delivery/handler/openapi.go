@@ -0,0 +1,1215 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/krittawatcode/books/delivery/auth"
+	"github.com/krittawatcode/books/delivery/openapi"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+// apiVersion is reported in the info object of the OpenAPI document
+const apiVersion = "1.0.0"
+
+// reads of the catalog may send a token, writes must
+var (
+	readSecurity  = []openapi.SecurityRequirement{{}, {"bearerAuth": {}}, {"apiKeyAuth": {}}}
+	writeSecurity = []openapi.SecurityRequirement{{"bearerAuth": {}}, {"apiKeyAuth": {}}}
+)
+
+// errorResponses maps each apperror.Type to the name of its component response
+var errorResponses = map[apperror.Type]string{
+	apperror.Authorization:        "Unauthorized",
+	apperror.BadRequest:           "BadRequest",
+	apperror.Conflict:             "Conflict",
+	apperror.Forbidden:            "Forbidden",
+	apperror.Internal:             "InternalServerError",
+	apperror.NotFound:             "NotFound",
+	apperror.PayloadTooLarge:      "PayloadTooLarge",
+	apperror.ServiceUnavailable:   "ServiceUnavailable",
+	apperror.TooManyRequests:      "TooManyRequests",
+	apperror.UnsupportedMediaType: "UnsupportedMediaType",
+}
+
+// NewOpenAPIDocument describes every route registered by NewBookHandler
+// with the books group mounted at booksPath
+func NewOpenAPIDocument(booksPath string) *openapi.Document {
+	doc := openapi.New("Books API", apiVersion)
+	doc.Info.Description = "Book management service."
+
+	addComponents(doc)
+	addBookOperations(doc, booksPath)
+	addAuthorOperations(doc)
+	addPublisherOperations(doc)
+	addSeriesOperations(doc)
+	addWorkOperations(doc)
+	addCopyOperations(doc, booksPath)
+	addAPIKeyOperations(doc)
+	addAuditOperations(doc)
+
+	return doc
+}
+
+func addComponents(doc *openapi.Document) {
+	doc.Components.Schemas["Book"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"title", "author", "publication_year"},
+		Properties: map[string]*openapi.Schema{
+			"id": {
+				Type:     "string",
+				Format:   "uuid",
+				ReadOnly: true,
+			},
+			"title": {
+				Type:      "string",
+				MinLength: openapi.Int(1),
+				Example:   "test100x",
+			},
+			"author": {
+				Type:      "string",
+				MinLength: openapi.Int(1),
+				Example:   "Prach",
+			},
+			"publication_year": openapi.Ref("PublicationDate"),
+			"isbn": {
+				Type:        "string",
+				Description: "ISBN-10 or ISBN-13 with a valid check digit, hyphens allowed; stored and returned as 13 digits",
+				Pattern:     "^[0-9Xx -]{10,17}$",
+				Example:     "978-0-13-419044-0",
+			},
+			"publisher": {
+				Type:      "string",
+				MaxLength: openapi.Int(255),
+			},
+			"publisher_id": {
+				Type:        "string",
+				Format:      "uuid",
+				Description: "Publisher of the catalog, publisher stays the name as printed",
+			},
+			"language": {
+				Type:        "string",
+				Description: "BCP 47 language tag",
+				Example:     "en-GB",
+			},
+			"pages": {
+				Type:    "integer",
+				Minimum: openapi.Float(1),
+				Maximum: openapi.Float(100000),
+			},
+			"description": {
+				Type:      "string",
+				MaxLength: openapi.Int(10000),
+			},
+			"genres": {
+				Type:     "array",
+				MaxItems: openapi.Int(20),
+				Items:    &openapi.Schema{Type: "string", MinLength: openapi.Int(1), MaxLength: openapi.Int(64)},
+			},
+			"subjects": {
+				Type:     "array",
+				MaxItems: openapi.Int(50),
+				Items:    &openapi.Schema{Type: "string", MinLength: openapi.Int(1), MaxLength: openapi.Int(128)},
+			},
+			"edition": {
+				Type:      "string",
+				MaxLength: openapi.Int(64),
+				Example:   "2nd",
+			},
+			"format": openapi.Ref("BookFormat"),
+			"contributors": {
+				Type:        "array",
+				Description: "Authors of the catalog credited for the book, author stays the name as printed",
+				MaxItems:    openapi.Int(50),
+				Items:       openapi.Ref("Contributor"),
+			},
+			"series": {
+				Type:     "array",
+				MaxItems: openapi.Int(10),
+				Items:    openapi.Ref("SeriesEntry"),
+			},
+			"work_id": {
+				Type:        "string",
+				Format:      "uuid",
+				Description: "Work the book is an edition of",
+			},
+			"copies": openapi.Ref("CopyCounts"),
+		},
+	}
+
+	doc.Components.Schemas["Contributor"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"author_id", "role"},
+		Properties: map[string]*openapi.Schema{
+			"author_id": {Type: "string", Format: "uuid"},
+			"role":      openapi.Ref("ContributorRole"),
+		},
+	}
+
+	roles := make([]interface{}, len(domain.ContributorRoles))
+	for i, r := range domain.ContributorRoles {
+		roles[i] = string(r)
+	}
+	doc.Components.Schemas["ContributorRole"] = &openapi.Schema{Type: "string", Enum: roles}
+
+	doc.Components.Schemas["Author"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"name"},
+		Properties: map[string]*openapi.Schema{
+			"id":   {Type: "string", Format: "uuid", ReadOnly: true},
+			"name": {Type: "string", MinLength: openapi.Int(1), MaxLength: openapi.Int(255), Example: "J. K. Rowling"},
+			"bio":  {Type: "string", MaxLength: openapi.Int(10000)},
+		},
+	}
+
+	doc.Components.Schemas["Publisher"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"name"},
+		Properties: map[string]*openapi.Schema{
+			"id":        {Type: "string", Format: "uuid", ReadOnly: true},
+			"name":      {Type: "string", MinLength: openapi.Int(1), MaxLength: openapi.Int(255), Example: "Penguin Books"},
+			"parent_id": {Type: "string", Format: "uuid", Description: "Publisher an imprint belongs to"},
+		},
+	}
+
+	doc.Components.Schemas["Series"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"name"},
+		Properties: map[string]*openapi.Schema{
+			"id":          {Type: "string", Format: "uuid", ReadOnly: true},
+			"name":        {Type: "string", MinLength: openapi.Int(1), MaxLength: openapi.Int(255), Example: "Discworld"},
+			"description": {Type: "string", MaxLength: openapi.Int(10000)},
+		},
+	}
+
+	doc.Components.Schemas["SeriesEntry"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"series_id", "position"},
+		Properties: map[string]*openapi.Schema{
+			"series_id": {Type: "string", Format: "uuid"},
+			"position": {
+				Type:        "number",
+				Description: "Reading order, unique within the series, eg. 2.5 for a novella between 2 and 3",
+				Minimum:     openapi.Float(0),
+				Example:     2.5,
+			},
+			"previous": openapi.Ref("SeriesNeighbour"),
+			"next":     openapi.Ref("SeriesNeighbour"),
+		},
+	}
+
+	doc.Components.Schemas["SeriesNeighbour"] = &openapi.Schema{
+		Type:        "object",
+		Description: "Book read before or after, set by getBookByID only",
+		ReadOnly:    true,
+		Required:    []string{"id", "title", "position"},
+		Properties: map[string]*openapi.Schema{
+			"id":       {Type: "string", Format: "uuid"},
+			"title":    {Type: "string"},
+			"position": {Type: "number"},
+		},
+	}
+
+	doc.Components.Schemas["Work"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"title"},
+		Properties: map[string]*openapi.Schema{
+			"id":    {Type: "string", Format: "uuid", ReadOnly: true},
+			"title": {Type: "string", MinLength: openapi.Int(1), MaxLength: openapi.Int(255), Example: "Dune"},
+		},
+	}
+
+	doc.Components.Schemas["WorkMerge"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"work_ids"},
+		Properties: map[string]*openapi.Schema{
+			"work_ids": {Type: "array", MinItems: openapi.Int(1), MaxItems: openapi.Int(50), Items: &openapi.Schema{Type: "string", Format: "uuid"}},
+		},
+	}
+
+	doc.Components.Schemas["WorkSplit"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"book_ids"},
+		Properties: map[string]*openapi.Schema{
+			"book_ids": {Type: "array", MinItems: openapi.Int(1), MaxItems: openapi.Int(500), Items: &openapi.Schema{Type: "string", Format: "uuid"}},
+			"title":    {Type: "string", MaxLength: openapi.Int(255), Description: "Title of the new work, the title of the first book by default"},
+		},
+	}
+
+	doc.Components.Schemas["WorkSuggestion"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"score", "books", "work_ids"},
+		Properties: map[string]*openapi.Schema{
+			"score":    {Type: "number", Description: "Title similarity of the least similar pair linking the group"},
+			"books":    {Type: "array", Items: openapi.Ref("Book")},
+			"work_ids": {Type: "array", Description: "Works some of the books belong to already", Items: &openapi.Schema{Type: "string", Format: "uuid"}},
+		},
+	}
+
+	doc.Components.Schemas["AuthorMigration"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"books", "authors", "failed"},
+		Properties: map[string]*openapi.Schema{
+			"books":   {Type: "integer", Description: "Books given contributors"},
+			"authors": {Type: "integer", Description: "Authors created"},
+			"failed": {
+				Type: "array",
+				Items: &openapi.Schema{
+					Type:     "object",
+					Required: []string{"book_id", "error"},
+					Properties: map[string]*openapi.Schema{
+						"book_id": {Type: "string", Format: "uuid"},
+						"error":   {Type: "string"},
+					},
+				},
+			},
+		},
+	}
+
+	doc.Components.Schemas["PublicationDate"] = &openapi.Schema{
+		Type:        "string",
+		Description: "A year, month or day, from 1450 up to a grace period after today",
+		Pattern:     "^[0-9]{4}(-[0-9]{2}(-[0-9]{2})?)?$",
+		Example:     "1994",
+	}
+
+	formats := make([]interface{}, len(domain.BookFormats))
+	for i, f := range domain.BookFormats {
+		formats[i] = string(f)
+	}
+	doc.Components.Schemas["BookFormat"] = &openapi.Schema{Type: "string", Enum: formats}
+
+	statuses := make([]interface{}, len(domain.CopyStatuses))
+	for i, s := range domain.CopyStatuses {
+		statuses[i] = string(s)
+	}
+	doc.Components.Schemas["CopyStatus"] = &openapi.Schema{Type: "string", Enum: statuses}
+
+	doc.Components.Schemas["Copy"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"barcode"},
+		Properties: map[string]*openapi.Schema{
+			"id":               {Type: "string", Format: "uuid", ReadOnly: true},
+			"book_id":          {Type: "string", Format: "uuid", ReadOnly: true},
+			"barcode":          {Type: "string", MinLength: openapi.Int(1), MaxLength: openapi.Int(64), Description: "Unique in the catalog, case-insensitive", Example: "31234000123456"},
+			"location":         {Type: "string", MaxLength: openapi.Int(255), Description: "Shelf location", Example: "2F-A12"},
+			"condition":        {Type: "string", MaxLength: openapi.Int(255), Example: "good"},
+			"acquisition_date": {Type: "string", Format: "date", Description: "Not in the future", Example: "2024-03-01"},
+			"status": {
+				Description: "Available when not given",
+				AllOf:       []*openapi.Schema{openapi.Ref("CopyStatus")},
+			},
+		},
+	}
+
+	doc.Components.Schemas["CopyCounts"] = &openapi.Schema{
+		Type:        "object",
+		Description: "Copies of the book, lost copies left out; set when the book is read",
+		ReadOnly:    true,
+		Required:    []string{"available", "total"},
+		Properties: map[string]*openapi.Schema{
+			"available": {Type: "integer", Minimum: openapi.Float(0)},
+			"total":     {Type: "integer", Minimum: openapi.Float(0)},
+		},
+	}
+
+	doc.Components.Schemas["successResponse"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"status", "code", "data"},
+		Properties: map[string]*openapi.Schema{
+			"status": {Type: "string", Const: string(response.StatusSuccess)},
+			"code":   {Type: "integer", Const: int(response.CodeSuccess)},
+			"data":   {Description: "Payload of the operation, null when there is nothing to return"},
+		},
+	}
+
+	doc.Components.Schemas["errorResponse"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"status", "code", "error_code", "error"},
+		Properties: map[string]*openapi.Schema{
+			"status":     {Type: "string", Const: string(response.StatusFail)},
+			"code":       {Type: "integer", Const: int(response.CodeFail)},
+			"error_code": {Type: "string", Description: "Stable machine code of the failure, eg. not_found"},
+			"error":      {Type: "string", Description: "Reason of the failure, localized through Accept-Language (en, th)"},
+			"details": {
+				Type:        "array",
+				Description: "Each invalid field of the request",
+				Items:       openapi.Ref("FieldError"),
+			},
+		},
+	}
+
+	doc.Components.Schemas["FieldError"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"in", "field", "pointer", "rule", "message"},
+		Properties: map[string]*openapi.Schema{
+			"in":      {Type: "string", Enum: []interface{}{"body", "path", "query", "response"}},
+			"field":   {Type: "string", Description: "JSON name of the field, dotted when nested"},
+			"pointer": {Type: "string", Description: "JSON pointer to the field"},
+			"rule":    {Type: "string", Description: "The rule which failed, eg. required"},
+			"message": {Type: "string"},
+			"value":   {Description: "The rejected value"},
+		},
+	}
+
+	doc.Components.Schemas["ProblemDetails"] = &openapi.Schema{
+		Type:        "object",
+		Description: "RFC 7807 Problem Details, sent when the client accepts application/problem+json",
+		Required:    []string{"type", "title", "status", "detail", "instance", "code"},
+		Properties: map[string]*openapi.Schema{
+			"type":     {Type: "string", Description: "URI reference identifying the apperror type, eg. /problems/notfound"},
+			"title":    {Type: "string"},
+			"status":   {Type: "integer"},
+			"detail":   {Type: "string", Description: "Localized through Accept-Language (en, th)"},
+			"instance": {Type: "string", Description: "The request URI"},
+			"code":     {Type: "string", Description: "Stable machine code of the failure, eg. not_found"},
+			"details": {
+				Type:  "array",
+				Items: openapi.Ref("FieldError"),
+			},
+		},
+	}
+
+	for t, name := range errorResponses {
+		doc.Components.Responses[name] = &openapi.Response{
+			Description: http.StatusText((&apperror.Error{Type: t}).Status()),
+			Content: map[string]*openapi.MediaType{
+				"application/json":          {Schema: openapi.Ref("errorResponse")},
+				response.ProblemContentType: {Schema: problemSchema(t)},
+			},
+		}
+	}
+
+	doc.Components.SecuritySchemes["bearerAuth"] = &openapi.SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+		Description:  "HS256 or RS256 token, required to modify books when authentication is configured",
+	}
+	doc.Components.SecuritySchemes["apiKeyAuth"] = &openapi.SecurityScheme{
+		Type:        "apiKey",
+		In:          "header",
+		Name:        auth.APIKeyHeader,
+		Description: "Key issued through " + APIKeysPath + ", also accepted as Authorization: ApiKey <key>",
+	}
+
+	doc.Components.Schemas["APIKey"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"id", "name", "prefix", "scopes", "created_at", "last_used_at", "revoked_at"},
+		Properties: map[string]*openapi.Schema{
+			"id":           {Type: "string", Format: "uuid"},
+			"name":         {Type: "string"},
+			"prefix":       {Type: "string", Description: "The start of the key, to tell keys apart"},
+			"scopes":       {Type: "array", Items: openapi.Ref("APIKeyScope")},
+			"created_at":   {Type: "string", Format: "date-time"},
+			"last_used_at": {OneOf: []*openapi.Schema{{Type: "string", Format: "date-time"}, {Type: "null"}}},
+			"revoked_at":   {OneOf: []*openapi.Schema{{Type: "string", Format: "date-time"}, {Type: "null"}}},
+		},
+	}
+
+	scopes := make([]interface{}, 0, len(domain.APIKeyScopes))
+	for _, s := range domain.APIKeyScopes {
+		scopes = append(scopes, s)
+	}
+	doc.Components.Schemas["APIKeyScope"] = &openapi.Schema{Type: "string", Enum: scopes}
+
+	doc.Components.Schemas["AuditAction"] = &openapi.Schema{
+		Type: "string",
+		Enum: []interface{}{string(domain.AuditActionCreate), string(domain.AuditActionUpdate), string(domain.AuditActionDelete), string(domain.AuditActionRevert), string(domain.AuditActionRestore)},
+	}
+
+	doc.Components.Schemas["TrashedBook"] = &openapi.Schema{
+		AllOf: []*openapi.Schema{
+			openapi.Ref("Book"),
+			{
+				Required: []string{"deleted_at", "deleted_by"},
+				Properties: map[string]*openapi.Schema{
+					"deleted_at": {Type: "string", Format: "date-time"},
+					"deleted_by": {Type: "string", Description: "Subject of the caller who deleted the book, or " + domain.ActorAnonymous},
+				},
+			},
+		},
+	}
+
+	doc.Components.Schemas["BookRevision"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"revision", "time", "action", "book"},
+		Properties: map[string]*openapi.Schema{
+			"revision": {Type: "integer", Minimum: openapi.Float(1)},
+			"time":     {Type: "string", Format: "date-time"},
+			"action":   openapi.Ref("AuditAction"),
+			"book":     {AllOf: []*openapi.Schema{openapi.Ref("Book")}, Description: "The book after the change, or as it was deleted"},
+		},
+	}
+
+	doc.Components.Schemas["AuditEntry"] = &openapi.Schema{
+		Type:     "object",
+		Required: []string{"id", "time", "action", "book_id", "actor", "changes"},
+		Properties: map[string]*openapi.Schema{
+			"id":          {Type: "string", Format: "uuid"},
+			"time":        {Type: "string", Format: "date-time"},
+			"action":      openapi.Ref("AuditAction"),
+			"book_id":     {Type: "string", Format: "uuid"},
+			"actor":       {Type: "string", Description: "Subject of the caller, or " + domain.ActorAnonymous},
+			"auth_method": {Type: "string", Enum: []interface{}{domain.AuthMethodJWT, domain.AuthMethodAPIKey}},
+			"request_id":  {Type: "string"},
+			"changes": {
+				Type: "array",
+				Items: &openapi.Schema{
+					Type:     "object",
+					Required: []string{"field", "before", "after"},
+					Properties: map[string]*openapi.Schema{
+						"field":  {Type: "string", Description: "JSON name of the book field", Example: "title"},
+						"before": {Description: "Value before the change, null when the book was created"},
+						"after":  {Description: "Value after the change, null when the book was deleted"},
+					},
+				},
+			},
+		},
+	}
+
+	doc.Components.Parameters["bookID"] = &openapi.Parameter{
+		Name:     "id",
+		In:       "path",
+		Required: true,
+		Schema:   &openapi.Schema{Type: "string", Format: "uuid"},
+	}
+}
+
+func addBookOperations(doc *openapi.Document, path string) {
+	tags := []string{"books"}
+	bookBody := &openapi.RequestBody{
+		Required: true,
+		Content:  openapi.JSON(openapi.Ref("Book")),
+	}
+
+	doc.AddOperation(http.MethodGet, path+"/", &openapi.Operation{
+		OperationID: "fetchBooks",
+		Security:    readSecurity,
+		Summary:     "Fetch the books matching every given filter",
+		Tags:        tags,
+		Parameters: []*openapi.Parameter{
+			{Name: "title", In: "query", Description: "Part of the title, case-insensitive", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "author", In: "query", Description: "Part of the author, case-insensitive", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "author_id", In: "query", Description: "One of the contributors, in any role", Schema: &openapi.Schema{Type: "string", Format: "uuid"}},
+			{Name: "isbn", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "publisher", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "publisher_id", In: "query", Description: "The publisher itself, not its imprints", Schema: &openapi.Schema{Type: "string", Format: "uuid"}},
+			{Name: "series_id", In: "query", Description: "The books of a series, in reading order", Schema: &openapi.Schema{Type: "string", Format: "uuid"}},
+			{Name: "work_id", In: "query", Description: "The editions of a work", Schema: &openapi.Schema{Type: "string", Format: "uuid"}},
+			{Name: "language", In: "query", Description: "BCP 47 language tag", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "format", In: "query", Schema: openapi.Ref("BookFormat")},
+			{Name: "genre", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "subject", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "published_after", In: "query", Description: "Books dated wholly after, eg. 1999 selects 2000 on", Schema: openapi.Ref("PublicationDate")},
+			{Name: "published_before", In: "query", Description: "Books dated wholly before, eg. 2000 selects up to 1999", Schema: openapi.Ref("PublicationDate")},
+		},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The list of books", &openapi.Schema{Type: "array", Items: openapi.Ref("Book")}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.NotFound),
+	})
+
+	doc.AddOperation(http.MethodPost, path+"/", &openapi.Operation{
+		OperationID: "createBook",
+		Security:    writeSecurity,
+		Summary:     "Create a new book",
+		Tags:        tags,
+		RequestBody: bookBody,
+		Responses: withErrors(map[string]*openapi.Response{
+			"201": success("The created book", openapi.Ref("Book")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.Conflict, apperror.PayloadTooLarge, apperror.UnsupportedMediaType),
+	})
+
+	doc.AddOperation(http.MethodGet, path+"/:id", &openapi.Operation{
+		OperationID: "getBookByID",
+		Security:    readSecurity,
+		Summary:     "Fetch a book by its ID, as it is with its neighbours in each series, or as it was at a point in time",
+		Tags:        tags,
+		Parameters: []*openapi.Parameter{
+			openapi.ParameterRef("bookID"),
+			{Name: "as_of", In: "query", Description: "Return the book as it was at this time, deleted books included", Schema: &openapi.Schema{Type: "string", Format: "date-time"}},
+		},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The requested book", openapi.Ref("Book")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound),
+	})
+
+	doc.AddOperation(http.MethodGet, path+"/isbn/:isbn", &openapi.Operation{
+		OperationID: "getBookByISBN",
+		Security:    readSecurity,
+		Summary:     "Fetch a book by its ISBN-10 or ISBN-13",
+		Tags:        tags,
+		Parameters: []*openapi.Parameter{{
+			Name:     "isbn",
+			In:       "path",
+			Required: true,
+			Schema:   &openapi.Schema{Type: "string", Pattern: "^[0-9Xx -]{10,17}$"},
+		}},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The book with the ISBN", openapi.Ref("Book")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.NotFound),
+	})
+
+	doc.AddOperation(http.MethodPut, path+"/:id", &openapi.Operation{
+		OperationID: "updateBook",
+		Security:    writeSecurity,
+		Summary:     "Update a book by its ID",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{openapi.ParameterRef("bookID")},
+		RequestBody: bookBody,
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The updated book", openapi.Ref("Book")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.NotFound, apperror.Conflict, apperror.PayloadTooLarge, apperror.UnsupportedMediaType),
+	})
+
+	doc.AddOperation(http.MethodDelete, path+"/:id", &openapi.Operation{
+		OperationID: "deleteBook",
+		Security:    writeSecurity,
+		Summary:     "Move a book without active copies to the trash",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{openapi.ParameterRef("bookID")},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The book was deleted", &openapi.Schema{Type: "null"}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound, apperror.Conflict),
+	})
+
+	doc.AddOperation(http.MethodGet, path+"/trash", &openapi.Operation{
+		OperationID: "fetchTrash",
+		Security:    writeSecurity,
+		Summary:     "List the deleted books which were not purged yet, most recently deleted first",
+		Tags:        tags,
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The trashed books", &openapi.Schema{Type: "array", Items: openapi.Ref("TrashedBook")}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests),
+	})
+
+	doc.AddOperation(http.MethodPost, path+"/:id/restore", &openapi.Operation{
+		OperationID: "restoreBook",
+		Security:    writeSecurity,
+		Summary:     "Take a book out of the trash",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{openapi.ParameterRef("bookID")},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The restored book", openapi.Ref("Book")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.Conflict, apperror.NotFound),
+	})
+
+	doc.AddOperation(http.MethodGet, path+"/:id/history", &openapi.Operation{
+		OperationID: "fetchBookHistory",
+		Security:    readSecurity,
+		Summary:     "List the revisions of a book, deleted books included, oldest first",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{openapi.ParameterRef("bookID")},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The revisions of the book", &openapi.Schema{Type: "array", Items: openapi.Ref("BookRevision")}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound),
+	})
+
+	doc.AddOperation(http.MethodPost, path+"/:id/revert/:revision", &openapi.Operation{
+		OperationID: "revertBook",
+		Security:    writeSecurity,
+		Summary:     "Restore a book, trashed or not, to the state of a revision",
+		Tags:        tags,
+		Parameters: []*openapi.Parameter{
+			openapi.ParameterRef("bookID"),
+			{Name: "revision", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer", Minimum: openapi.Float(1)}},
+		},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The restored book", openapi.Ref("Book")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.Conflict, apperror.NotFound),
+	})
+}
+
+func addAuthorOperations(doc *openapi.Document) {
+	tags := []string{"authors"}
+	authorID := &openapi.Parameter{
+		Name:     "id",
+		In:       "path",
+		Required: true,
+		Schema:   &openapi.Schema{Type: "string", Format: "uuid"},
+	}
+	authorBody := &openapi.RequestBody{
+		Required: true,
+		Content:  openapi.JSON(openapi.Ref("Author")),
+	}
+
+	doc.AddOperation(http.MethodGet, AuthorsPath+"/", &openapi.Operation{
+		OperationID: "fetchAuthors",
+		Security:    readSecurity,
+		Summary:     "List the authors by name",
+		Tags:        tags,
+		Parameters: []*openapi.Parameter{
+			{Name: "name", In: "query", Description: "Part of the name, case-insensitive", Schema: &openapi.Schema{Type: "string"}},
+		},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The matching authors", &openapi.Schema{Type: "array", Items: openapi.Ref("Author")}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests),
+	})
+
+	doc.AddOperation(http.MethodPost, AuthorsPath+"/", &openapi.Operation{
+		OperationID: "createAuthor",
+		Security:    writeSecurity,
+		Summary:     "Create an author, names differing only in case, spacing or punctuation conflict",
+		Tags:        tags,
+		RequestBody: authorBody,
+		Responses: withErrors(map[string]*openapi.Response{
+			"201": success("The created author", openapi.Ref("Author")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.Conflict, apperror.PayloadTooLarge, apperror.UnsupportedMediaType),
+	})
+
+	doc.AddOperation(http.MethodPost, AuthorsPath+"/migrate", &openapi.Operation{
+		OperationID: "migrateAuthors",
+		Security:    writeSecurity,
+		Summary:     "Link the books without contributors to the authors named by their author string, creating the missing authors",
+		Tags:        tags,
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("What was migrated", openapi.Ref("AuthorMigration")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.Conflict),
+	})
+
+	doc.AddOperation(http.MethodGet, AuthorsPath+"/:id", &openapi.Operation{
+		OperationID: "getAuthorByID",
+		Security:    readSecurity,
+		Summary:     "Fetch an author by its ID",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{authorID},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The requested author", openapi.Ref("Author")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound),
+	})
+
+	doc.AddOperation(http.MethodPut, AuthorsPath+"/:id", &openapi.Operation{
+		OperationID: "updateAuthor",
+		Security:    writeSecurity,
+		Summary:     "Update an author by its ID",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{authorID},
+		RequestBody: authorBody,
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The updated author", openapi.Ref("Author")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.NotFound, apperror.Conflict, apperror.PayloadTooLarge, apperror.UnsupportedMediaType),
+	})
+
+	doc.AddOperation(http.MethodDelete, AuthorsPath+"/:id", &openapi.Operation{
+		OperationID: "deleteAuthor",
+		Security:    writeSecurity,
+		Summary:     "Delete an author no book, trashed or not, is linked to",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{authorID},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The author was deleted", &openapi.Schema{Type: "null"}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound, apperror.Conflict),
+	})
+
+	doc.AddOperation(http.MethodGet, AuthorsPath+"/:id/books", &openapi.Operation{
+		OperationID: "fetchAuthorBooks",
+		Security:    readSecurity,
+		Summary:     "List the books an author contributed to, in any role",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{authorID},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The books of the author", &openapi.Schema{Type: "array", Items: openapi.Ref("Book")}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound),
+	})
+}
+
+func addSeriesOperations(doc *openapi.Document) {
+	tags := []string{"series"}
+	seriesID := &openapi.Parameter{
+		Name:     "id",
+		In:       "path",
+		Required: true,
+		Schema:   &openapi.Schema{Type: "string", Format: "uuid"},
+	}
+	seriesBody := &openapi.RequestBody{
+		Required: true,
+		Content:  openapi.JSON(openapi.Ref("Series")),
+	}
+
+	doc.AddOperation(http.MethodGet, SeriesPath+"/", &openapi.Operation{
+		OperationID: "fetchSeries",
+		Security:    readSecurity,
+		Summary:     "List the series by name",
+		Tags:        tags,
+		Parameters: []*openapi.Parameter{
+			{Name: "name", In: "query", Description: "Part of the name, case-insensitive", Schema: &openapi.Schema{Type: "string"}},
+		},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The matching series", &openapi.Schema{Type: "array", Items: openapi.Ref("Series")}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests),
+	})
+
+	doc.AddOperation(http.MethodPost, SeriesPath+"/", &openapi.Operation{
+		OperationID: "createSeries",
+		Security:    writeSecurity,
+		Summary:     "Create a series, names differing only in case conflict",
+		Tags:        tags,
+		RequestBody: seriesBody,
+		Responses: withErrors(map[string]*openapi.Response{
+			"201": success("The created series", openapi.Ref("Series")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.Conflict, apperror.PayloadTooLarge, apperror.UnsupportedMediaType),
+	})
+
+	doc.AddOperation(http.MethodGet, SeriesPath+"/:id", &openapi.Operation{
+		OperationID: "getSeriesByID",
+		Security:    readSecurity,
+		Summary:     "Fetch a series by its ID",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{seriesID},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The requested series", openapi.Ref("Series")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound),
+	})
+
+	doc.AddOperation(http.MethodPut, SeriesPath+"/:id", &openapi.Operation{
+		OperationID: "updateSeries",
+		Security:    writeSecurity,
+		Summary:     "Update a series by its ID",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{seriesID},
+		RequestBody: seriesBody,
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The updated series", openapi.Ref("Series")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.NotFound, apperror.Conflict, apperror.PayloadTooLarge, apperror.UnsupportedMediaType),
+	})
+
+	doc.AddOperation(http.MethodDelete, SeriesPath+"/:id", &openapi.Operation{
+		OperationID: "deleteSeries",
+		Security:    writeSecurity,
+		Summary:     "Delete a series no book, trashed or not, belongs to",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{seriesID},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The series was deleted", &openapi.Schema{Type: "null"}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound, apperror.Conflict),
+	})
+
+	doc.AddOperation(http.MethodGet, SeriesPath+"/:id/books", &openapi.Operation{
+		OperationID: "fetchSeriesBooks",
+		Security:    readSecurity,
+		Summary:     "List the books of a series in reading order",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{seriesID},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The books of the series, in reading order", &openapi.Schema{Type: "array", Items: openapi.Ref("Book")}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound),
+	})
+}
+
+func addPublisherOperations(doc *openapi.Document) {
+	tags := []string{"publishers"}
+	publisherID := &openapi.Parameter{
+		Name:     "id",
+		In:       "path",
+		Required: true,
+		Schema:   &openapi.Schema{Type: "string", Format: "uuid"},
+	}
+	publisherBody := &openapi.RequestBody{
+		Required: true,
+		Content:  openapi.JSON(openapi.Ref("Publisher")),
+	}
+
+	doc.AddOperation(http.MethodGet, PublishersPath+"/", &openapi.Operation{
+		OperationID: "fetchPublishers",
+		Security:    readSecurity,
+		Summary:     "List the publishers by name",
+		Tags:        tags,
+		Parameters: []*openapi.Parameter{
+			{Name: "name", In: "query", Description: "Part of the name, case-insensitive", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "parent_id", In: "query", Description: "The imprints of a publisher", Schema: &openapi.Schema{Type: "string", Format: "uuid"}},
+		},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The matching publishers", &openapi.Schema{Type: "array", Items: openapi.Ref("Publisher")}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests),
+	})
+
+	doc.AddOperation(http.MethodPost, PublishersPath+"/", &openapi.Operation{
+		OperationID: "createPublisher",
+		Security:    writeSecurity,
+		Summary:     "Create a publisher, or an imprint of the publisher given as parent",
+		Tags:        tags,
+		RequestBody: publisherBody,
+		Responses: withErrors(map[string]*openapi.Response{
+			"201": success("The created publisher", openapi.Ref("Publisher")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.Conflict, apperror.PayloadTooLarge, apperror.UnsupportedMediaType),
+	})
+
+	doc.AddOperation(http.MethodGet, PublishersPath+"/:id", &openapi.Operation{
+		OperationID: "getPublisherByID",
+		Security:    readSecurity,
+		Summary:     "Fetch a publisher by its ID",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{publisherID},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The requested publisher", openapi.Ref("Publisher")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound),
+	})
+
+	doc.AddOperation(http.MethodPut, PublishersPath+"/:id", &openapi.Operation{
+		OperationID: "updatePublisher",
+		Security:    writeSecurity,
+		Summary:     "Update a publisher by its ID",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{publisherID},
+		RequestBody: publisherBody,
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The updated publisher", openapi.Ref("Publisher")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.NotFound, apperror.Conflict, apperror.PayloadTooLarge, apperror.UnsupportedMediaType),
+	})
+
+	doc.AddOperation(http.MethodDelete, PublishersPath+"/:id", &openapi.Operation{
+		OperationID: "deletePublisher",
+		Security:    writeSecurity,
+		Summary:     "Delete a publisher without imprints or books, or with them when cascading",
+		Tags:        tags,
+		Parameters: []*openapi.Parameter{
+			publisherID,
+			{Name: "cascade", In: "query", Description: "Also delete the imprints and move the books to the trash", Schema: &openapi.Schema{Type: "boolean"}},
+		},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The publisher was deleted", &openapi.Schema{Type: "null"}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound, apperror.Conflict),
+	})
+
+	doc.AddOperation(http.MethodGet, PublishersPath+"/:id/books", &openapi.Operation{
+		OperationID: "fetchPublisherBooks",
+		Security:    readSecurity,
+		Summary:     "List the books of a publisher and of its imprints",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{publisherID},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The books of the publisher", &openapi.Schema{Type: "array", Items: openapi.Ref("Book")}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound),
+	})
+}
+
+func addWorkOperations(doc *openapi.Document) {
+	tags := []string{"works"}
+	workID := &openapi.Parameter{
+		Name:     "id",
+		In:       "path",
+		Required: true,
+		Schema:   &openapi.Schema{Type: "string", Format: "uuid"},
+	}
+	workBody := &openapi.RequestBody{
+		Required: true,
+		Content:  openapi.JSON(openapi.Ref("Work")),
+	}
+
+	doc.AddOperation(http.MethodGet, WorksPath+"/", &openapi.Operation{
+		OperationID: "fetchWorks",
+		Security:    readSecurity,
+		Summary:     "List the works by title",
+		Tags:        tags,
+		Parameters: []*openapi.Parameter{
+			{Name: "title", In: "query", Description: "Part of the title, case-insensitive", Schema: &openapi.Schema{Type: "string"}},
+		},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The matching works", &openapi.Schema{Type: "array", Items: openapi.Ref("Work")}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests),
+	})
+
+	doc.AddOperation(http.MethodPost, WorksPath+"/", &openapi.Operation{
+		OperationID: "createWork",
+		Security:    writeSecurity,
+		Summary:     "Create a work, books join it by their work_id",
+		Tags:        tags,
+		RequestBody: workBody,
+		Responses: withErrors(map[string]*openapi.Response{
+			"201": success("The created work", openapi.Ref("Work")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.PayloadTooLarge, apperror.UnsupportedMediaType),
+	})
+
+	doc.AddOperation(http.MethodGet, WorksPath+"/suggestions", &openapi.Operation{
+		OperationID: "suggestWorks",
+		Security:    readSecurity,
+		Summary:     "Suggest groups of books by the same author with alike titles, which are not editions of one work yet",
+		Tags:        tags,
+		Parameters: []*openapi.Parameter{
+			{
+				Name:        "min_score",
+				In:          "query",
+				Description: "Title similarity from which books are grouped, from 0 to 1",
+				Schema:      &openapi.Schema{Type: "number", Minimum: openapi.Float(0), Maximum: openapi.Float(1), Example: domain.DefaultWorkSuggestionScore},
+			},
+		},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The suggested groups, most alike first", &openapi.Schema{Type: "array", Items: openapi.Ref("WorkSuggestion")}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest),
+	})
+
+	doc.AddOperation(http.MethodGet, WorksPath+"/:id", &openapi.Operation{
+		OperationID: "getWorkByID",
+		Security:    readSecurity,
+		Summary:     "Fetch a work by its ID",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{workID},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The requested work", openapi.Ref("Work")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound),
+	})
+
+	doc.AddOperation(http.MethodPut, WorksPath+"/:id", &openapi.Operation{
+		OperationID: "updateWork",
+		Security:    writeSecurity,
+		Summary:     "Update a work by its ID",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{workID},
+		RequestBody: workBody,
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The updated work", openapi.Ref("Work")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.NotFound, apperror.PayloadTooLarge, apperror.UnsupportedMediaType),
+	})
+
+	doc.AddOperation(http.MethodDelete, WorksPath+"/:id", &openapi.Operation{
+		OperationID: "deleteWork",
+		Security:    writeSecurity,
+		Summary:     "Delete a work no book, trashed or not, is an edition of",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{workID},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The work was deleted", &openapi.Schema{Type: "null"}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound, apperror.Conflict),
+	})
+
+	doc.AddOperation(http.MethodGet, WorksPath+"/:id/editions", &openapi.Operation{
+		OperationID: "fetchEditions",
+		Security:    readSecurity,
+		Summary:     "List the editions of a work by publication date",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{workID},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The editions of the work", &openapi.Schema{Type: "array", Items: openapi.Ref("Book")}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound),
+	})
+
+	doc.AddOperation(http.MethodPost, WorksPath+"/:id/merge", &openapi.Operation{
+		OperationID: "mergeWorks",
+		Security:    writeSecurity,
+		Summary:     "Move the editions of other works to this one and delete them",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{workID},
+		RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(openapi.Ref("WorkMerge"))},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The work the others were merged into", openapi.Ref("Work")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.NotFound, apperror.Conflict, apperror.PayloadTooLarge, apperror.UnsupportedMediaType),
+	})
+
+	doc.AddOperation(http.MethodPost, WorksPath+"/:id/split", &openapi.Operation{
+		OperationID: "splitWork",
+		Security:    writeSecurity,
+		Summary:     "Move editions of this work to a new work",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{workID},
+		RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(openapi.Ref("WorkSplit"))},
+		Responses: withErrors(map[string]*openapi.Response{
+			"201": success("The new work", openapi.Ref("Work")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.NotFound, apperror.Conflict, apperror.PayloadTooLarge, apperror.UnsupportedMediaType),
+	})
+}
+
+func addCopyOperations(doc *openapi.Document, booksPath string) {
+	tags := []string{"copies"}
+	path := booksPath + "/:id/copies"
+	copyID := &openapi.Parameter{
+		Name:     "copy_id",
+		In:       "path",
+		Required: true,
+		Schema:   &openapi.Schema{Type: "string", Format: "uuid"},
+	}
+	copyBody := &openapi.RequestBody{
+		Required: true,
+		Content:  openapi.JSON(openapi.Ref("Copy")),
+	}
+
+	doc.AddOperation(http.MethodGet, path+"/", &openapi.Operation{
+		OperationID: "fetchCopies",
+		Security:    readSecurity,
+		Summary:     "List the copies of a book by barcode",
+		Tags:        tags,
+		Parameters: []*openapi.Parameter{
+			openapi.ParameterRef("bookID"),
+			{Name: "status", In: "query", Schema: openapi.Ref("CopyStatus")},
+		},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The copies of the book", &openapi.Schema{Type: "array", Items: openapi.Ref("Copy")}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.NotFound),
+	})
+
+	doc.AddOperation(http.MethodPost, path+"/", &openapi.Operation{
+		OperationID: "createCopy",
+		Security:    writeSecurity,
+		Summary:     "Add a copy of a book",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{openapi.ParameterRef("bookID")},
+		RequestBody: copyBody,
+		Responses: withErrors(map[string]*openapi.Response{
+			"201": success("The created copy", openapi.Ref("Copy")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.NotFound, apperror.Conflict, apperror.PayloadTooLarge, apperror.UnsupportedMediaType),
+	})
+
+	doc.AddOperation(http.MethodGet, path+"/:copy_id", &openapi.Operation{
+		OperationID: "getCopyByID",
+		Security:    readSecurity,
+		Summary:     "Fetch a copy of a book by its ID",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{openapi.ParameterRef("bookID"), copyID},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The requested copy", openapi.Ref("Copy")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound),
+	})
+
+	doc.AddOperation(http.MethodPut, path+"/:copy_id", &openapi.Operation{
+		OperationID: "updateCopy",
+		Security:    writeSecurity,
+		Summary:     "Update a copy of a book by its ID, its status included",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{openapi.ParameterRef("bookID"), copyID},
+		RequestBody: copyBody,
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The updated copy", openapi.Ref("Copy")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.BadRequest, apperror.NotFound, apperror.Conflict, apperror.PayloadTooLarge, apperror.UnsupportedMediaType),
+	})
+
+	doc.AddOperation(http.MethodDelete, path+"/:copy_id", &openapi.Operation{
+		OperationID: "deleteCopy",
+		Security:    writeSecurity,
+		Summary:     "Delete a copy of a book by its ID",
+		Tags:        tags,
+		Parameters:  []*openapi.Parameter{openapi.ParameterRef("bookID"), copyID},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The copy was deleted", &openapi.Schema{Type: "null"}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound),
+	})
+
+	doc.AddOperation(http.MethodGet, CopiesPath+"/barcode/:code", &openapi.Operation{
+		OperationID: "getCopyByBarcode",
+		Security:    readSecurity,
+		Summary:     "Fetch a copy by its barcode, case-insensitive",
+		Tags:        tags,
+		Parameters: []*openapi.Parameter{
+			{Name: "code", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", MinLength: openapi.Int(1), MaxLength: openapi.Int(64)}},
+		},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The copy with the barcode", openapi.Ref("Copy")),
+		}, apperror.Authorization, apperror.Forbidden, apperror.TooManyRequests, apperror.NotFound),
+	})
+}
+
+func addAPIKeyOperations(doc *openapi.Document) {
+	tags := []string{"admin"}
+	security := []openapi.SecurityRequirement{{"bearerAuth": {}}}
+
+	doc.AddOperation(http.MethodGet, APIKeysPath+"/", &openapi.Operation{
+		OperationID: "fetchAPIKeys",
+		Summary:     "List the API keys, revoked ones included",
+		Tags:        tags,
+		Security:    security,
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The list of API keys", &openapi.Schema{Type: "array", Items: openapi.Ref("APIKey")}),
+		}, apperror.Authorization),
+	})
+
+	doc.AddOperation(http.MethodPost, APIKeysPath+"/", &openapi.Operation{
+		OperationID: "issueAPIKey",
+		Summary:     "Issue an API key, the key is only returned once",
+		Tags:        tags,
+		Security:    security,
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: openapi.JSON(&openapi.Schema{
+				Type:     "object",
+				Required: []string{"name", "scopes"},
+				Properties: map[string]*openapi.Schema{
+					"name":   {Type: "string", MinLength: openapi.Int(1), Example: "nightly-import"},
+					"scopes": {Type: "array", MinItems: openapi.Int(1), Items: openapi.Ref("APIKeyScope")},
+				},
+			}),
+		},
+		Responses: withErrors(map[string]*openapi.Response{
+			"201": success("The issued API key", &openapi.Schema{
+				AllOf: []*openapi.Schema{
+					openapi.Ref("APIKey"),
+					{
+						Required:   []string{"key"},
+						Properties: map[string]*openapi.Schema{"key": {Type: "string"}},
+					},
+				},
+			}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.BadRequest, apperror.PayloadTooLarge, apperror.UnsupportedMediaType),
+	})
+
+	doc.AddOperation(http.MethodDelete, APIKeysPath+"/:id", &openapi.Operation{
+		OperationID: "revokeAPIKey",
+		Summary:     "Revoke an API key",
+		Tags:        tags,
+		Security:    security,
+		Parameters: []*openapi.Parameter{{
+			Name:     "id",
+			In:       "path",
+			Required: true,
+			Schema:   &openapi.Schema{Type: "string", Format: "uuid"},
+		}},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The API key was revoked", &openapi.Schema{Type: "null"}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.NotFound),
+	})
+}
+
+func addAuditOperations(doc *openapi.Document) {
+	doc.AddOperation(http.MethodGet, AuditPath+"/", &openapi.Operation{
+		OperationID: "fetchAuditEntries",
+		Summary:     "List the changes made to the catalog, oldest first",
+		Tags:        []string{"admin"},
+		Security:    []openapi.SecurityRequirement{{"bearerAuth": {}}},
+		Parameters: []*openapi.Parameter{
+			{Name: "book_id", In: "query", Schema: &openapi.Schema{Type: "string", Format: "uuid"}},
+			{Name: "actor", In: "query", Description: "Subject of the caller", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "from", In: "query", Description: "Earliest time, inclusive", Schema: &openapi.Schema{Type: "string", Format: "date-time"}},
+			{Name: "to", In: "query", Description: "Latest time, exclusive", Schema: &openapi.Schema{Type: "string", Format: "date-time"}},
+		},
+		Responses: withErrors(map[string]*openapi.Response{
+			"200": success("The matching audit entries", &openapi.Schema{Type: "array", Items: openapi.Ref("AuditEntry")}),
+		}, apperror.Authorization, apperror.Forbidden, apperror.BadRequest),
+	})
+}
+
+// problemSchema describes the Problem Details of an apperror.Type
+func problemSchema(t apperror.Type) *openapi.Schema {
+	return &openapi.Schema{
+		AllOf: []*openapi.Schema{
+			openapi.Ref("ProblemDetails"),
+			{Properties: map[string]*openapi.Schema{
+				"type":   {Const: response.ProblemType(t)},
+				"status": {Const: (&apperror.Error{Type: t}).Status()},
+			}},
+		},
+	}
+}
+
+// success describes a successResponse envelope carrying data
+func success(description string, data *openapi.Schema) *openapi.Response {
+	return &openapi.Response{
+		Description: description,
+		Content: openapi.JSON(&openapi.Schema{
+			AllOf: []*openapi.Schema{
+				openapi.Ref("successResponse"),
+				{Properties: map[string]*openapi.Schema{"data": data}},
+			},
+		}),
+	}
+}
+
+// withErrors adds the responses of the given error types to responses,
+// every route may additionally time out or fail internally
+func withErrors(responses map[string]*openapi.Response, types ...apperror.Type) map[string]*openapi.Response {
+	types = append(types, apperror.ServiceUnavailable, apperror.Internal)
+	for _, t := range types {
+		status := strconv.Itoa((&apperror.Error{Type: t}).Status())
+		responses[status] = openapi.ResponseRef(errorResponses[t])
+	}
+
+	return responses
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/openapi"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIDocument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Every route is described", func(t *testing.T) {
+		router := gin.New()
+		NewBookHandler(router, new(appmock.MockBookUseCase), "/books", time.Second)
+		NewAPIKeyHandler(router, new(appmock.MockAPIKeyUseCase), time.Second)
+		NewAuditHandler(router, new(appmock.MockAuditUseCase), time.Second)
+		NewAuthorHandler(router, new(appmock.MockAuthorUseCase), time.Second)
+		NewPublisherHandler(router, new(appmock.MockPublisherUseCase), time.Second)
+		NewSeriesHandler(router, new(appmock.MockSeriesUseCase), time.Second)
+		NewWorkHandler(router, new(appmock.MockWorkUseCase), time.Second)
+		NewCopyHandler(router, new(appmock.MockCopyUseCase), "/books", time.Second)
+
+		doc := NewOpenAPIDocument("/books")
+
+		for _, route := range router.Routes() {
+			assert.NotNil(t, doc.Operation(route.Method, route.Path), "%s %s is missing from the OpenAPI document", route.Method, route.Path)
+		}
+	})
+
+	t.Run("Every operation is routed", func(t *testing.T) {
+		router := gin.New()
+		NewBookHandler(router, new(appmock.MockBookUseCase), "/books", time.Second)
+		NewAPIKeyHandler(router, new(appmock.MockAPIKeyUseCase), time.Second)
+		NewAuditHandler(router, new(appmock.MockAuditUseCase), time.Second)
+		NewAuthorHandler(router, new(appmock.MockAuthorUseCase), time.Second)
+		NewPublisherHandler(router, new(appmock.MockPublisherUseCase), time.Second)
+		NewSeriesHandler(router, new(appmock.MockSeriesUseCase), time.Second)
+		NewWorkHandler(router, new(appmock.MockWorkUseCase), time.Second)
+		NewCopyHandler(router, new(appmock.MockCopyUseCase), "/books", time.Second)
+
+		routes := map[string]bool{}
+		for _, route := range router.Routes() {
+			routes[route.Method+" "+openapi.PathFromGin(route.Path)] = true
+		}
+
+		doc := NewOpenAPIDocument("/books")
+
+		for path, item := range doc.Paths {
+			for _, method := range item.Methods() {
+				assert.True(t, routes[method+" "+path], "%s %s is described but not routed", method, path)
+			}
+		}
+	})
+}
+
+func TestDocsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	NewDocsHandler(router, NewOpenAPIDocument("/books"))
+
+	t.Run("Spec", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/openapi.json", nil)
+
+		router.ServeHTTP(w, req)
+
+		var doc openapi.Document
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &doc))
+		assert.Equal(t, openapi.Version, doc.OpenAPI)
+		assert.NotNil(t, doc.Paths["/books/{id}"])
+	})
+
+	t.Run("Swagger UI", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/docs/index.html", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	})
+
+	t.Run("Swagger UI - initializer points at the spec", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/docs/swagger-initializer.js", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"/openapi.json"`)
+	})
+
+	t.Run("Swagger UI - missing file", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/docs/missing.js", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+// PublishersPath is where the publishers are served
+const PublishersPath = "/publishers"
+
+type PublisherHandler struct {
+	PublisherUseCase domain.PublisherUseCase
+	RouteMiddleware
+}
+
+// NewPublisherHandler registers the publisher routes, with the read and
+// write middleware of opts as for the books
+func NewPublisherHandler(router *gin.Engine, pu domain.PublisherUseCase, timeout time.Duration, opts ...Option) *PublisherHandler {
+	handler := &PublisherHandler{
+		PublisherUseCase: pu,
+		RouteMiddleware:  newRouteMiddleware(opts),
+	}
+
+	read, write := handler.groups(router, PublishersPath, timeout)
+	// setup routes
+	read.GET("/", handler.FetchPublishers)
+	write.POST("/", handler.CreatePublisher)
+	read.GET("/:id", handler.GetPublisherByID)
+	write.PUT("/:id", handler.UpdatePublisher)
+	write.DELETE("/:id", handler.DeletePublisher)
+	read.GET("/:id/books", handler.FetchPublisherBooks)
+
+	return handler
+}
+
+// FetchPublishers returns the publishers by name, narrowed by the name
+// and parent_id query params
+func (h *PublisherHandler) FetchPublishers(c *gin.Context) {
+	filter := domain.PublisherFilter{Name: c.Query("name")}
+	if parentID := c.Query("parent_id"); parentID != "" {
+		id, err := uuid.Parse(parentID)
+		if err != nil {
+			response.Error(c, apperror.NewValidation([]apperror.FieldError{
+				apperror.NewFieldError("query", "parent_id", "/parent_id", "format", parentID, apperror.Params{"param": "uuid"}),
+			}))
+			return
+		}
+		filter.ParentID = id
+	}
+
+	publishers, err := h.PublisherUseCase.FetchPublishers(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, publishers)
+}
+
+func (h *PublisherHandler) CreatePublisher(c *gin.Context) {
+	var publisher domain.Publisher
+	if ok := bindData(c, &publisher); !ok {
+		return
+	}
+
+	err := h.PublisherUseCase.CreatePublisher(c.Request.Context(), &publisher)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, publisher)
+}
+
+func (h *PublisherHandler) GetPublisherByID(c *gin.Context) {
+	id := c.Param("id")
+
+	publisher, err := h.PublisherUseCase.GetPublisherByID(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, publisher)
+}
+
+func (h *PublisherHandler) UpdatePublisher(c *gin.Context) {
+	var publisher domain.Publisher
+	if ok := bindData(c, &publisher); !ok {
+		return
+	}
+
+	id := c.Param("id")
+	err := h.PublisherUseCase.UpdatePublisher(c.Request.Context(), id, &publisher)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, publisher)
+}
+
+// DeletePublisher deletes a publisher without imprints or books, or with
+// them when the cascade query param is true
+func (h *PublisherHandler) DeletePublisher(c *gin.Context) {
+	id := c.Param("id")
+
+	cascade := false
+	if value := c.Query("cascade"); value != "" {
+		var err error
+		if cascade, err = strconv.ParseBool(value); err != nil {
+			response.Error(c, apperror.NewValidation([]apperror.FieldError{
+				apperror.NewFieldError("query", "cascade", "/cascade", "type", value, apperror.Params{"type": "boolean"}),
+			}))
+			return
+		}
+	}
+
+	err := h.PublisherUseCase.DeletePublisher(c.Request.Context(), id, cascade)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil)
+}
+
+func (h *PublisherHandler) FetchPublisherBooks(c *gin.Context) {
+	id := c.Param("id")
+
+	books, err := h.PublisherUseCase.FetchPublisherBooks(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, books)
+}
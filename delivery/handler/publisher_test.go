@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/delivery/middleware"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPublisherHandler(t *testing.T) {
+	// setup
+	gin.SetMode(gin.TestMode)
+
+	parent := domain.Publisher{ID: uuid.New(), Name: "Penguin Books"}
+	publisher := domain.Publisher{ID: uuid.New(), Name: "Puffin", ParentID: &parent.ID}
+	id := publisher.ID.String()
+	unknown := uuid.New()
+
+	tests := []struct {
+		name     string
+		validate bool
+		method   string
+		path     string
+		body     string
+		setup    func(m *appmock.MockPublisherUseCase)
+		code     int
+		want     string // a fragment of the response body
+	}{
+		{
+			name:     "Fetch imprints",
+			validate: true,
+			method:   http.MethodGet,
+			path:     "/publishers/?parent_id=" + parent.ID.String(),
+			setup: func(m *appmock.MockPublisherUseCase) {
+				m.On("FetchPublishers", mock.Anything, domain.PublisherFilter{ParentID: parent.ID}).Return(&[]domain.Publisher{publisher}, nil)
+			},
+			want: `"name":"Puffin","parent_id":"` + parent.ID.String() + `"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Fetch with an invalid parent_id",
+			method: http.MethodGet,
+			path:   "/publishers/?parent_id=penguin",
+			setup:  func(m *appmock.MockPublisherUseCase) {},
+			want:   `"error_code":"invalid_fields"`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:     "Create an imprint",
+			validate: true,
+			method:   http.MethodPost,
+			path:     "/publishers/",
+			body:     `{"name":"Puffin","parent_id":"` + parent.ID.String() + `"}`,
+			setup: func(m *appmock.MockPublisherUseCase) {
+				m.On("CreatePublisher", mock.Anything, mock.MatchedBy(func(p *domain.Publisher) bool {
+					return p.ParentID != nil && *p.ParentID == parent.ID
+				})).Return(nil)
+			},
+			want: `"name":"Puffin","parent_id":"` + parent.ID.String() + `"`,
+			code: http.StatusCreated,
+		},
+		{
+			name:     "Create with an unknown parent",
+			validate: true,
+			method:   http.MethodPost,
+			path:     "/publishers/",
+			body:     `{"name":"Puffin","parent_id":"` + unknown.String() + `"}`,
+			setup: func(m *appmock.MockPublisherUseCase) {
+				m.On("CreatePublisher", mock.Anything, mock.AnythingOfType("*domain.Publisher")).Return(apperror.NewBadRequestCode("unknown_parent", apperror.Params{"parent_id": unknown}))
+			},
+			want: `"error_code":"unknown_parent"`,
+			code: http.StatusBadRequest,
+		},
+		{
+			name:     "Get",
+			validate: true,
+			method:   http.MethodGet,
+			path:     "/publishers/" + id,
+			setup: func(m *appmock.MockPublisherUseCase) {
+				m.On("GetPublisherByID", mock.Anything, id).Return(&publisher, nil)
+			},
+			want: `"data":{"id":"` + id + `","name":"Puffin"`,
+			code: http.StatusOK,
+		},
+		{
+			name:     "Update",
+			validate: true,
+			method:   http.MethodPut,
+			path:     "/publishers/" + id,
+			body:     `{"name":"Puffin Books"}`,
+			setup: func(m *appmock.MockPublisherUseCase) {
+				m.On("UpdatePublisher", mock.Anything, id, mock.AnythingOfType("*domain.Publisher")).Return(nil)
+			},
+			want: `"name":"Puffin Books"`,
+			code: http.StatusOK,
+		},
+		{
+			name:     "Delete with books",
+			validate: true,
+			method:   http.MethodDelete,
+			path:     "/publishers/" + id,
+			setup: func(m *appmock.MockPublisherUseCase) {
+				m.On("DeletePublisher", mock.Anything, id, false).Return(apperror.NewConflictCode("books_of_publisher", apperror.Params{"publisher": "Puffin"}))
+			},
+			want: `"error_code":"books_of_publisher"`,
+			code: http.StatusConflict,
+		},
+		{
+			name:     "Delete cascading",
+			validate: true,
+			method:   http.MethodDelete,
+			path:     "/publishers/" + id + "?cascade=true",
+			setup: func(m *appmock.MockPublisherUseCase) {
+				m.On("DeletePublisher", mock.Anything, id, true).Return(nil)
+			},
+			want: `"data":null`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Delete with an invalid cascade",
+			method: http.MethodDelete,
+			path:   "/publishers/" + id + "?cascade=always",
+			setup:  func(m *appmock.MockPublisherUseCase) {},
+			want:   `"error_code":"invalid_fields"`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:     "Books",
+			validate: true,
+			method:   http.MethodGet,
+			path:     "/publishers/" + id + "/books",
+			setup: func(m *appmock.MockPublisherUseCase) {
+				books := []domain.Book{{ID: uuid.New(), Title: "Matilda", Author: "Roald Dahl", PublicationDate: "1988", PublisherID: &publisher.ID}}
+				m.On("FetchPublisherBooks", mock.Anything, id).Return(&books, nil)
+			},
+			want: `"title":"Matilda"`,
+			code: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPublisherUseCase := new(appmock.MockPublisherUseCase)
+			tt.setup(mockPublisherUseCase)
+
+			router := gin.New()
+			var opts []Option
+			if tt.validate {
+				opts = append(opts, WithMiddleware(middleware.OpenAPIValidator(NewOpenAPIDocument("/books"), middleware.OpenAPIOptions{ValidateResponses: true})))
+			}
+			NewPublisherHandler(router, mockPublisherUseCase, time.Second, opts...)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code, w.Body.String())
+			assert.Contains(t, w.Body.String(), tt.want)
+			mockPublisherUseCase.AssertExpectations(t)
+		})
+	}
+}
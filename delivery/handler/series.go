@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain"
+)
+
+// SeriesPath is where the series are served
+const SeriesPath = "/series"
+
+type SeriesHandler struct {
+	SeriesUseCase domain.SeriesUseCase
+	RouteMiddleware
+}
+
+// NewSeriesHandler registers the series routes, with the read and write
+// middleware of opts as for the books
+func NewSeriesHandler(router *gin.Engine, su domain.SeriesUseCase, timeout time.Duration, opts ...Option) *SeriesHandler {
+	handler := &SeriesHandler{
+		SeriesUseCase:   su,
+		RouteMiddleware: newRouteMiddleware(opts),
+	}
+
+	read, write := handler.groups(router, SeriesPath, timeout)
+	// setup routes
+	read.GET("/", handler.FetchSeries)
+	write.POST("/", handler.CreateSeries)
+	read.GET("/:id", handler.GetSeriesByID)
+	write.PUT("/:id", handler.UpdateSeries)
+	write.DELETE("/:id", handler.DeleteSeries)
+	read.GET("/:id/books", handler.FetchSeriesBooks)
+
+	return handler
+}
+
+// FetchSeries returns the series by name, those whose name contains the
+// name query param when given
+func (h *SeriesHandler) FetchSeries(c *gin.Context) {
+	series, err := h.SeriesUseCase.FetchSeries(c.Request.Context(), domain.SeriesFilter{Name: c.Query("name")})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, series)
+}
+
+func (h *SeriesHandler) CreateSeries(c *gin.Context) {
+	var series domain.Series
+	if ok := bindData(c, &series); !ok {
+		return
+	}
+
+	err := h.SeriesUseCase.CreateSeries(c.Request.Context(), &series)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, series)
+}
+
+func (h *SeriesHandler) GetSeriesByID(c *gin.Context) {
+	id := c.Param("id")
+
+	series, err := h.SeriesUseCase.GetSeriesByID(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, series)
+}
+
+func (h *SeriesHandler) UpdateSeries(c *gin.Context) {
+	var series domain.Series
+	if ok := bindData(c, &series); !ok {
+		return
+	}
+
+	id := c.Param("id")
+	err := h.SeriesUseCase.UpdateSeries(c.Request.Context(), id, &series)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, series)
+}
+
+func (h *SeriesHandler) DeleteSeries(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.SeriesUseCase.DeleteSeries(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil)
+}
+
+// FetchSeriesBooks returns the books of a series in reading order
+func (h *SeriesHandler) FetchSeriesBooks(c *gin.Context) {
+	id := c.Param("id")
+
+	books, err := h.SeriesUseCase.FetchSeriesBooks(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, books)
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/delivery/middleware"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSeriesHandler(t *testing.T) {
+	// setup
+	gin.SetMode(gin.TestMode)
+
+	series := domain.Series{ID: uuid.New(), Name: "Discworld", Description: "Novels by Terry Pratchett"}
+	id := series.ID.String()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		setup  func(m *appmock.MockSeriesUseCase)
+		code   int
+		want   string // a fragment of the response body
+	}{
+		{
+			name:   "Fetch",
+			method: http.MethodGet,
+			path:   "/series/?name=disc",
+			setup: func(m *appmock.MockSeriesUseCase) {
+				m.On("FetchSeries", mock.Anything, domain.SeriesFilter{Name: "disc"}).Return(&[]domain.Series{series}, nil)
+			},
+			want: `"data":[{"id":"` + id + `","name":"Discworld"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			path:   "/series/",
+			body:   `{"name":"Discworld","description":"Novels by Terry Pratchett"}`,
+			setup: func(m *appmock.MockSeriesUseCase) {
+				m.On("CreateSeries", mock.Anything, mock.AnythingOfType("*domain.Series")).Return(nil)
+			},
+			want: `"name":"Discworld","description":"Novels by Terry Pratchett"`,
+			code: http.StatusCreated,
+		},
+		{
+			name:   "Create without a name",
+			method: http.MethodPost,
+			path:   "/series/",
+			body:   `{"description":"Novels by Terry Pratchett"}`,
+			setup:  func(m *appmock.MockSeriesUseCase) {},
+			want:   `"error_code":"invalid_fields"`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "Create a duplicate",
+			method: http.MethodPost,
+			path:   "/series/",
+			body:   `{"name":"discworld"}`,
+			setup: func(m *appmock.MockSeriesUseCase) {
+				m.On("CreateSeries", mock.Anything, mock.AnythingOfType("*domain.Series")).Return(apperror.NewConflict("series", "Discworld"))
+			},
+			want: `"error_code":"conflict"`,
+			code: http.StatusConflict,
+		},
+		{
+			name:   "Get",
+			method: http.MethodGet,
+			path:   "/series/" + id,
+			setup: func(m *appmock.MockSeriesUseCase) {
+				m.On("GetSeriesByID", mock.Anything, id).Return(&series, nil)
+			},
+			want: `"data":{"id":"` + id + `","name":"Discworld"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Get unknown",
+			method: http.MethodGet,
+			path:   "/series/" + id,
+			setup: func(m *appmock.MockSeriesUseCase) {
+				m.On("GetSeriesByID", mock.Anything, id).Return((*domain.Series)(nil), apperror.NewNotFound("Series", "ID", id))
+			},
+			want: `"error_code":"not_found"`,
+			code: http.StatusNotFound,
+		},
+		{
+			name:   "Update",
+			method: http.MethodPut,
+			path:   "/series/" + id,
+			body:   `{"name":"Discworld"}`,
+			setup: func(m *appmock.MockSeriesUseCase) {
+				m.On("UpdateSeries", mock.Anything, id, mock.AnythingOfType("*domain.Series")).Return(nil)
+			},
+			want: `"name":"Discworld"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Delete with books",
+			method: http.MethodDelete,
+			path:   "/series/" + id,
+			setup: func(m *appmock.MockSeriesUseCase) {
+				m.On("DeleteSeries", mock.Anything, id).Return(apperror.NewConflictCode("books_of_series", apperror.Params{"series": "Discworld"}))
+			},
+			want: `"error_code":"books_of_series"`,
+			code: http.StatusConflict,
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			path:   "/series/" + id,
+			setup: func(m *appmock.MockSeriesUseCase) {
+				m.On("DeleteSeries", mock.Anything, id).Return(nil)
+			},
+			want: `"data":null`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Books",
+			method: http.MethodGet,
+			path:   "/series/" + id + "/books",
+			setup: func(m *appmock.MockSeriesUseCase) {
+				books := []domain.Book{{
+					ID:              uuid.New(),
+					Title:           "The Colour of Magic",
+					Author:          "Terry Pratchett",
+					PublicationDate: "1983-11-24",
+					Series:          []domain.SeriesEntry{{SeriesID: series.ID, Position: 1}},
+				}}
+				m.On("FetchSeriesBooks", mock.Anything, id).Return(&books, nil)
+			},
+			want: `"title":"The Colour of Magic"`,
+			code: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSeriesUseCase := new(appmock.MockSeriesUseCase)
+			tt.setup(mockSeriesUseCase)
+
+			router := gin.New()
+			validator := middleware.OpenAPIValidator(NewOpenAPIDocument("/books"), middleware.OpenAPIOptions{ValidateResponses: true})
+			NewSeriesHandler(router, mockSeriesUseCase, time.Second, WithMiddleware(validator))
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code, w.Body.String())
+			assert.Contains(t, w.Body.String(), tt.want)
+			mockSeriesUseCase.AssertExpectations(t)
+		})
+	}
+}
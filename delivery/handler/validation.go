@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		// report fields by their JSON name instead of the Go struct field
+		v.RegisterTagNameFunc(jsonName)
+		// replaces the built-in isbn tag, which rejects hyphens
+		if err := v.RegisterValidation("isbn", isbn); err != nil {
+			panic(err)
+		}
+		if err := v.RegisterValidation("publication_date", publicationDate); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// isbn validates the checksum of an ISBN-10 or ISBN-13 string field
+func isbn(fl validator.FieldLevel) bool {
+	return domain.ValidISBN(fl.Field().String())
+}
+
+// publicationDate validates the form of a domain.PublicationDate and its
+// lower bound, the upper bound depends on the time and is left to the
+// use case
+func publicationDate(fl validator.FieldLevel) bool {
+	return domain.PublicationDate(fl.Field().String()).Valid()
+}
+
+func jsonName(f reflect.StructField) string {
+	name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	default:
+		return name
+	}
+}
+
+// bindError converts the error returned by c.ShouldBind to an apperror,
+// listing each invalid field when the body could be decoded
+func bindError(err error) *apperror.Error {
+	// the body may have been cut off by middleware.BodyLimit
+	var e *apperror.Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		fields := make([]apperror.FieldError, 0, len(verrs))
+		for _, fe := range verrs {
+			fields = append(fields, fieldError(fe))
+		}
+		return apperror.NewValidation(fields)
+	}
+
+	var terr *json.UnmarshalTypeError
+	if errors.As(err, &terr) {
+		pointer := "/" + strings.ReplaceAll(terr.Field, ".", "/")
+		return apperror.NewValidation([]apperror.FieldError{
+			apperror.NewFieldError("body", terr.Field, pointer, "type", terr.Value, apperror.Params{"type": jsonType(terr.Type)}),
+		})
+	}
+
+	return apperror.NewBadRequest(err.Error())
+}
+
+// jsonType names the JSON type a Go type is decoded from, so named types
+// such as domain.PublicationDate are reported as a string
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return t.String()
+	}
+}
+
+func fieldError(fe validator.FieldError) apperror.FieldError {
+	// the namespace starts with the struct name, eg. Book.title
+	path := fe.Namespace()
+	if i := strings.Index(path, "."); i >= 0 {
+		path = path[i+1:]
+	}
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+
+	pointer := "/" + strings.ReplaceAll(path, ".", "/")
+
+	params := apperror.Params{"param": fe.Param()}
+	// the lower bound of a publication date is not a param of its tag
+	if fe.Tag() == "publication_date" {
+		params["min"] = domain.MinPublicationYear
+	}
+
+	return apperror.NewFieldError("body", path, pointer, fe.Tag(), fe.Value(), params)
+}
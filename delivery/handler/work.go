@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+// WorksPath is where the works are served
+const WorksPath = "/works"
+
+type WorkHandler struct {
+	WorkUseCase domain.WorkUseCase
+	RouteMiddleware
+}
+
+// NewWorkHandler registers the work routes, with the read and write
+// middleware of opts as for the books
+func NewWorkHandler(router *gin.Engine, wu domain.WorkUseCase, timeout time.Duration, opts ...Option) *WorkHandler {
+	handler := &WorkHandler{
+		WorkUseCase:     wu,
+		RouteMiddleware: newRouteMiddleware(opts),
+	}
+
+	read, write := handler.groups(router, WorksPath, timeout)
+	// setup routes
+	read.GET("/", handler.FetchWorks)
+	write.POST("/", handler.CreateWork)
+	read.GET("/suggestions", handler.SuggestWorks)
+	read.GET("/:id", handler.GetWorkByID)
+	write.PUT("/:id", handler.UpdateWork)
+	write.DELETE("/:id", handler.DeleteWork)
+	read.GET("/:id/editions", handler.FetchEditions)
+	write.POST("/:id/merge", handler.MergeWorks)
+	write.POST("/:id/split", handler.SplitWork)
+
+	return handler
+}
+
+// FetchWorks returns the works by title, those whose title contains the
+// title query param when given
+func (h *WorkHandler) FetchWorks(c *gin.Context) {
+	works, err := h.WorkUseCase.FetchWorks(c.Request.Context(), domain.WorkFilter{Title: c.Query("title")})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, works)
+}
+
+func (h *WorkHandler) CreateWork(c *gin.Context) {
+	var work domain.Work
+	if ok := bindData(c, &work); !ok {
+		return
+	}
+
+	err := h.WorkUseCase.CreateWork(c.Request.Context(), &work)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, work)
+}
+
+func (h *WorkHandler) GetWorkByID(c *gin.Context) {
+	id := c.Param("id")
+
+	work, err := h.WorkUseCase.GetWorkByID(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, work)
+}
+
+func (h *WorkHandler) UpdateWork(c *gin.Context) {
+	var work domain.Work
+	if ok := bindData(c, &work); !ok {
+		return
+	}
+
+	id := c.Param("id")
+	err := h.WorkUseCase.UpdateWork(c.Request.Context(), id, &work)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, work)
+}
+
+func (h *WorkHandler) DeleteWork(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.WorkUseCase.DeleteWork(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, nil)
+}
+
+// FetchEditions returns the editions of a work by publication date
+func (h *WorkHandler) FetchEditions(c *gin.Context) {
+	id := c.Param("id")
+
+	editions, err := h.WorkUseCase.FetchEditions(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, editions)
+}
+
+// SuggestWorks returns the groups of books which look like editions of
+// one work, scoring at least the min_score query param
+func (h *WorkHandler) SuggestWorks(c *gin.Context) {
+	minScore := domain.DefaultWorkSuggestionScore
+	if value := c.Query("min_score"); value != "" {
+		var err error
+		if minScore, err = strconv.ParseFloat(value, 64); err != nil {
+			response.Error(c, apperror.NewValidation([]apperror.FieldError{
+				apperror.NewFieldError("query", "min_score", "/min_score", "type", value, apperror.Params{"type": "number"}),
+			}))
+			return
+		}
+	}
+
+	suggestions, err := h.WorkUseCase.SuggestWorks(c.Request.Context(), minScore)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, suggestions)
+}
+
+// MergeWorks moves the editions of the works of the body to the work of
+// the path, deleting them
+func (h *WorkHandler) MergeWorks(c *gin.Context) {
+	var merge domain.WorkMerge
+	if ok := bindData(c, &merge); !ok {
+		return
+	}
+
+	id := c.Param("id")
+	work, err := h.WorkUseCase.MergeWorks(c.Request.Context(), id, &merge)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, work)
+}
+
+// SplitWork moves the editions of the body out of the work of the path
+// into a new work
+func (h *WorkHandler) SplitWork(c *gin.Context) {
+	var split domain.WorkSplit
+	if ok := bindData(c, &split); !ok {
+		return
+	}
+
+	id := c.Param("id")
+	work, err := h.WorkUseCase.SplitWork(c.Request.Context(), id, &split)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, work)
+}
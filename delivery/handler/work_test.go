@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/delivery/middleware"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWorkHandler(t *testing.T) {
+	// setup
+	gin.SetMode(gin.TestMode)
+
+	work := domain.Work{ID: uuid.New(), Title: "Dune"}
+	id := work.ID.String()
+	other := uuid.New()
+	edition := domain.Book{ID: uuid.New(), Title: "Dune", Author: "Frank Herbert", PublicationDate: "1965", WorkID: &work.ID}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		setup  func(m *appmock.MockWorkUseCase)
+		code   int
+		want   string // a fragment of the response body
+	}{
+		{
+			name:   "Fetch",
+			method: http.MethodGet,
+			path:   "/works/?title=dune",
+			setup: func(m *appmock.MockWorkUseCase) {
+				m.On("FetchWorks", mock.Anything, domain.WorkFilter{Title: "dune"}).Return(&[]domain.Work{work}, nil)
+			},
+			want: `"data":[{"id":"` + id + `","title":"Dune"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			path:   "/works/",
+			body:   `{"title":"Dune"}`,
+			setup: func(m *appmock.MockWorkUseCase) {
+				m.On("CreateWork", mock.Anything, mock.AnythingOfType("*domain.Work")).Return(nil)
+			},
+			want: `"title":"Dune"`,
+			code: http.StatusCreated,
+		},
+		{
+			name:   "Create without a title",
+			method: http.MethodPost,
+			path:   "/works/",
+			body:   `{}`,
+			setup:  func(m *appmock.MockWorkUseCase) {},
+			want:   `"error_code":"invalid_fields"`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "Suggestions",
+			method: http.MethodGet,
+			path:   "/works/suggestions",
+			setup: func(m *appmock.MockWorkUseCase) {
+				m.On("SuggestWorks", mock.Anything, domain.DefaultWorkSuggestionScore).Return(&[]domain.WorkSuggestion{
+					{Score: 1, Books: []domain.Book{edition, edition}, WorkIDs: []uuid.UUID{work.ID}},
+				}, nil)
+			},
+			want: `"score":1`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Suggestions with a score",
+			method: http.MethodGet,
+			path:   "/works/suggestions?min_score=0.8",
+			setup: func(m *appmock.MockWorkUseCase) {
+				m.On("SuggestWorks", mock.Anything, 0.8).Return(&[]domain.WorkSuggestion{}, nil)
+			},
+			want: `"data":[]`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Suggestions with an invalid score",
+			method: http.MethodGet,
+			path:   "/works/suggestions?min_score=high",
+			setup:  func(m *appmock.MockWorkUseCase) {},
+			want:   `"error_code":"invalid_fields"`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "Suggestions with a score above 1",
+			method: http.MethodGet,
+			path:   "/works/suggestions?min_score=2",
+			setup:  func(m *appmock.MockWorkUseCase) {},
+			want:   `"field":"min_score"`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "Get",
+			method: http.MethodGet,
+			path:   "/works/" + id,
+			setup: func(m *appmock.MockWorkUseCase) {
+				m.On("GetWorkByID", mock.Anything, id).Return(&work, nil)
+			},
+			want: `"data":{"id":"` + id + `","title":"Dune"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Get unknown",
+			method: http.MethodGet,
+			path:   "/works/" + id,
+			setup: func(m *appmock.MockWorkUseCase) {
+				m.On("GetWorkByID", mock.Anything, id).Return((*domain.Work)(nil), apperror.NewNotFound("Work", "ID", id))
+			},
+			want: `"error_code":"not_found"`,
+			code: http.StatusNotFound,
+		},
+		{
+			name:   "Update",
+			method: http.MethodPut,
+			path:   "/works/" + id,
+			body:   `{"title":"Dune"}`,
+			setup: func(m *appmock.MockWorkUseCase) {
+				m.On("UpdateWork", mock.Anything, id, mock.AnythingOfType("*domain.Work")).Return(nil)
+			},
+			want: `"title":"Dune"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Delete with editions",
+			method: http.MethodDelete,
+			path:   "/works/" + id,
+			setup: func(m *appmock.MockWorkUseCase) {
+				m.On("DeleteWork", mock.Anything, id).Return(apperror.NewConflictCode("editions_of_work", apperror.Params{"work": "Dune"}))
+			},
+			want: `"error_code":"editions_of_work"`,
+			code: http.StatusConflict,
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			path:   "/works/" + id,
+			setup: func(m *appmock.MockWorkUseCase) {
+				m.On("DeleteWork", mock.Anything, id).Return(nil)
+			},
+			want: `"data":null`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Editions",
+			method: http.MethodGet,
+			path:   "/works/" + id + "/editions",
+			setup: func(m *appmock.MockWorkUseCase) {
+				m.On("FetchEditions", mock.Anything, id).Return(&[]domain.Book{edition}, nil)
+			},
+			want: `"work_id":"` + id + `"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Merge",
+			method: http.MethodPost,
+			path:   "/works/" + id + "/merge",
+			body:   `{"work_ids":["` + uuid.NewString() + `"]}`,
+			setup: func(m *appmock.MockWorkUseCase) {
+				m.On("MergeWorks", mock.Anything, id, mock.AnythingOfType("*domain.WorkMerge")).Return(&work, nil)
+			},
+			want: `"data":{"id":"` + id + `"`,
+			code: http.StatusOK,
+		},
+		{
+			name:   "Merge nothing",
+			method: http.MethodPost,
+			path:   "/works/" + id + "/merge",
+			body:   `{"work_ids":[]}`,
+			setup:  func(m *appmock.MockWorkUseCase) {},
+			want:   `"error_code":"invalid_fields"`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "Merge with trashed editions",
+			method: http.MethodPost,
+			path:   "/works/" + id + "/merge",
+			body:   `{"work_ids":["` + uuid.NewString() + `"]}`,
+			setup: func(m *appmock.MockWorkUseCase) {
+				m.On("MergeWorks", mock.Anything, id, mock.AnythingOfType("*domain.WorkMerge")).Return((*domain.Work)(nil), apperror.NewConflictCode("trashed_editions_of_work", apperror.Params{"work": "Dune"}))
+			},
+			want: `"error_code":"trashed_editions_of_work"`,
+			code: http.StatusConflict,
+		},
+		{
+			name:   "Split",
+			method: http.MethodPost,
+			path:   "/works/" + id + "/split",
+			body:   `{"book_ids":["` + edition.ID.String() + `"],"title":"Dune Messiah"}`,
+			setup: func(m *appmock.MockWorkUseCase) {
+				m.On("SplitWork", mock.Anything, id, mock.AnythingOfType("*domain.WorkSplit")).Return(&domain.Work{ID: uuid.New(), Title: "Dune Messiah"}, nil)
+			},
+			want: `"title":"Dune Messiah"`,
+			code: http.StatusCreated,
+		},
+		{
+			name:   "Split a book of another work",
+			method: http.MethodPost,
+			path:   "/works/" + id + "/split",
+			body:   `{"book_ids":["` + other.String() + `"]}`,
+			setup: func(m *appmock.MockWorkUseCase) {
+				m.On("SplitWork", mock.Anything, id, mock.AnythingOfType("*domain.WorkSplit")).Return((*domain.Work)(nil), apperror.NewBadRequestCode("not_an_edition", apperror.Params{"book_id": other, "work_id": work.ID}))
+			},
+			want: `"error_code":"not_an_edition"`,
+			code: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWorkUseCase := new(appmock.MockWorkUseCase)
+			tt.setup(mockWorkUseCase)
+
+			router := gin.New()
+			validator := middleware.OpenAPIValidator(NewOpenAPIDocument("/books"), middleware.OpenAPIOptions{ValidateResponses: true})
+			NewWorkHandler(router, mockWorkUseCase, time.Second, WithMiddleware(validator))
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code, w.Body.String())
+			assert.Contains(t, w.Body.String(), tt.want)
+			mockWorkUseCase.AssertExpectations(t)
+		})
+	}
+}
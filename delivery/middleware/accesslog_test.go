@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/applog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var logs bytes.Buffer
+	logger, _ := applog.New(&logs, "info", applog.FormatJSON)
+
+	router := gin.New()
+	router.Use(RequestID(), AccessLog(logger))
+	g := router.Group("/books", Timeout(time.Second, apperror.NewServiceUnavailable()))
+	g.GET("/:id", func(c *gin.Context) {
+		response.Error(c, apperror.NewNotFound("Book", "ID", c.Param("id")))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/books/1", nil)
+	req.Header.Set(response.RequestIDHeader, "req-1")
+	req.RemoteAddr = "10.0.0.1:1234"
+
+	router.ServeHTTP(w, req)
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "req-1", record["request_id"])
+	assert.Equal(t, "GET", record["method"])
+	assert.Equal(t, "/books/:id", record["route"])
+	assert.Equal(t, "/books/1", record["path"])
+	assert.Equal(t, float64(http.StatusNotFound), record["status"])
+	assert.Equal(t, float64(w.Body.Len()), record["bytes"])
+	assert.Equal(t, "10.0.0.1", record["client_ip"])
+	assert.Contains(t, record, "latency_ms")
+}
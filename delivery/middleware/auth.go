@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+// Authenticator verifies the credentials sent with a request
+type Authenticator interface {
+	// Authenticate returns the caller of r, or nil, nil when r carries
+	// no credentials the Authenticator handles
+	Authenticate(r *http.Request) (*domain.Principal, error)
+}
+
+// RequireAuth rejects requests which are not authenticated by one of
+// authns, the caller is put in the request context
+func RequireAuth(authns ...Authenticator) gin.HandlerFunc {
+	return authenticate(true, authns)
+}
+
+// OptionalAuth lets anonymous requests through, but still rejects
+// requests with invalid credentials
+func OptionalAuth(authns ...Authenticator) gin.HandlerFunc {
+	return authenticate(false, authns)
+}
+
+func authenticate(required bool, authns []Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, a := range authns {
+			p, err := a.Authenticate(c.Request)
+			if err != nil {
+				rejectAuth(c, err)
+				return
+			}
+			if p != nil {
+				c.Request = c.Request.WithContext(domain.ContextWithPrincipal(c.Request.Context(), p))
+				c.Next()
+				return
+			}
+		}
+
+		if required {
+			rejectAuth(c, apperror.NewAuthorization("missing credentials"))
+			return
+		}
+		c.Next()
+	}
+}
+
+func rejectAuth(c *gin.Context, err error) {
+	if apperror.Status(err) == http.StatusUnauthorized {
+		c.Header("WWW-Authenticate", `Bearer realm="books"`)
+	}
+	response.Error(c, err)
+	c.Abort()
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+// tokenAuth accepts the token "valid" and rejects any other
+type tokenAuth struct{}
+
+func (tokenAuth) Authenticate(r *http.Request) (*domain.Principal, error) {
+	switch r.Header.Get("Authorization") {
+	case "":
+		return nil, nil
+	case "Bearer valid":
+		return &domain.Principal{Subject: "user-1"}, nil
+	default:
+		return nil, apperror.NewAuthorization("invalid bearer token")
+	}
+}
+
+func TestAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	whoami := func(c *gin.Context) {
+		p, ok := domain.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			c.String(http.StatusOK, "anonymous")
+			return
+		}
+		c.String(http.StatusOK, p.Subject)
+	}
+	router.GET("/required", RequireAuth(tokenAuth{}), whoami)
+	router.GET("/optional", OptionalAuth(tokenAuth{}), whoami)
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		code          int
+		body          string
+	}{
+		{name: "Required with token", path: "/required", authorization: "Bearer valid", code: http.StatusOK, body: "user-1"},
+		{name: "Required without token", path: "/required", code: http.StatusUnauthorized},
+		{name: "Required with invalid token", path: "/required", authorization: "Bearer invalid", code: http.StatusUnauthorized},
+		{name: "Optional with token", path: "/optional", authorization: "Bearer valid", code: http.StatusOK, body: "user-1"},
+		{name: "Optional without token", path: "/optional", code: http.StatusOK, body: "anonymous"},
+		{name: "Optional with invalid token", path: "/optional", authorization: "Bearer invalid", code: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+			if tt.code == http.StatusUnauthorized {
+				assert.Equal(t, `Bearer realm="books"`, w.Header().Get("WWW-Authenticate"))
+				assert.Contains(t, w.Body.String(), `"error_code":"unauthorized"`)
+				return
+			}
+			assert.Equal(t, tt.body, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+// DefaultBodyLimit caps request bodies unless configured otherwise
+const DefaultBodyLimit int64 = 1 << 20
+
+// BodyLimit rejects request bodies over max bytes. Routes listed in
+// overrides by method and template, eg. "POST /books/import", get
+// their own limit. The Content-Length is checked up front and bodies
+// without one, such as chunked bodies, are cut off while being read.
+func BodyLimit(max int64, overrides map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := max
+		if l, ok := overrides[c.Request.Method+" "+c.FullPath()]; ok {
+			limit = l
+		}
+
+		if c.Request.ContentLength > limit {
+			response.Error(c, apperror.NewPayloadTooLarge(limit, c.Request.ContentLength))
+			c.Abort()
+			return
+		}
+
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			c.Request.Body = &limitedBody{ReadCloser: c.Request.Body, limit: limit}
+		}
+
+		c.Next()
+	}
+}
+
+// ParseBodyLimits parses per route limits such as
+// "POST /books/import=10485760,PUT /books/:id=2048"
+func ParseBodyLimits(s string) (map[string]int64, error) {
+	limits := map[string]int64{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		route, size, ok := strings.Cut(entry, "=")
+		limit, err := strconv.ParseInt(size, 0, 64)
+		if !ok || err != nil || limit <= 0 || len(strings.Fields(route)) != 2 {
+			return nil, fmt.Errorf("body limit %q is not of the form <METHOD> <route>=<bytes>", entry)
+		}
+
+		fields := strings.Fields(route)
+		limits[strings.ToUpper(fields[0])+" "+fields[1]] = limit
+	}
+
+	return limits, nil
+}
+
+// limitedBody fails reads with a PayloadTooLarge error once more than
+// limit bytes were read, the error reports how many were read by then
+type limitedBody struct {
+	io.ReadCloser
+	limit int64
+	read  int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.read > b.limit {
+		return 0, apperror.NewPayloadTooLarge(b.limit, b.read)
+	}
+
+	// read one byte past the limit to tell a body of exactly limit bytes
+	// from a larger one
+	if left := b.limit + 1 - b.read; int64(len(p)) > left {
+		p = p[:left]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if b.read > b.limit {
+		return n, apperror.NewPayloadTooLarge(b.limit, b.read)
+	}
+
+	return n, err
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/stretchr/testify/assert"
+)
+
+// onlyReader hides the length of a body, so it is sent chunked
+type onlyReader struct {
+	io.Reader
+}
+
+func TestBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(BodyLimit(10, map[string]int64{"POST /books/import": 20}))
+	echo := func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		c.String(http.StatusOK, "%d", len(body))
+	}
+	router.POST("/books/", echo)
+	router.POST("/books/import", echo)
+
+	tests := []struct {
+		name    string
+		path    string
+		body    io.Reader
+		code    int
+		message string
+	}{
+		{name: "Under the limit", path: "/books/", body: strings.NewReader("0123456789"), code: http.StatusOK},
+		{name: "Content-Length over the limit", path: "/books/", body: strings.NewReader("0123456789X"), code: http.StatusRequestEntityTooLarge, message: "Max payload size of 10 exceeded. Actual payload size: 11"},
+		{name: "Chunked under the limit", path: "/books/", body: onlyReader{strings.NewReader("0123456789")}, code: http.StatusOK},
+		{name: "Chunked over the limit", path: "/books/", body: onlyReader{strings.NewReader(strings.Repeat("X", 100))}, code: http.StatusRequestEntityTooLarge, message: "Max payload size of 10 exceeded. Actual payload size: 11"},
+		{name: "Route override", path: "/books/import", body: strings.NewReader(strings.Repeat("X", 20)), code: http.StatusOK},
+		{name: "Route override over the limit", path: "/books/import", body: strings.NewReader(strings.Repeat("X", 21)), code: http.StatusRequestEntityTooLarge, message: "Max payload size of 20 exceeded. Actual payload size: 21"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", tt.path, tt.body)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+			if tt.message != "" {
+				assert.Contains(t, w.Body.String(), tt.message)
+			}
+		})
+	}
+}
+
+func TestParseBodyLimits(t *testing.T) {
+	limits, err := ParseBodyLimits("post /books/import=10485760, PUT /books/:id=2048")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"POST /books/import": 10485760, "PUT /books/:id": 2048}, limits)
+
+	for _, s := range []string{"/books/import=10", "POST /books/import", "POST /books/import=-1", "POST /books/import=big"} {
+		_, err := ParseBodyLimits(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TimedOutKey is set on the gin.Context of requests which Timeout cut off
+const TimedOutKey = "timed_out"
+
+// HTTPMetrics holds the RED metrics of the routes, by route template
+type HTTPMetrics struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+	timeouts *prometheus.CounterVec
+}
+
+// NewHTTPMetrics registers the HTTP metrics with reg
+func NewHTTPMetrics(reg prometheus.Registerer) *HTTPMetrics {
+	m := &HTTPMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Requests handled, by route template and status.",
+		}, []string{"method", "route", "status"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_errors_total",
+			Help: "Requests answered with an error, by route template and apperror type.",
+		}, []string{"method", "route", "type"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Latency of the requests, by route template.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "Requests being handled.",
+		}),
+		timeouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_request_timeouts_total",
+			Help: "Requests cut off by the handler timeout, by route template.",
+		}, []string{"method", "route"}),
+	}
+	reg.MustRegister(m.requests, m.errors, m.duration, m.inFlight, m.timeouts)
+
+	return m
+}
+
+// Handler records the metrics of every request it sees
+func (m *HTTPMetrics) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		// the writer on the wire, middleware such as Timeout replace c.Writer
+		w := c.Writer
+
+		m.inFlight.Inc()
+		defer m.inFlight.Dec()
+
+		c.Next()
+
+		method := c.Request.Method
+		route := c.FullPath()
+		if route == "" {
+			// keep the cardinality bounded, whatever the client asks for
+			route = "unmatched"
+		}
+
+		m.requests.WithLabelValues(method, route, strconv.Itoa(w.Status())).Inc()
+		m.duration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+
+		if c.GetBool(TimedOutKey) {
+			m.timeouts.WithLabelValues(method, route).Inc()
+		}
+		if t, ok := c.Get(response.ErrorTypeKey); ok {
+			m.errors.WithLabelValues(method, route, string(t.(apperror.Type))).Inc()
+		}
+	}
+}
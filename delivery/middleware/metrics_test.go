@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	reg := prometheus.NewRegistry()
+	m := NewHTTPMetrics(reg)
+
+	// timedOut answers the slow request as Timeout does once its deadline
+	// passes, without the handler goroutine Timeout leaves running
+	timedOut := func(c *gin.Context) {
+		if c.Param("id") != "slow" {
+			return
+		}
+		response.Error(c, apperror.NewServiceUnavailable())
+		c.Set(TimedOutKey, true)
+		c.Abort()
+	}
+
+	router := gin.New()
+	router.Use(m.Handler())
+	g := router.Group("/books", timedOut)
+	g.GET("/:id", func(c *gin.Context) {
+		if c.Param("id") == "missing" {
+			response.Error(c, apperror.NewNotFound("Book", "ID", "missing"))
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/books/1", "/books/2", "/books/missing", "/books/slow", "/nowhere/1"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+	}
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.requests.WithLabelValues("GET", "/books/:id", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.requests.WithLabelValues("GET", "/books/:id", "404")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.requests.WithLabelValues("GET", "/books/:id", "503")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.requests.WithLabelValues("GET", "unmatched", "404")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.errors.WithLabelValues("GET", "/books/:id", string(apperror.NotFound))))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.errors.WithLabelValues("GET", "/books/:id", string(apperror.ServiceUnavailable))))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.timeouts.WithLabelValues("GET", "/books/:id")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(m.inFlight))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.duration))
+}
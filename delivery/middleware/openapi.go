@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/openapi"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+// OpenAPIOptions configures the OpenAPIValidator middleware
+type OpenAPIOptions struct {
+	// ValidateResponses replaces responses which break the contract
+	// with a 500, meant for development and tests only
+	ValidateResponses bool
+}
+
+// OpenAPIValidator rejects requests whose path params, query string or
+// JSON body do not match the operation described in doc
+func OpenAPIValidator(doc *openapi.Document, opts OpenAPIOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		op := doc.Operation(c.Request.Method, c.FullPath())
+		if op == nil {
+			c.Next()
+			return
+		}
+
+		req := openapi.Request{
+			PathParams:  map[string]string{},
+			Query:       c.Request.URL.Query(),
+			ContentType: c.GetHeader("Content-Type"),
+		}
+		for _, p := range c.Params {
+			req.PathParams[p.Key] = p.Value
+		}
+
+		if op.RequestBody != nil && c.Request.Body != nil {
+			body, err := io.ReadAll(c.Request.Body)
+			var tooLarge *apperror.Error
+			if errors.As(err, &tooLarge) && tooLarge.Type == apperror.PayloadTooLarge {
+				response.Error(c, tooLarge)
+				c.Abort()
+				return
+			}
+			if err != nil {
+				response.Error(c, apperror.NewBadRequest("unable to read request body"))
+				c.Abort()
+				return
+			}
+			// restore the body for the handlers to bind
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			req.Body = body
+		}
+
+		if errs := doc.ValidateRequest(op, req); len(errs) > 0 {
+			response.Error(c, apperror.NewValidation(fieldErrors(errs)))
+			c.Abort()
+			return
+		}
+
+		if !opts.ValidateResponses {
+			c.Next()
+			return
+		}
+
+		rw := &recordingWriter{ResponseWriter: c.Writer, code: http.StatusOK}
+		c.Writer = rw
+		c.Next()
+		c.Writer = rw.ResponseWriter
+
+		if errs := doc.ValidateResponse(op, rw.code, rw.Header().Get("Content-Type"), rw.body.Bytes()); len(errs) > 0 {
+			slog.ErrorContext(c.Request.Context(), "response does not match the API schema", "method", c.Request.Method, "route", c.FullPath(), "errors", fmt.Sprint(errs))
+			e := apperror.NewInternal()
+			e.Details = fieldErrors(errs)
+			response.Error(c, e)
+			return
+		}
+
+		c.Writer.WriteHeader(rw.code)
+		c.Writer.Write(rw.body.Bytes())
+	}
+}
+
+// fieldErrors converts schema violations to apperror details, their
+// messages localized from the field.* catalog entries like those of the
+// handlers
+func fieldErrors(errs []openapi.ValidationError) []apperror.FieldError {
+	fields := make([]apperror.FieldError, 0, len(errs))
+	for _, e := range errs {
+		params := apperror.Params{}
+		if e.Param != nil {
+			params["param"] = e.Param
+			if e.Rule == "type" {
+				params = apperror.Params{"type": e.Param}
+			}
+		}
+		if e.Pointer == "" {
+			// the whole body or response failed, it has no field name
+			params["field"] = e.In
+		}
+		fields = append(fields, apperror.NewFieldError(e.In, e.Field(), e.Pointer, e.Rule, e.Value, params))
+	}
+
+	return fields
+}
+
+// recordingWriter holds back the response so it can be validated
+// before being sent
+type recordingWriter struct {
+	gin.ResponseWriter
+	body    bytes.Buffer
+	code    int
+	written bool
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.body.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *recordingWriter) WriteHeader(code int) {
+	if !w.written {
+		w.code = code
+	}
+}
+
+func (w *recordingWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *recordingWriter) Status() int {
+	return w.code
+}
+
+func (w *recordingWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *recordingWriter) Written() bool {
+	return w.written
+}
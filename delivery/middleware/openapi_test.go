@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/openapi"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+func newValidatedRouter(opts OpenAPIOptions, h gin.HandlerFunc) *gin.Engine {
+	doc := openapi.New("test", "1.0.0")
+	doc.AddOperation(http.MethodPost, "/items/:id", &openapi.Operation{
+		OperationID: "createItem",
+		Parameters: []*openapi.Parameter{
+			{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer"}},
+		},
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: openapi.JSON(&openapi.Schema{
+				Type:       "object",
+				Required:   []string{"name"},
+				Properties: map[string]*openapi.Schema{"name": {Type: "string"}},
+			}),
+		},
+		Responses: map[string]*openapi.Response{
+			"201": {
+				Description: "created",
+				Content: openapi.JSON(&openapi.Schema{
+					Type:     "object",
+					Required: []string{"name"},
+				}),
+			},
+		},
+	})
+
+	router := gin.New()
+	router.Use(OpenAPIValidator(doc, opts))
+	router.POST("/items/:id", h)
+	router.POST("/undocumented", h)
+
+	return router
+}
+
+func TestOpenAPIValidator(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	echo := func(c *gin.Context) {
+		var body map[string]interface{}
+		c.ShouldBindJSON(&body)
+		c.JSON(http.StatusCreated, body)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/items/1", bytes.NewBufferString(`{"name":"x"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		newValidatedRouter(OpenAPIOptions{ValidateResponses: true}, echo).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"name":"x"}`, w.Body.String())
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/items/one", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		newValidatedRouter(OpenAPIOptions{}, echo).ServeHTTP(w, req)
+
+		var body struct {
+			Details []apperror.FieldError `json:"details"`
+		}
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, []apperror.FieldError{
+			{In: "path", Field: "id", Pointer: "/id", Rule: "type", Message: "id must be of type integer", Value: "one"},
+			{In: "body", Field: "name", Pointer: "/name", Rule: "required", Message: "name is required"},
+		}, body.Details)
+	})
+
+	t.Run("Invalid request - Thai", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/items/one", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", "th")
+
+		newValidatedRouter(OpenAPIOptions{}, echo).ServeHTTP(w, req)
+
+		var body struct {
+			Details []apperror.FieldError `json:"details"`
+		}
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body))
+		var messages []string
+		for _, d := range body.Details {
+			messages = append(messages, d.Message)
+		}
+		assert.Equal(t, []string{"id ต้องเป็นชนิด integer", "ต้องระบุ name"}, messages)
+	})
+
+	t.Run("Missing body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/items/1", nil)
+		req.Header.Set("Content-Type", "application/json")
+
+		newValidatedRouter(OpenAPIOptions{}, echo).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"message":"body is required"`)
+	})
+
+	t.Run("Undocumented route", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/undocumented", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		newValidatedRouter(OpenAPIOptions{ValidateResponses: true}, echo).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Invalid response", func(t *testing.T) {
+		broken := func(c *gin.Context) {
+			c.JSON(http.StatusCreated, gin.H{"title": "x"})
+		}
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/items/1", bytes.NewBufferString(`{"name":"x"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		newValidatedRouter(OpenAPIOptions{ValidateResponses: true}, broken).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), `"pointer":"/name"`)
+	})
+
+	t.Run("Invalid response - not validated", func(t *testing.T) {
+		broken := func(c *gin.Context) {
+			c.JSON(http.StatusCreated, gin.H{"title": "x"})
+		}
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/items/1", bytes.NewBufferString(`{"name":"x"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		newValidatedRouter(OpenAPIOptions{}, broken).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+}
@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+// Rate allows bursts of Requests which refill evenly over Per
+type Rate struct {
+	Requests int
+	Per      time.Duration
+}
+
+// ParseRate parses rates such as 100/1m or 5/s
+func ParseRate(s string) (Rate, error) {
+	n, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("rate %q is not of the form <requests>/<duration>", s)
+	}
+
+	requests, err := strconv.Atoi(n)
+	if err != nil || requests <= 0 {
+		return Rate{}, fmt.Errorf("rate %q must allow a positive number of requests", s)
+	}
+
+	d, err := time.ParseDuration(per)
+	if err != nil {
+		// allow a bare unit, eg. 5/s
+		d, err = time.ParseDuration("1" + per)
+	}
+	if err != nil || d <= 0 {
+		return Rate{}, fmt.Errorf("rate %q must have a positive duration", s)
+	}
+
+	return Rate{Requests: requests, Per: d}, nil
+}
+
+// RateLimitResult is the state of a bucket after a request was counted
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int           // requests left in the bucket
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next request is allowed, when not Allowed
+}
+
+// RateLimitStore keeps the token buckets, implement it over a shared
+// backend, such as Redis, to limit clients across instances
+type RateLimitStore interface {
+	// Take removes a token from the bucket of key, if there is one
+	Take(ctx context.Context, key string, rate Rate) (RateLimitResult, error)
+}
+
+// RateLimit limits each client to rate on the routes it is used on,
+// clients are identified by API key, then user, then IP. Routes sharing
+// a name share the buckets. It must run after the auth middleware.
+func RateLimit(store RateLimitStore, name string, rate Rate) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := name + ":" + clientKey(c)
+
+		res, err := store.Take(c.Request.Context(), key, rate)
+		if err != nil {
+			// an unavailable store should not take the API down
+			slog.WarnContext(c.Request.Context(), "rate limit store failed, the request is not limited", "key", key, "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(rate.Requests))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+
+		if !res.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			response.Error(c, apperror.NewTooManyRequests(res.RetryAfter))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func clientKey(c *gin.Context) string {
+	if p, ok := domain.PrincipalFromContext(c.Request.Context()); ok {
+		if p.Method == domain.AuthMethodAPIKey {
+			return p.Subject
+		}
+		return "user:" + p.Subject
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// MemoryRateLimitStore keeps the buckets of a single instance
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	at     time.Time // when tokens was computed
+	full   time.Time // when the bucket is full again, it can be dropped after
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, rate Rate) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now, rate.Per)
+
+	capacity := float64(rate.Requests)
+	perToken := rate.Per / time.Duration(rate.Requests)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, at: now}
+		s.buckets[key] = b
+	}
+
+	// refill for the time elapsed since the last request
+	b.tokens = math.Min(capacity, b.tokens+float64(now.Sub(b.at))/float64(perToken))
+	b.at = now
+
+	res := RateLimitResult{Allowed: b.tokens >= 1}
+	if res.Allowed {
+		b.tokens--
+	} else {
+		res.RetryAfter = time.Duration((1 - b.tokens) * float64(perToken))
+	}
+
+	res.Remaining = int(b.tokens)
+	res.Reset = time.Duration((capacity - b.tokens) * float64(perToken))
+	b.full = now.Add(res.Reset)
+
+	return res, nil
+}
+
+// sweep drops the buckets which have refilled, at most once per period
+func (s *MemoryRateLimitStore) sweep(now time.Time, period time.Duration) {
+	if now.Sub(s.lastSweep) < period {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := map[string]Rate{
+		"100/1m": {Requests: 100, Per: time.Minute},
+		"5/s":    {Requests: 5, Per: time.Second},
+		"10/30s": {Requests: 10, Per: 30 * time.Second},
+	}
+	for s, want := range tests {
+		t.Run(s, func(t *testing.T) {
+			rate, err := ParseRate(s)
+
+			assert.NoError(t, err)
+			assert.Equal(t, want, rate)
+		})
+	}
+
+	for _, s := range []string{"100", "0/1m", "x/1m", "5/forever", "5/-1s"} {
+		t.Run(s, func(t *testing.T) {
+			_, err := ParseRate(s)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryRateLimitStore()
+	store.now = func() time.Time { return now }
+	rate := Rate{Requests: 2, Per: 10 * time.Second}
+	ctx := context.Background()
+
+	res, _ := store.Take(ctx, "a", rate)
+	assert.Equal(t, RateLimitResult{Allowed: true, Remaining: 1, Reset: 5 * time.Second}, res)
+
+	res, _ = store.Take(ctx, "a", rate)
+	assert.Equal(t, RateLimitResult{Allowed: true, Remaining: 0, Reset: 10 * time.Second}, res)
+
+	res, _ = store.Take(ctx, "a", rate)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 5*time.Second, res.RetryAfter)
+
+	// other keys have their own bucket
+	res, _ = store.Take(ctx, "b", rate)
+	assert.True(t, res.Allowed)
+
+	// a token is back after 5s
+	now = now.Add(5 * time.Second)
+	res, _ = store.Take(ctx, "a", rate)
+	assert.True(t, res.Allowed)
+
+	// full buckets are dropped
+	now = now.Add(time.Minute)
+	store.Take(ctx, "c", rate)
+	assert.Len(t, store.buckets, 1)
+}
+
+// failingStore is a shared backend which is down
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, rate Rate) (RateLimitResult, error) {
+	return RateLimitResult{}, errors.New("connection refused")
+}
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(store RateLimitStore, principal *domain.Principal) *gin.Engine {
+		router := gin.New()
+		router.GET("/books", func(c *gin.Context) {
+			if principal != nil {
+				c.Request = c.Request.WithContext(domain.ContextWithPrincipal(c.Request.Context(), principal))
+			}
+		}, RateLimit(store, "read", Rate{Requests: 1, Per: time.Minute}), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+	get := func(router *gin.Engine, ip string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books", nil)
+		req.RemoteAddr = ip + ":1234"
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Limited by IP", func(t *testing.T) {
+		router := newRouter(NewMemoryRateLimitStore(), nil)
+
+		w := get(router, "10.0.0.1")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "60", w.Header().Get("RateLimit-Reset"))
+
+		w = get(router, "10.0.0.1")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "60", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), `"error_code":"too_many_requests"`)
+
+		w = get(router, "10.0.0.2")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Limited by principal", func(t *testing.T) {
+		router := newRouter(NewMemoryRateLimitStore(), &domain.Principal{Subject: "api-key:1", Method: domain.AuthMethodAPIKey})
+
+		assert.Equal(t, http.StatusOK, get(router, "10.0.0.1").Code)
+		// the same key from another IP shares the bucket
+		assert.Equal(t, http.StatusTooManyRequests, get(router, "10.0.0.2").Code)
+	})
+
+	t.Run("Store unavailable", func(t *testing.T) {
+		router := newRouter(failingStore{}, nil)
+
+		assert.Equal(t, http.StatusOK, get(router, "10.0.0.1").Code)
+		assert.Equal(t, http.StatusOK, get(router, "10.0.0.1").Code)
+	})
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+// Recovery recovers from panics outside of Timeout and answers with
+// an internal error in the same format as the handlers
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
+		response.Error(c, apperror.NewInternal().WithCause(fmt.Errorf("panic: %v", recovered)))
+		c.Abort()
+	})
+}
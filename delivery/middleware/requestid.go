@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain/applog"
+)
+
+// maxRequestIDLength bounds the request IDs accepted from clients
+const maxRequestIDLength = 128
+
+// RequestID accepts the X-Request-ID of the client, or generates one,
+// and puts it in the request context and the response headers
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(response.RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+
+		c.Request = c.Request.WithContext(applog.WithRequestID(c.Request.Context(), id))
+		c.Header(response.RequestIDHeader, id)
+
+		c.Next()
+	}
+}
+
+// validRequestID keeps IDs which are safe to log and echo back
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range id {
+		ok := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' || r == ':'
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
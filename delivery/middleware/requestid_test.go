@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain/applog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, applog.RequestID(c.Request.Context()))
+	})
+
+	get := func(id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/", nil)
+		if id != "" {
+			req.Header.Set(response.RequestIDHeader, id)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Accepted from the client", func(t *testing.T) {
+		w := get("req-1")
+
+		assert.Equal(t, "req-1", w.Body.String())
+		assert.Equal(t, "req-1", w.Header().Get(response.RequestIDHeader))
+	})
+
+	for name, id := range map[string]string{"Missing": "", "Unsafe": "req 1\n", "Too long": strings.Repeat("x", 200)} {
+		t.Run("Generated - "+name, func(t *testing.T) {
+			w := get(id)
+
+			_, err := uuid.Parse(w.Body.String())
+			assert.NoError(t, err)
+			assert.Equal(t, w.Body.String(), w.Header().Get(response.RequestIDHeader))
+		})
+	}
+}
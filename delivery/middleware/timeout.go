@@ -8,13 +8,13 @@ package middleware
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/response"
 	"github.com/krittawatcode/books/domain/apperror"
 )
 
@@ -31,6 +31,8 @@ func Timeout(timeout time.Duration, errTimeout *apperror.Error) gin.HandlerFunc
 
 		// update gin request context
 		c.Request = c.Request.WithContext(ctx)
+		// keep our own reference, the handler may replace c.Request
+		req := c.Request
 
 		finished := make(chan struct{})        // to indicate handler finished
 		panicChan := make(chan interface{}, 1) // used to handle panics if we can't recover
@@ -47,15 +49,11 @@ func Timeout(timeout time.Duration, errTimeout *apperror.Error) gin.HandlerFunc
 		}()
 
 		select {
-		case <-panicChan:
+		case p := <-panicChan:
 			// if we cannot recover from panic,
 			// send internal server error
-			e := apperror.NewInternal()
-			tw.ResponseWriter.WriteHeader(e.Status())
-			eResp, _ := json.Marshal(gin.H{
-				"error": e,
-			})
-			tw.ResponseWriter.Write(eResp)
+			response.WriteError(tw.ResponseWriter, req, apperror.NewInternal().WithCause(fmt.Errorf("panic: %v", p)))
+			c.Set(response.ErrorTypeKey, apperror.Internal)
 		case <-finished:
 			// if finished, set headers and write resp
 			tw.mu.Lock()
@@ -74,14 +72,11 @@ func Timeout(timeout time.Duration, errTimeout *apperror.Error) gin.HandlerFunc
 			tw.mu.Lock()
 			defer tw.mu.Unlock()
 			// ResponseWriter from gin
-			tw.ResponseWriter.Header().Set("Content-Type", "application/json")
-			tw.ResponseWriter.WriteHeader(errTimeout.Status())
-			eResp, _ := json.Marshal(gin.H{
-				"error": errTimeout,
-			})
-			tw.ResponseWriter.Write(eResp)
+			response.WriteError(tw.ResponseWriter, req, errTimeout)
 			c.Abort()
 			tw.SetTimedOut()
+			c.Set(TimedOutKey, true)
+			c.Set(response.ErrorTypeKey, errTimeout.Type)
 		}
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/response"
 	"github.com/krittawatcode/books/domain/apperror"
 	"github.com/stretchr/testify/assert"
 )
@@ -40,5 +41,39 @@ func TestTimeout(t *testing.T) {
 		h(c)
 
 		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.JSONEq(t, `{"status":"FAIL","code":500,"error_code":"service_unavailable","error":"Service unavailable or timed out"}`, w.Body.String())
+	})
+
+	t.Run("Timeout - Problem Details", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		// Initialize the Request of gin.Context
+		c.Request, _ = http.NewRequest("GET", "/books", nil)
+		c.Request.Header.Set("Accept", response.ProblemContentType)
+
+		h := Timeout(1*time.Nanosecond, apperror.NewServiceUnavailable())
+
+		h(c)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, response.ProblemContentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `"status":503`)
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		_, router := gin.CreateTestContext(w)
+
+		router.Use(Timeout(1*time.Second, apperror.NewServiceUnavailable()))
+		router.GET("/books", func(c *gin.Context) {
+			panic("boom")
+		})
+
+		req, _ := http.NewRequest("GET", "/books", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"status":"FAIL","code":500,"error_code":"internal","error":"Internal server error."}`, w.Body.String())
 	})
 }
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/applog"
+	"github.com/krittawatcode/books/domain/apptrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing starts a server span of every request, continuing the trace
+// of the caller found in the headers by prop, eg. a W3C traceparent.
+// The span is carried by the request context, so the use cases and
+// repositories called by the handler record their spans under it
+func Tracing(tp trace.TracerProvider, prop propagation.TextMapPropagator) gin.HandlerFunc {
+	tracer := tp.Tracer(apptrace.InstrumentationName)
+
+	return func(c *gin.Context) {
+		ctx := prop.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		ctx, span := tracer.Start(ctx, c.Request.Method, trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("url.path", c.Request.URL.Path),
+				attribute.String("client.address", c.ClientIP()),
+			),
+		)
+		defer span.End()
+		if id := applog.RequestID(ctx); id != "" {
+			span.SetAttributes(attribute.String("app.request_id", id))
+		}
+
+		// the writer on the wire, middleware such as Timeout replace c.Writer
+		w := c.Writer
+		// let clients find the trace of their request
+		prop.Inject(ctx, propagation.HeaderCarrier(w.Header()))
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		span.SetName(c.Request.Method + " " + route)
+		span.SetAttributes(
+			attribute.String("http.route", route),
+			attribute.Int("http.response.status_code", w.Status()),
+		)
+
+		if t, ok := c.Get(response.ErrorTypeKey); ok {
+			span.SetAttributes(apptrace.ErrorTypeKey.String(string(t.(apperror.Type))))
+			span.SetStatus(codes.Error, string(t.(apperror.Type)))
+		}
+		if c.GetBool(TimedOutKey) {
+			span.SetAttributes(attribute.Bool("app.timed_out", true))
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/apptrace"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	// the trace ID seen by the handler, behind the goroutine of Timeout
+	var handlerTraceID trace.TraceID
+
+	router := gin.New()
+	router.Use(Tracing(tp, propagation.TraceContext{}))
+	g := router.Group("/books", Timeout(time.Second, apperror.NewServiceUnavailable()))
+	g.GET("/:id", func(c *gin.Context) {
+		handlerTraceID = trace.SpanContextFromContext(c.Request.Context()).TraceID()
+		if c.Param("id") == "missing" {
+			response.Error(c, apperror.NewNotFound("Book", "ID", "missing"))
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	t.Run("ContinuesTrace", func(t *testing.T) {
+		before := len(sr.Ended())
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books/1", nil)
+		req.Header.Set("traceparent", traceparent)
+		router.ServeHTTP(w, req)
+
+		spans := sr.Ended()[before:]
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "GET /books/:id", spans[0].Name())
+		assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+		assert.Equal(t, spans[0].SpanContext().TraceID(), handlerTraceID)
+		assert.Contains(t, spans[0].Attributes(), attribute.String("http.route", "/books/:id"))
+		assert.Contains(t, spans[0].Attributes(), attribute.Int("http.response.status_code", http.StatusOK))
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+		assert.Contains(t, w.Header().Get("traceparent"), "4bf92f3577b34da6a3ce929d0e0e4736")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		before := len(sr.Ended())
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books/missing", nil)
+		router.ServeHTTP(w, req)
+
+		spans := sr.Ended()[before:]
+		assert.Len(t, spans, 1)
+		assert.True(t, spans[0].SpanContext().TraceID().IsValid())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Equal(t, string(apperror.NotFound), spans[0].Status().Description)
+		assert.Contains(t, spans[0].Attributes(), apptrace.ErrorTypeKey.String(string(apperror.NotFound)))
+	})
+
+	t.Run("Unmatched", func(t *testing.T) {
+		before := len(sr.Ended())
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/nowhere", nil)
+		router.ServeHTTP(w, req)
+
+		spans := sr.Ended()[before:]
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "GET unmatched", spans[0].Name())
+	})
+}
@@ -0,0 +1,255 @@
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Version of the OpenAPI specification the documents are written against
+const Version = "3.1.0"
+
+// Document is the root object of an OpenAPI description
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info holds the metadata about the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem holds the operations available on a single path
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Patch  *Operation `json:"patch,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+}
+
+// Operation describes a single API operation on a path
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []SecurityRequirement `json:"security,omitempty"`
+}
+
+// SecurityRequirement maps the name of a security scheme to the scopes
+// it requires, an empty requirement makes the security optional
+type SecurityRequirement map[string][]string
+
+// SecurityScheme describes how clients authenticate
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Parameter describes a path or query parameter
+type Parameter struct {
+	Ref         string  `json:"$ref,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	In          string  `json:"in,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// RequestBody describes the body accepted by an operation
+type RequestBody struct {
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Content     map[string]*MediaType `json:"content"`
+}
+
+// Response describes a single response of an operation
+type Response struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema for a content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the reusable objects of the document
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	Responses       map[string]*Response       `json:"responses,omitempty"`
+	Parameters      map[string]*Parameter      `json:"parameters,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// Schema is the subset of JSON Schema (2020-12) used by our documents
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Const                interface{}        `json:"const,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	ReadOnly             bool               `json:"readOnly,omitempty"`
+	Example              interface{}        `json:"example,omitempty"`
+}
+
+// New returns an empty document with the given title and version
+func New(title string, version string) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:   title,
+			Version: version,
+		},
+		Paths: map[string]*PathItem{},
+		Components: Components{
+			Schemas:         map[string]*Schema{},
+			Responses:       map[string]*Response{},
+			Parameters:      map[string]*Parameter{},
+			SecuritySchemes: map[string]*SecurityScheme{},
+		},
+	}
+}
+
+// AddOperation registers op for the method on a gin style path
+// eg. AddOperation(http.MethodGet, "/books/:id", op)
+func (d *Document) AddOperation(method string, path string, op *Operation) {
+	path = PathFromGin(path)
+
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+
+	switch method {
+	case http.MethodGet:
+		item.Get = op
+	case http.MethodPost:
+		item.Post = op
+	case http.MethodPut:
+		item.Put = op
+	case http.MethodPatch:
+		item.Patch = op
+	case http.MethodDelete:
+		item.Delete = op
+	}
+}
+
+// Operation returns the operation for the method on a gin style path
+// or nil when the document does not describe it
+func (d *Document) Operation(method string, path string) *Operation {
+	item, ok := d.Paths[PathFromGin(path)]
+	if !ok {
+		return nil
+	}
+
+	switch method {
+	case http.MethodGet:
+		return item.Get
+	case http.MethodPost:
+		return item.Post
+	case http.MethodPut:
+		return item.Put
+	case http.MethodPatch:
+		return item.Patch
+	case http.MethodDelete:
+		return item.Delete
+	default:
+		return nil
+	}
+}
+
+// PathFromGin converts a gin route template such as /books/:id
+// to the OpenAPI form /books/{id}
+func PathFromGin(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// Methods returns the methods described on path, sorted
+func (p *PathItem) Methods() []string {
+	methods := []string{}
+	for m, op := range map[string]*Operation{
+		http.MethodGet:    p.Get,
+		http.MethodPost:   p.Post,
+		http.MethodPut:    p.Put,
+		http.MethodPatch:  p.Patch,
+		http.MethodDelete: p.Delete,
+	} {
+		if op != nil {
+			methods = append(methods, m)
+		}
+	}
+	sort.Strings(methods)
+
+	return methods
+}
+
+// Ref returns a $ref pointing at the named component schema
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// ResponseRef returns a $ref pointing at the named component response
+func ResponseRef(name string) *Response {
+	return &Response{Ref: "#/components/responses/" + name}
+}
+
+// ParameterRef returns a $ref pointing at the named component parameter
+func ParameterRef(name string) *Parameter {
+	return &Parameter{Ref: "#/components/parameters/" + name}
+}
+
+// JSON wraps schema into application/json content
+func JSON(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: schema}}
+}
+
+// Int returns a pointer to v, for the optional schema keywords
+func Int(v int) *int {
+	return &v
+}
+
+// Float returns a pointer to v, for the optional schema keywords
+func Float(v float64) *float64 {
+	return &v
+}
+
+// Bool returns a pointer to v, for the optional schema keywords
+func Bool(v bool) *bool {
+	return &v
+}
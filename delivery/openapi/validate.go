@@ -0,0 +1,402 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ValidationError describes a single value which does not match its schema
+type ValidationError struct {
+	In      string      `json:"in"`      // body, path or query
+	Pointer string      `json:"pointer"` // JSON pointer to the failing value, relative to In
+	Rule    string      `json:"rule"`    // schema keyword which failed, eg. required
+	Message string      `json:"message"`
+	Value   interface{} `json:"value,omitempty"` // the rejected value
+	Param   interface{} `json:"param,omitempty"` // what Rule checked against, eg. 3 of minLength
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s %s %s", e.In, e.Pointer, e.Message)
+}
+
+// Field returns the dotted name of the failing value, eg. /tags/1 is tags.1
+func (e ValidationError) Field() string {
+	tokens := strings.Split(strings.TrimPrefix(e.Pointer, "/"), "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+
+	return strings.Join(tokens, ".")
+}
+
+// Request holds the parts of an incoming request which can be validated
+type Request struct {
+	PathParams  map[string]string
+	Query       url.Values
+	ContentType string
+	Body        []byte
+}
+
+// patterns caches compiled schema patterns
+var patterns sync.Map
+
+// ValidateRequest validates req against the parameters and request body of op
+func (d *Document) ValidateRequest(op *Operation, req Request) []ValidationError {
+	errs := []ValidationError{}
+
+	for _, p := range op.Parameters {
+		p = d.resolveParameter(p)
+		if p == nil {
+			continue
+		}
+
+		var raw []string
+		switch p.In {
+		case "path":
+			if v, ok := req.PathParams[p.Name]; ok {
+				raw = []string{v}
+			}
+		case "query":
+			raw = req.Query[p.Name]
+		default:
+			continue
+		}
+
+		ptr := "/" + escapePointer(p.Name)
+		if len(raw) == 0 {
+			if p.Required {
+				errs = append(errs, ValidationError{In: p.In, Pointer: ptr, Rule: "required", Message: "is required"})
+			}
+			continue
+		}
+
+		for _, e := range d.validate(p.Schema, d.coerceParameter(p.Schema, raw), ptr) {
+			e.In = p.In
+			errs = append(errs, e)
+		}
+	}
+
+	if op.RequestBody == nil {
+		return errs
+	}
+
+	if len(bytes.TrimSpace(req.Body)) == 0 {
+		if op.RequestBody.Required {
+			errs = append(errs, ValidationError{In: "body", Pointer: "", Rule: "required", Message: "is required"})
+		}
+		return errs
+	}
+
+	media := mediaType(op.RequestBody.Content, req.ContentType)
+	if media == nil {
+		// unsupported content types are rejected by the handlers
+		return errs
+	}
+
+	value, err := decode(req.Body)
+	if err != nil {
+		return append(errs, ValidationError{In: "body", Pointer: "", Rule: "type", Message: "must be valid JSON", Param: "JSON"})
+	}
+
+	for _, e := range d.validate(media.Schema, value, "") {
+		e.In = "body"
+		errs = append(errs, e)
+	}
+
+	return errs
+}
+
+// ValidateResponse validates the status and body written for op
+func (d *Document) ValidateResponse(op *Operation, status int, contentType string, body []byte) []ValidationError {
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		resp, ok = op.Responses["default"]
+	}
+	if !ok {
+		return []ValidationError{{In: "response", Pointer: "", Rule: "status", Message: fmt.Sprintf("status %d is not documented", status), Param: status}}
+	}
+
+	resp = d.resolveResponse(resp)
+	if resp == nil || len(resp.Content) == 0 {
+		return nil
+	}
+
+	media := mediaType(resp.Content, contentType)
+	if media == nil {
+		return []ValidationError{{In: "response", Pointer: "", Rule: "contentType", Message: fmt.Sprintf("content type %q is not documented", contentType), Param: strconv.Quote(contentType)}}
+	}
+
+	value, err := decode(body)
+	if err != nil {
+		return []ValidationError{{In: "response", Pointer: "", Rule: "type", Message: "must be valid JSON", Param: "JSON"}}
+	}
+
+	errs := d.validate(media.Schema, value, "")
+	for i := range errs {
+		errs[i].In = "response"
+	}
+
+	return errs
+}
+
+func (d *Document) validate(s *Schema, value interface{}, ptr string) []ValidationError {
+	s = d.resolveSchema(s)
+	if s == nil {
+		return nil
+	}
+
+	errs := []ValidationError{}
+	fail := func(ptr string, value interface{}, rule string, format string, args ...interface{}) {
+		e := ValidationError{Pointer: ptr, Rule: rule, Message: fmt.Sprintf(format, args...), Value: value}
+		if len(args) > 0 {
+			e.Param = args[0]
+		}
+		errs = append(errs, e)
+	}
+
+	for _, sub := range s.AllOf {
+		errs = append(errs, d.validate(sub, value, ptr)...)
+	}
+
+	if len(s.OneOf) > 0 {
+		matches := 0
+		for _, sub := range s.OneOf {
+			if len(d.validate(sub, value, ptr)) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			fail(ptr, value, "oneOf", "must match exactly one schema, matched %d", matches)
+		}
+	}
+
+	if s.Type != "" && !hasType(value, s.Type) {
+		fail(ptr, value, "type", "must be of type %s", s.Type)
+		return errs
+	}
+
+	if s.Const != nil && !equal(value, s.Const) {
+		fail(ptr, value, "const", "must be equal to %v", s.Const)
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if equal(value, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail(ptr, value, "enum", "must be one of %v", s.Enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			fail(ptr, value, "minLength", "must be at least %d characters long", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			fail(ptr, value, "maxLength", "must be at most %d characters long", *s.MaxLength)
+		}
+		if s.Pattern != "" && !matchPattern(s.Pattern, v) {
+			fail(ptr, value, "pattern", "must match pattern %s", s.Pattern)
+		}
+		if s.Format != "" && !hasFormat(v, s.Format) {
+			fail(ptr, value, "format", "must be a valid %s", s.Format)
+		}
+	case json.Number:
+		n, _ := v.Float64()
+		if s.Minimum != nil && n < *s.Minimum {
+			fail(ptr, value, "minimum", "must be greater than or equal to %v", *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			fail(ptr, value, "maximum", "must be less than or equal to %v", *s.Maximum)
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			fail(ptr, value, "minItems", "must contain at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			fail(ptr, value, "maxItems", "must contain at most %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				errs = append(errs, d.validate(s.Items, item, ptr+"/"+strconv.Itoa(i))...)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				fail(ptr+"/"+escapePointer(name), nil, "required", "is required")
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			child := ptr + "/" + escapePointer(name)
+			if sub, ok := s.Properties[name]; ok {
+				errs = append(errs, d.validate(sub, v[name], child)...)
+			} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				fail(child, v[name], "additionalProperties", "is not allowed")
+			}
+		}
+	}
+
+	return errs
+}
+
+// coerceParameter converts the raw string values of a parameter
+// to the JSON type its schema expects
+func (d *Document) coerceParameter(s *Schema, raw []string) interface{} {
+	s = d.resolveSchema(s)
+	if s == nil {
+		return raw[0]
+	}
+
+	if s.Type == "array" {
+		values := make([]interface{}, 0, len(raw))
+		for _, r := range raw {
+			values = append(values, d.coerceParameter(s.Items, []string{r}))
+		}
+		return values
+	}
+
+	switch s.Type {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(raw[0], 64); err == nil {
+			return json.Number(raw[0])
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw[0]); err == nil {
+			return b
+		}
+	}
+
+	return raw[0]
+}
+
+func (d *Document) resolveSchema(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
+
+func (d *Document) resolveParameter(p *Parameter) *Parameter {
+	for p != nil && p.Ref != "" {
+		p = d.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+	}
+	return p
+}
+
+func (d *Document) resolveResponse(r *Response) *Response {
+	for r != nil && r.Ref != "" {
+		r = d.Components.Responses[strings.TrimPrefix(r.Ref, "#/components/responses/")]
+	}
+	return r
+}
+
+// mediaType returns the content entry matching the contentType header
+func mediaType(content map[string]*MediaType, contentType string) *MediaType {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+	return content[mt]
+}
+
+func decode(body []byte) (interface{}, error) {
+	var value interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func hasType(value interface{}, t string) bool {
+	switch v := value.(type) {
+	case nil:
+		return t == "null"
+	case bool:
+		return t == "boolean"
+	case string:
+		return t == "string"
+	case json.Number:
+		if t == "number" {
+			return true
+		}
+		_, err := v.Int64()
+		return t == "integer" && err == nil
+	case []interface{}:
+		return t == "array"
+	case map[string]interface{}:
+		return t == "object"
+	default:
+		return false
+	}
+}
+
+func hasFormat(value string, format string) bool {
+	switch format {
+	case "uuid":
+		_, err := uuid.Parse(value)
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, value)
+		return err == nil
+	default:
+		// unknown formats are annotations only
+		return true
+	}
+}
+
+func matchPattern(pattern string, value string) bool {
+	re, ok := patterns.Load(pattern)
+	if !ok {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return true
+		}
+		re, _ = patterns.LoadOrStore(pattern, compiled)
+	}
+
+	return re.(*regexp.Regexp).MatchString(value)
+}
+
+// equal compares a decoded JSON value with a value from a schema
+func equal(value interface{}, expected interface{}) bool {
+	a, errA := json.Marshal(value)
+	b, errB := json.Marshal(expected)
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
+
+// escapePointer escapes a reference token as described in RFC 6901
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,135 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testDocument() *Document {
+	doc := New("test", "1.0.0")
+	doc.Components.Schemas["Item"] = &Schema{
+		Type:     "object",
+		Required: []string{"name"},
+		Properties: map[string]*Schema{
+			"name":  {Type: "string", MinLength: Int(1)},
+			"count": {Type: "integer", Minimum: Float(0)},
+			"tags":  {Type: "array", Items: &Schema{Type: "string", Enum: []interface{}{"a", "b"}}},
+		},
+		AdditionalProperties: Bool(false),
+	}
+	doc.AddOperation(http.MethodPut, "/items/:id", &Operation{
+		OperationID: "putItem",
+		Parameters: []*Parameter{
+			{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string", Format: "uuid"}},
+			{Name: "limit", In: "query", Schema: &Schema{Type: "integer", Maximum: Float(10)}},
+		},
+		RequestBody: &RequestBody{Required: true, Content: JSON(Ref("Item"))},
+		Responses: map[string]*Response{
+			"200": {Description: "ok", Content: JSON(Ref("Item"))},
+			"204": {Description: "no content"},
+		},
+	})
+
+	return doc
+}
+
+func TestPathFromGin(t *testing.T) {
+	assert.Equal(t, "/books/{id}/revert/{revision}", PathFromGin("/books/:id/revert/:revision"))
+	assert.Equal(t, "/docs/{filepath}", PathFromGin("/docs/*filepath"))
+	assert.Equal(t, "/books/", PathFromGin("/books/"))
+}
+
+func TestValidationError_Field(t *testing.T) {
+	assert.Equal(t, "tags.1", ValidationError{Pointer: "/tags/1"}.Field())
+	assert.Equal(t, "a/b", ValidationError{Pointer: "/a~1b"}.Field())
+	assert.Equal(t, "", ValidationError{Pointer: ""}.Field())
+}
+
+func TestValidateRequest(t *testing.T) {
+	doc := testDocument()
+	op := doc.Operation(http.MethodPut, "/items/:id")
+
+	t.Run("Success", func(t *testing.T) {
+		errs := doc.ValidateRequest(op, Request{
+			PathParams:  map[string]string{"id": "0b1f5b8e-3b0a-4e43-9d5c-5a3c1d0f6f10"},
+			Query:       url.Values{"limit": {"5"}},
+			ContentType: "application/json; charset=utf-8",
+			Body:        []byte(`{"name":"x","count":2,"tags":["a"]}`),
+		})
+
+		assert.Empty(t, errs)
+	})
+
+	t.Run("Invalid params", func(t *testing.T) {
+		errs := doc.ValidateRequest(op, Request{
+			PathParams:  map[string]string{"id": "1"},
+			Query:       url.Values{"limit": {"11"}},
+			ContentType: "application/json",
+			Body:        []byte(`{"name":"x"}`),
+		})
+
+		assert.Equal(t, []ValidationError{
+			{In: "path", Pointer: "/id", Rule: "format", Message: "must be a valid uuid", Value: "1", Param: "uuid"},
+			{In: "query", Pointer: "/limit", Rule: "maximum", Message: "must be less than or equal to 10", Value: json.Number("11"), Param: float64(10)},
+		}, errs)
+	})
+
+	t.Run("Invalid body", func(t *testing.T) {
+		errs := doc.ValidateRequest(op, Request{
+			PathParams:  map[string]string{"id": "0b1f5b8e-3b0a-4e43-9d5c-5a3c1d0f6f10"},
+			ContentType: "application/json",
+			Body:        []byte(`{"count":1.5,"tags":["a","c"],"extra":true}`),
+		})
+
+		assert.Equal(t, []ValidationError{
+			{In: "body", Pointer: "/name", Rule: "required", Message: "is required"},
+			{In: "body", Pointer: "/count", Rule: "type", Message: "must be of type integer", Value: json.Number("1.5"), Param: "integer"},
+			{In: "body", Pointer: "/extra", Rule: "additionalProperties", Message: "is not allowed", Value: true},
+			{In: "body", Pointer: "/tags/1", Rule: "enum", Message: "must be one of [a b]", Value: "c", Param: []interface{}{"a", "b"}},
+		}, errs)
+	})
+
+	t.Run("Malformed body", func(t *testing.T) {
+		errs := doc.ValidateRequest(op, Request{
+			PathParams:  map[string]string{"id": "0b1f5b8e-3b0a-4e43-9d5c-5a3c1d0f6f10"},
+			ContentType: "application/json",
+			Body:        []byte(`{name:`),
+		})
+
+		assert.Equal(t, []ValidationError{{In: "body", Pointer: "", Rule: "type", Message: "must be valid JSON", Param: "JSON"}}, errs)
+	})
+
+	t.Run("Missing body", func(t *testing.T) {
+		errs := doc.ValidateRequest(op, Request{
+			PathParams: map[string]string{"id": "0b1f5b8e-3b0a-4e43-9d5c-5a3c1d0f6f10"},
+		})
+
+		assert.Equal(t, []ValidationError{{In: "body", Pointer: "", Rule: "required", Message: "is required"}}, errs)
+	})
+}
+
+func TestValidateResponse(t *testing.T) {
+	doc := testDocument()
+	op := doc.Operation(http.MethodPut, "/items/:id")
+
+	t.Run("Success", func(t *testing.T) {
+		assert.Empty(t, doc.ValidateResponse(op, http.StatusOK, "application/json; charset=utf-8", []byte(`{"name":"x"}`)))
+		assert.Empty(t, doc.ValidateResponse(op, http.StatusNoContent, "", nil))
+	})
+
+	t.Run("Undocumented status", func(t *testing.T) {
+		errs := doc.ValidateResponse(op, http.StatusTeapot, "application/json", []byte(`{}`))
+
+		assert.Equal(t, []ValidationError{{In: "response", Pointer: "", Rule: "status", Message: "status 418 is not documented", Param: 418}}, errs)
+	})
+
+	t.Run("Invalid body", func(t *testing.T) {
+		errs := doc.ValidateResponse(op, http.StatusOK, "application/json", []byte(`{"name":""}`))
+
+		assert.Equal(t, []ValidationError{{In: "response", Pointer: "/name", Rule: "minLength", Message: "must be at least 1 characters long", Value: "", Param: 1}}, errs)
+	})
+}
@@ -0,0 +1,239 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/domain/apperror"
+	"golang.org/x/text/language"
+)
+
+type status string
+
+const (
+	StatusSuccess status = "SUCCESS"
+	StatusFail    status = "FAIL"
+)
+
+type code int
+
+// represent our own service status code
+const (
+	CodeSuccess code = 200
+	CodeFail    code = 500
+)
+
+// Format of the error responses
+type Format string
+
+const (
+	FormatEnvelope Format = "envelope" // errorResponse
+	FormatProblem  Format = "problem"  // RFC 7807 Problem Details
+)
+
+// ProblemContentType is the media type of Problem Details responses
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBase prefixes the type URI of Problem Details responses
+const ProblemTypeBase = "/problems/"
+
+// RequestIDHeader correlates a request with its logs
+const RequestIDHeader = "X-Request-ID"
+
+// ErrorTypeKey is set on the gin.Context to the apperror.Type of the
+// error sent by Error, eg. for metrics
+const ErrorTypeKey = "error_type"
+
+// defaultFormat is used unless the client asks for Problem Details
+var defaultFormat = FormatEnvelope
+
+type response struct {
+	Status status `json:"status"`
+	Code   code   `json:"code"`
+}
+
+type successResponse struct {
+	response
+	Data interface{} `json:"data"`
+}
+
+// ErrorResponse represents an error response
+type errorResponse struct {
+	response
+	ErrorCode string                `json:"error_code"`
+	Error     string                `json:"error"`
+	Details   []apperror.FieldError `json:"details,omitempty"`
+}
+
+// problemDetails represents an RFC 7807 error response
+type problemDetails struct {
+	Type     string                `json:"type"`
+	Title    string                `json:"title"`
+	Status   int                   `json:"status"`
+	Detail   string                `json:"detail"`
+	Instance string                `json:"instance"`
+	Code     string                `json:"code"`
+	Details  []apperror.FieldError `json:"details,omitempty"`
+}
+
+// languages we have message catalogs for, the first is the fallback
+var languages = language.NewMatcher([]language.Tag{language.English, language.Thai})
+
+// ParseFormat parses the ERROR_FORMAT configuration
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatEnvelope:
+		return FormatEnvelope, nil
+	case FormatProblem:
+		return FormatProblem, nil
+	default:
+		return "", fmt.Errorf("unknown error format %q", s)
+	}
+}
+
+// SetDefaultFormat sets the error format used when the client does not
+// ask for Problem Details through the Accept header
+func SetDefaultFormat(f Format) {
+	defaultFormat = f
+}
+
+// Success writes data in the success envelope
+func Success(c *gin.Context, httpStatus int, data interface{}) {
+	c.JSON(httpStatus, successResponse{response: response{Status: StatusSuccess, Code: CodeSuccess}, Data: data})
+}
+
+// Error writes err in the format and language negotiated for the request,
+// with the http status and the invalid fields, if any, derived from err
+func Error(c *gin.Context, err error) {
+	e := apperror.Wrap(err)
+	c.Set(ErrorTypeKey, e.Type)
+
+	httpStatus, contentType, lang, body := renderError(c.Request, e)
+	c.Header("Content-Language", lang)
+	c.Data(httpStatus, contentType, body)
+}
+
+// WriteError is Error for code which cannot go through gin.Context,
+// such as middleware writing directly to the underlying writer
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	httpStatus, contentType, lang, body := renderError(r, err)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Language", lang)
+	w.WriteHeader(httpStatus)
+	w.Write(body)
+}
+
+func renderError(r *http.Request, err error) (int, string, string, []byte) {
+	// errors which are not apperrors may carry internals, such as
+	// driver messages, so they are only logged
+	e := apperror.Wrap(err)
+	logError(r, e)
+
+	httpStatus := e.Status()
+	lang := negotiateLanguage(r)
+	message := e.Localize(lang)
+
+	var details []apperror.FieldError
+	for _, d := range e.Details {
+		d.Message = d.Localize(lang)
+		details = append(details, d)
+	}
+
+	if negotiateFormat(r) == FormatProblem {
+		instance := ""
+		if r != nil {
+			instance = r.URL.RequestURI()
+		}
+
+		body, _ := json.Marshal(problemDetails{
+			Type:     ProblemType(e.Type),
+			Title:    http.StatusText(httpStatus),
+			Status:   httpStatus,
+			Detail:   message,
+			Instance: instance,
+			Code:     e.Code,
+			Details:  details,
+		})
+		return httpStatus, ProblemContentType, lang, body
+	}
+
+	body, _ := json.Marshal(errorResponse{response: response{Status: StatusFail, Code: CodeFail}, ErrorCode: e.Code, Error: message, Details: details})
+	return httpStatus, gin.MIMEJSON + "; charset=utf-8", lang, body
+}
+
+// logError logs server errors and errors with a cause, along with
+// the full chain of causes, the request ID comes from the context
+func logError(r *http.Request, e *apperror.Error) {
+	if e.Status() < http.StatusInternalServerError && e.Unwrap() == nil {
+		return
+	}
+
+	ctx, method, uri := context.Background(), "", ""
+	if r != nil {
+		ctx, method, uri = r.Context(), r.Method, r.URL.RequestURI()
+	}
+
+	level := slog.LevelError
+	if e.Status() < http.StatusInternalServerError {
+		level = slog.LevelWarn
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("uri", uri),
+		slog.Int("status", e.Status()),
+		slog.String("error", strings.Join(apperror.Chain(e), ": caused by: ")),
+	}
+	if stack := e.StackTrace(); stack != "" {
+		attrs = append(attrs, slog.String("stack", stack))
+	}
+
+	slog.LogAttrs(ctx, level, "request failed", attrs...)
+}
+
+// negotiateLanguage picks the catalog language from Accept-Language
+func negotiateLanguage(r *http.Request) string {
+	if r == nil {
+		return apperror.DefaultLanguage
+	}
+
+	tags, _, _ := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	tag, _, _ := languages.Match(tags...)
+	base, _ := tag.Base()
+
+	if !apperror.Supported(base.String()) {
+		return apperror.DefaultLanguage
+	}
+	return base.String()
+}
+
+// ProblemType returns the Problem Details type URI of an apperror.Type
+// eg. /problems/service-unavailable
+func ProblemType(t apperror.Type) string {
+	return ProblemTypeBase + strings.ReplaceAll(strings.ToLower(string(t)), "_", "-")
+}
+
+// negotiateFormat picks Problem Details when the client accepts it,
+// otherwise the configured default
+func negotiateFormat(r *http.Request) Format {
+	if r == nil {
+		return defaultFormat
+	}
+
+	for _, accept := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(accept, ",") {
+			mt, _, err := mime.ParseMediaType(part)
+			if err == nil && mt == ProblemContentType {
+				return FormatProblem
+			}
+		}
+	}
+
+	return defaultFormat
+}
@@ -0,0 +1,190 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/applog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	Success(c, http.StatusCreated, gin.H{"title": "100x"})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"status":"SUCCESS","code":200,"data":{"title":"100x"}}`, w.Body.String())
+}
+
+func TestError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("apperror", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		Error(c, apperror.NewNotFound("Book", "ID", "1"))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"status":"FAIL","code":500,"error_code":"not_found","error":"resource: Book with ID value: 1 not found"}`, w.Body.String())
+	})
+
+	t.Run("Unknown error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		Error(c, errors.New("driver: connection refused"))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.NotContains(t, w.Body.String(), "connection refused")
+	})
+
+	t.Run("Details", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		Error(c, apperror.NewValidation([]apperror.FieldError{
+			{In: "body", Field: "title", Pointer: "/title", Rule: "required", Message: "title is required"},
+		}))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"status":"FAIL","code":500,"error_code":"invalid_fields","error":"Bad request. Reason: 1 invalid field(s)","details":[
+			{"in":"body","field":"title","pointer":"/title","rule":"required","message":"title is required"}
+		]}`, w.Body.String())
+	})
+}
+
+func TestError_Logging(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var logs bytes.Buffer
+	logger, _ := applog.New(&logs, "info", applog.FormatJSON)
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(logger)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/books", nil)
+	c.Request = c.Request.WithContext(applog.WithRequestID(c.Request.Context(), "req-1"))
+
+	Error(c, apperror.NewInternal().WithCause(fmt.Errorf("fetch books: %w", errors.New("connection refused"))))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "connection refused")
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "req-1", record["request_id"])
+	assert.Equal(t, "/books", record["uri"])
+	assert.Equal(t, "Internal server error.: caused by: fetch books: connection refused: caused by: connection refused", record["error"])
+}
+
+func TestError_ProblemDetails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Accept header", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/books/1?x=y", nil)
+		c.Request.Header.Set("Accept", "application/json, application/problem+json;q=0.9")
+
+		Error(c, apperror.NewNotFound("Book", "ID", "1"))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{
+			"type": "/problems/notfound",
+			"title": "Not Found",
+			"status": 404,
+			"detail": "resource: Book with ID value: 1 not found",
+			"instance": "/books/1?x=y",
+			"code": "not_found"
+		}`, w.Body.String())
+	})
+
+	t.Run("Default format", func(t *testing.T) {
+		SetDefaultFormat(FormatProblem)
+		defer SetDefaultFormat(FormatEnvelope)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/books", nil)
+
+		Error(c, apperror.NewServiceUnavailable())
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `"type":"/problems/service-unavailable"`)
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/books", nil)
+		c.Request.Header.Set("Accept", "application/json")
+
+		Error(c, apperror.NewServiceUnavailable())
+
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"status":"FAIL","code":500,"error_code":"service_unavailable","error":"Service unavailable or timed out"}`, w.Body.String())
+	})
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatEnvelope, f)
+
+	f, err = ParseFormat("problem")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatProblem, f)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestError_Localized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Thai", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/books", nil)
+		c.Request.Header.Set("Accept-Language", "th-TH,th;q=0.9,en;q=0.8")
+
+		Error(c, apperror.NewValidation([]apperror.FieldError{
+			apperror.NewFieldError("body", "title", "/title", "required", "", apperror.Params{"param": ""}),
+		}))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "th", w.Header().Get("Content-Language"))
+		assert.JSONEq(t, `{"status":"FAIL","code":500,"error_code":"invalid_fields","error":"คำขอไม่ถูกต้อง สาเหตุ: ข้อมูลไม่ถูกต้อง 1 รายการ","details":[
+			{"in":"body","field":"title","pointer":"/title","rule":"required","message":"ต้องระบุ title","value":""}
+		]}`, w.Body.String())
+	})
+
+	t.Run("Unsupported language", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/books/1", nil)
+		c.Request.Header.Set("Accept-Language", "fr-FR")
+
+		Error(c, apperror.NewNotFound("Book", "ID", "1"))
+
+		assert.Equal(t, "en", w.Header().Get("Content-Language"))
+		assert.Contains(t, w.Body.String(), "resource: Book with ID value: 1 not found")
+	})
+}
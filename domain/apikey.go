@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scopes which can be granted to an API key, they are the permissions
+// of the key
+const (
+	ScopeBooksRead   = PermissionBooksRead
+	ScopeBooksWrite  = PermissionBooksWrite
+	ScopeBooksDelete = PermissionBooksDelete
+)
+
+// APIKeyScopes lists every scope an API key can be granted
+var APIKeyScopes = []string{ScopeBooksRead, ScopeBooksWrite, ScopeBooksDelete}
+
+// APIKey identifies a service client, only the hash of the key is kept
+type APIKey struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // the start of the key, to tell keys apart
+	Hash       string     `json:"-"`      // hex encoded SHA-256 of the key
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// IssuedAPIKey is an APIKey along with the key itself, which is only
+// returned when the key is issued
+type IssuedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+type APIKeyUseCase interface {
+	FetchAPIKeys(ctx context.Context) (*[]APIKey, error)
+	IssueAPIKey(ctx context.Context, name string, scopes []string) (*IssuedAPIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+	AuthenticateAPIKey(ctx context.Context, key string) (*APIKey, error)
+}
+
+type APIKeyRepository interface {
+	FetchAPIKeys(ctx context.Context) (*[]APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error)
+	CreateAPIKey(ctx context.Context, key *APIKey) error
+	RevokeAPIKey(ctx context.Context, id string, at time.Time) error
+	TouchAPIKey(ctx context.Context, id string, at time.Time) error
+}
@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"runtime"
+	"strings"
+	"time"
 )
 
 // Type holds a type string and integer code for the error
@@ -14,10 +17,12 @@ const (
 	Authorization        Type = "AUTHORIZATION"        // Authentication Failures -
 	BadRequest           Type = "BADREQUEST"           // Validation errors / BadInput
 	Conflict             Type = "CONFLICT"             // Already exists (eg, create account with existent email) - 409
+	Forbidden            Type = "FORBIDDEN"            // Authenticated but not allowed - 403
 	Internal             Type = "INTERNAL"             // Server (500) and fallback errors
 	NotFound             Type = "NOTFOUND"             // For not finding resource
 	PayloadTooLarge      Type = "PAYLOADTOOLARGE"      // for uploading tons of JSON, or an image over the limit - 413
 	ServiceUnavailable   Type = "SERVICE_UNAVAILABLE"  // For long running handlers
+	TooManyRequests      Type = "TOO_MANY_REQUESTS"    // Rate limited clients - 429
 	UnsupportedMediaType Type = "UNSUPPORTEDMEDIATYPE" // for http 415
 )
 
@@ -25,8 +30,28 @@ const (
 // which is helpful in returning a consistent
 // error type/message from API endpoints
 type Error struct {
-	Type    Type   `json:"type"`
-	Message string `json:"message"`
+	Type    Type         `json:"type"`
+	Code    string       `json:"code"`             // stable machine code, eg. not_found
+	Params  Params       `json:"params,omitempty"` // values of the message placeholders
+	Message string       `json:"message"`          // the message in DefaultLanguage
+	Details []FieldError `json:"details,omitempty"`
+
+	cause error     // the underlying error, never sent to clients
+	stack []uintptr // where the cause was attached, when CaptureStack is on
+}
+
+// CaptureStack records a stack trace whenever a cause is attached
+var CaptureStack = false
+
+// FieldError describes a single invalid field of a request
+type FieldError struct {
+	In      string      `json:"in"`      // where the field was sent: body, path or query
+	Field   string      `json:"field"`   // JSON name of the field, dotted when nested
+	Pointer string      `json:"pointer"` // JSON pointer to the field, relative to In
+	Rule    string      `json:"rule"`    // the rule which failed, eg. required
+	Message string      `json:"message"`
+	Value   interface{} `json:"value,omitempty"` // the rejected value
+	Params  Params      `json:"-"`               // set when Message can be localized
 }
 
 // Error satisfies standard error interface
@@ -36,6 +61,111 @@ func (e *Error) Error() string {
 	return e.Message
 }
 
+// Unwrap returns the cause of e, for errors.Is and errors.As
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether target is an *Error of the same Type, and of the
+// same Code when target has one, eg.
+// errors.Is(err, &apperror.Error{Type: apperror.NotFound})
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.Type == e.Type && (t.Code == "" || t.Code == e.Code)
+}
+
+// WithCause attaches the underlying error to e, it is logged
+// but never rendered to clients
+func (e *Error) WithCause(cause error) *Error {
+	e.cause = cause
+	if CaptureStack {
+		pcs := make([]uintptr, 32)
+		n := runtime.Callers(2, pcs)
+		e.stack = pcs[:n]
+	}
+
+	return e
+}
+
+// StackTrace formats the stack recorded by WithCause, empty unless
+// CaptureStack was on
+func (e *Error) StackTrace() string {
+	if len(e.stack) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+
+	return b.String()
+}
+
+// Chain returns the message of err and of every error it wraps,
+// outermost first
+func Chain(err error) []string {
+	chain := []string{}
+	for ; err != nil; err = errors.Unwrap(err) {
+		chain = append(chain, err.Error())
+	}
+
+	return chain
+}
+
+// Localize renders the message of e in lang
+func (e *Error) Localize(lang string) string {
+	if msg, ok := translate(lang, e.Code, e.Params); ok {
+		return msg
+	}
+	return e.Message
+}
+
+// Localize renders the message of f in lang, messages which were not
+// built from Params are returned as is
+func (f FieldError) Localize(lang string) string {
+	if f.Params == nil {
+		return f.Message
+	}
+
+	params := Params{"field": f.Field, "rule": f.Rule}
+	for k, v := range f.Params {
+		params[k] = v
+	}
+
+	if msg, ok := translate(lang, "field."+f.Rule, params); ok {
+		return msg
+	}
+	if msg, ok := translate(lang, "field.invalid", params); ok {
+		return msg
+	}
+	return f.Message
+}
+
+// NewFieldError builds a FieldError whose message is localized from
+// the rule which failed and params
+func NewFieldError(in string, field string, pointer string, rule string, value interface{}, params Params) FieldError {
+	f := FieldError{
+		In:      in,
+		Field:   field,
+		Pointer: pointer,
+		Rule:    rule,
+		Value:   value,
+		Params:  params,
+	}
+	f.Message = f.Localize(DefaultLanguage)
+
+	return f
+}
+
 // Status is a mapping errors to status codes
 // Of course, this is somewhat redundant since
 // our errors already map http status codes
@@ -47,6 +177,8 @@ func (e *Error) Status() int {
 		return http.StatusBadRequest
 	case Conflict:
 		return http.StatusConflict
+	case Forbidden:
+		return http.StatusForbidden
 	case Internal:
 		return http.StatusInternalServerError
 	case NotFound:
@@ -55,6 +187,8 @@ func (e *Error) Status() int {
 		return http.StatusRequestEntityTooLarge
 	case ServiceUnavailable:
 		return http.StatusServiceUnavailable
+	case TooManyRequests:
+		return http.StatusTooManyRequests
 	case UnsupportedMediaType:
 		return http.StatusUnsupportedMediaType
 	default:
@@ -77,66 +211,102 @@ func Status(err error) int {
 * Error "Factories"
  */
 
+// newError builds an Error rendering its Message in DefaultLanguage
+func newError(t Type, code string, params Params) *Error {
+	e := &Error{
+		Type:   t,
+		Code:   code,
+		Params: params,
+	}
+	e.Message = e.Localize(DefaultLanguage)
+
+	return e
+}
+
 // NewAuthorization to create a 401
 func NewAuthorization(reason string) *Error {
-	return &Error{
-		Type:    Authorization,
-		Message: reason,
-	}
+	return newError(Authorization, "unauthorized", Params{"reason": reason})
+}
+
+// NewForbidden to create a 403
+func NewForbidden(reason string) *Error {
+	return newError(Forbidden, "forbidden", Params{"reason": reason})
 }
 
 // NewBadRequest to create 400 errors (validation, for example)
 func NewBadRequest(reason string) *Error {
-	return &Error{
-		Type:    BadRequest,
-		Message: fmt.Sprintf("Bad request. Reason: %v", reason),
-	}
+	return newError(BadRequest, "bad_request", Params{"reason": reason})
+}
+
+// NewBadRequestCode to create 400 errors whose message is the catalog
+// entry of code filled with params, so clients can tell the failures
+// apart and read them in their language
+func NewBadRequestCode(code string, params Params) *Error {
+	return newError(BadRequest, code, params)
+}
+
+// NewValidation to create 400 errors listing each invalid field
+func NewValidation(details []FieldError) *Error {
+	e := newError(BadRequest, "invalid_fields", Params{"count": len(details)})
+	e.Details = details
+
+	return e
 }
 
 // NewConflict to create an error for 409
 func NewConflict(name string, value string) *Error {
-	return &Error{
-		Type:    Conflict,
-		Message: fmt.Sprintf("resource: %v with value: %v already exists", name, value),
-	}
+	return newError(Conflict, "conflict", Params{"name": name, "value": value})
+}
+
+// NewConflictCode to create a 409 whose message is the catalog entry of
+// code filled with params, for conflicts other than a duplicate value
+func NewConflictCode(code string, params Params) *Error {
+	return newError(Conflict, code, params)
 }
 
 // NewInternal for 500 errors and unknown errors
 func NewInternal() *Error {
-	return &Error{
-		Type:    Internal,
-		Message: "Internal server error.",
+	return newError(Internal, "internal", nil)
+}
+
+// Wrap turns err into an apperror, errors which already are one are
+// returned as is, anything else becomes an internal error caused by err
+func Wrap(err error) *Error {
+	if err == nil {
+		return nil
 	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	return NewInternal().WithCause(err)
 }
 
 // NewNotFound to create an error for 404
 func NewNotFound(name string, key string, value string) *Error {
-	return &Error{
-		Type:    NotFound,
-		Message: fmt.Sprintf("resource: %v with %v value: %v not found", name, key, value),
-	}
+	return newError(NotFound, "not_found", Params{"name": name, "key": key, "value": value})
 }
 
 // NewPayloadTooLarge to create an error for 413
 func NewPayloadTooLarge(maxBodySize int64, contentLength int64) *Error {
-	return &Error{
-		Type:    PayloadTooLarge,
-		Message: fmt.Sprintf("Max payload size of %v exceeded. Actual payload size: %v", maxBodySize, contentLength),
-	}
+	return newError(PayloadTooLarge, "payload_too_large", Params{"max": maxBodySize, "actual": contentLength})
 }
 
 // NewServiceUnavailable to create an error for 503
 func NewServiceUnavailable() *Error {
-	return &Error{
-		Type:    ServiceUnavailable,
-		Message: "Service unavailable or timed out",
-	}
+	return newError(ServiceUnavailable, "service_unavailable", nil)
+}
+
+// NewTooManyRequests to create an error for 429, retryAfter is rounded
+// up to the second
+func NewTooManyRequests(retryAfter time.Duration) *Error {
+	seconds := int64((retryAfter + time.Second - 1) / time.Second)
+	return newError(TooManyRequests, "too_many_requests", Params{"seconds": seconds})
 }
 
 // NewUnsupportedMediaType to create an error for 415
 func NewUnsupportedMediaType(reason string) *Error {
-	return &Error{
-		Type:    UnsupportedMediaType,
-		Message: reason,
-	}
+	return newError(UnsupportedMediaType, "unsupported_media_type", Params{"reason": reason})
 }
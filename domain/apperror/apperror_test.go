@@ -0,0 +1,85 @@
+package apperror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCause(t *testing.T) {
+	t.Run("Unwrap", func(t *testing.T) {
+		err := NewInternal().WithCause(fmt.Errorf("fetch books: %w", context.DeadlineExceeded))
+
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Equal(t, "Internal server error.", err.Error())
+		assert.Equal(t, []string{
+			"Internal server error.",
+			"fetch books: context deadline exceeded",
+			"context deadline exceeded",
+		}, Chain(err))
+	})
+
+	t.Run("As", func(t *testing.T) {
+		err := fmt.Errorf("usecase: %w", NewNotFound("Book", "ID", "1"))
+
+		var e *Error
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, http.StatusNotFound, Status(err))
+	})
+
+	t.Run("Is", func(t *testing.T) {
+		err := fmt.Errorf("usecase: %w", NewNotFound("Book", "ID", "1"))
+
+		assert.True(t, errors.Is(err, &Error{Type: NotFound}))
+		assert.True(t, errors.Is(err, &Error{Type: NotFound, Code: "not_found"}))
+		assert.False(t, errors.Is(err, &Error{Type: Conflict}))
+	})
+
+	t.Run("Stack trace", func(t *testing.T) {
+		CaptureStack = true
+		defer func() { CaptureStack = false }()
+
+		err := NewInternal().WithCause(errors.New("boom"))
+
+		assert.Contains(t, err.StackTrace(), "TestWithCause")
+	})
+
+	t.Run("No stack trace", func(t *testing.T) {
+		err := NewInternal().WithCause(errors.New("boom"))
+
+		assert.Empty(t, err.StackTrace())
+	})
+}
+
+func TestWrap(t *testing.T) {
+	assert.Nil(t, Wrap(nil))
+
+	notFound := NewNotFound("Book", "ID", "1")
+	assert.Same(t, notFound, Wrap(fmt.Errorf("usecase: %w", notFound)))
+
+	cause := errors.New("driver: connection refused")
+	err := Wrap(cause)
+	assert.Equal(t, Internal, err.Type)
+	assert.Same(t, cause, errors.Unwrap(err))
+}
+
+func TestNewForbidden(t *testing.T) {
+	err := NewForbidden("books:delete is required")
+
+	assert.Equal(t, http.StatusForbidden, err.Status())
+	assert.Equal(t, "forbidden", err.Code)
+	assert.Equal(t, "books:delete is required", err.Error())
+	assert.Equal(t, "ไม่มีสิทธิ์: books:delete is required", err.Localize(Thai))
+}
+
+func TestNewTooManyRequests(t *testing.T) {
+	err := NewTooManyRequests(1500 * time.Millisecond)
+
+	assert.Equal(t, http.StatusTooManyRequests, err.Status())
+	assert.Equal(t, "Too many requests, retry in 2 second(s)", err.Error())
+}
@@ -0,0 +1,193 @@
+package apperror
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Languages with a message catalog
+const (
+	English = "en"
+	Thai    = "th"
+)
+
+// DefaultLanguage is used for Error() and for languages without a catalog
+const DefaultLanguage = English
+
+// Params holds the values substituted into a message, eg. {name}
+type Params map[string]interface{}
+
+// catalogs maps a language to its messages, keyed by error code.
+// Field messages are keyed by "field." and the rule which failed.
+var catalogs = map[string]map[string]string{
+	English: {
+		"unauthorized":           "{reason}",
+		"bad_request":            "Bad request. Reason: {reason}",
+		"invalid_fields":         "Bad request. Reason: {count} invalid field(s)",
+		"conflict":               "resource: {name} with value: {value} already exists",
+		"forbidden":              "{reason}",
+		"internal":               "Internal server error.",
+		"not_found":              "resource: {name} with {key} value: {value} not found",
+		"payload_too_large":      "Max payload size of {max} exceeded. Actual payload size: {actual}",
+		"service_unavailable":    "Service unavailable or timed out",
+		"too_many_requests":      "Too many requests, retry in {seconds} second(s)",
+		"unsupported_media_type": "{reason}",
+
+		"invalid_isbn":              "{isbn} is not an ISBN-10 or ISBN-13 with a valid check digit",
+		"invalid_publication_date":  "{date} is not a publication date in the form YYYY, YYYY-MM or YYYY-MM-DD from {min} on",
+		"publication_date_too_late": "publication date {date} is later than {latest}",
+		"duplicate_contributor":     "author {author_id} is listed twice as {role}",
+		"unknown_author":            "contributor {author_id} is not an author of the catalog",
+		"unknown_publisher":         "publisher {publisher_id} is not a publisher of the catalog",
+		"unknown_parent":            "parent {parent_id} is not a publisher of the catalog",
+		"imprint_cycle":             "publisher {publisher_id} cannot be an imprint of itself or of its own imprints",
+		"duplicate_series":          "series {series_id} is listed twice",
+		"unknown_series":            "series {series_id} is not a series of the catalog",
+		"unknown_work":              "work {work_id} is not a work of the catalog",
+		"merge_into_itself":         "work {work_id} cannot be merged into itself",
+		"not_an_edition":            "book {book_id} is not an edition of work {work_id}",
+		"nothing_to_split":          "no edition of work {work_id} is listed to split off",
+		"invalid_min_score":         "the minimum score {min_score} is not above 0 and at most 1",
+		"barcode_required":          "a copy needs a barcode",
+		"invalid_copy_status":       "{status} is not a copy status",
+		"invalid_acquisition_date":  "acquisition date {date} is not in the form YYYY-MM-DD",
+		"acquisition_date_too_late": "acquisition date {date} is in the future",
+
+		"duplicate_barcode":          "a copy with barcode {barcode} already exists",
+		"active_copies":              "book {book_id} still has copies which are not lost",
+		"books_of_author":            "author {author} still has books",
+		"trashed_books_of_author":    "author {author} still has books in the trash",
+		"imprints_of_publisher":      "publisher {publisher} still has imprints",
+		"books_of_publisher":         "publisher {publisher} still has books",
+		"trashed_books_of_publisher": "publisher {publisher} still has books in the trash",
+		"books_of_series":            "series {series} still has books",
+		"trashed_books_of_series":    "series {series} still has books in the trash",
+		"editions_of_work":           "work {work} still has editions",
+		"trashed_editions_of_work":   "work {work} still has editions in the trash",
+
+		"field.required": "{field} is required",
+		"field.min":      "{field} must be at least {param}",
+		"field.max":      "{field} must be at most {param}",
+		"field.len":      "{field} must have a length of {param}",
+		"field.oneof":    "{field} must be one of [{param}]",
+		"field.type":     "{field} must be of type {type}",
+		"field.invalid":  "{field} failed on the {rule} rule",
+
+		"field.bcp47_language_tag": "{field} must be a BCP 47 language tag",
+		"field.isbn":               "{field} must be an ISBN-10 or ISBN-13 with a valid check digit",
+		"field.publication_date":   "{field} must be a year, month or day in the form YYYY, YYYY-MM or YYYY-MM-DD, from {min} on",
+
+		// the keywords of the OpenAPI schemas
+		"field.format":               "{field} must be a valid {param}",
+		"field.const":                "{field} must be equal to {param}",
+		"field.enum":                 "{field} must be one of {param}",
+		"field.pattern":              "{field} must match the pattern {param}",
+		"field.minLength":            "{field} must be at least {param} characters long",
+		"field.maxLength":            "{field} must be at most {param} characters long",
+		"field.minimum":              "{field} must be greater than or equal to {param}",
+		"field.maximum":              "{field} must be less than or equal to {param}",
+		"field.minItems":             "{field} must contain at least {param} items",
+		"field.maxItems":             "{field} must contain at most {param} items",
+		"field.oneOf":                "{field} must match exactly one schema, matched {param}",
+		"field.additionalProperties": "{field} is not allowed",
+		"field.status":               "{field} status {param} is not documented",
+		"field.contentType":          "{field} content type {param} is not documented",
+	},
+	Thai: {
+		"unauthorized":           "ไม่ได้รับอนุญาต: {reason}",
+		"bad_request":            "คำขอไม่ถูกต้อง สาเหตุ: {reason}",
+		"invalid_fields":         "คำขอไม่ถูกต้อง สาเหตุ: ข้อมูลไม่ถูกต้อง {count} รายการ",
+		"conflict":               "{name} ที่มีค่า {value} มีอยู่แล้ว",
+		"forbidden":              "ไม่มีสิทธิ์: {reason}",
+		"internal":               "เกิดข้อผิดพลาดภายในเซิร์ฟเวอร์",
+		"not_found":              "ไม่พบ {name} ที่มี {key} เป็น {value}",
+		"payload_too_large":      "ขนาดข้อมูลเกินกำหนด {max} ไบต์ ขนาดที่ส่งมา: {actual} ไบต์",
+		"service_unavailable":    "บริการไม่พร้อมใช้งานหรือหมดเวลา",
+		"too_many_requests":      "ส่งคำขอมากเกินไป กรุณาลองใหม่ในอีก {seconds} วินาที",
+		"unsupported_media_type": "ไม่รองรับประเภทข้อมูลที่ส่งมา: {reason}",
+
+		"invalid_isbn":              "{isbn} ไม่ใช่ ISBN-10 หรือ ISBN-13 ที่มีเลขตรวจสอบถูกต้อง",
+		"invalid_publication_date":  "{date} ไม่ใช่วันที่พิมพ์ในรูปแบบ YYYY, YYYY-MM หรือ YYYY-MM-DD ตั้งแต่ปี {min} เป็นต้นไป",
+		"publication_date_too_late": "วันที่พิมพ์ {date} อยู่หลังวันที่ {latest}",
+		"duplicate_contributor":     "ผู้เขียน {author_id} ถูกระบุซ้ำในบทบาท {role}",
+		"unknown_author":            "ผู้ร่วมจัดทำ {author_id} ไม่ใช่ผู้เขียนในแคตตาล็อก",
+		"unknown_publisher":         "สำนักพิมพ์ {publisher_id} ไม่ใช่สำนักพิมพ์ในแคตตาล็อก",
+		"unknown_parent":            "สำนักพิมพ์แม่ {parent_id} ไม่ใช่สำนักพิมพ์ในแคตตาล็อก",
+		"imprint_cycle":             "สำนักพิมพ์ {publisher_id} เป็นสำนักพิมพ์ในเครือของตัวเองหรือของสำนักพิมพ์ในเครือของตัวเองไม่ได้",
+		"duplicate_series":          "ชุดหนังสือ {series_id} ถูกระบุซ้ำ",
+		"unknown_series":            "ชุดหนังสือ {series_id} ไม่ใช่ชุดหนังสือในแคตตาล็อก",
+		"unknown_work":              "ผลงาน {work_id} ไม่ใช่ผลงานในแคตตาล็อก",
+		"merge_into_itself":         "ผลงาน {work_id} รวมเข้ากับตัวเองไม่ได้",
+		"not_an_edition":            "หนังสือ {book_id} ไม่ใช่ฉบับพิมพ์ของผลงาน {work_id}",
+		"nothing_to_split":          "ไม่ได้ระบุฉบับพิมพ์ของผลงาน {work_id} ที่จะแยกออก",
+		"invalid_min_score":         "คะแนนขั้นต่ำ {min_score} ต้องมากกว่า 0 และไม่เกิน 1",
+		"barcode_required":          "ต้องระบุบาร์โค้ดของเล่ม",
+		"invalid_copy_status":       "{status} ไม่ใช่สถานะของเล่ม",
+		"invalid_acquisition_date":  "วันที่ได้รับ {date} ไม่อยู่ในรูปแบบ YYYY-MM-DD",
+		"acquisition_date_too_late": "วันที่ได้รับ {date} เป็นวันในอนาคต",
+
+		"duplicate_barcode":          "มีเล่มที่ใช้บาร์โค้ด {barcode} อยู่แล้ว",
+		"active_copies":              "หนังสือ {book_id} ยังมีเล่มที่ไม่ได้สูญหาย",
+		"books_of_author":            "ผู้เขียน {author} ยังมีหนังสืออยู่",
+		"trashed_books_of_author":    "ผู้เขียน {author} ยังมีหนังสืออยู่ในถังขยะ",
+		"imprints_of_publisher":      "สำนักพิมพ์ {publisher} ยังมีสำนักพิมพ์ในเครือ",
+		"books_of_publisher":         "สำนักพิมพ์ {publisher} ยังมีหนังสืออยู่",
+		"trashed_books_of_publisher": "สำนักพิมพ์ {publisher} ยังมีหนังสืออยู่ในถังขยะ",
+		"books_of_series":            "ชุดหนังสือ {series} ยังมีหนังสืออยู่",
+		"trashed_books_of_series":    "ชุดหนังสือ {series} ยังมีหนังสืออยู่ในถังขยะ",
+		"editions_of_work":           "ผลงาน {work} ยังมีฉบับพิมพ์อยู่",
+		"trashed_editions_of_work":   "ผลงาน {work} ยังมีฉบับพิมพ์อยู่ในถังขยะ",
+
+		"field.required": "ต้องระบุ {field}",
+		"field.min":      "{field} ต้องมีค่าอย่างน้อย {param}",
+		"field.max":      "{field} ต้องมีค่าไม่เกิน {param}",
+		"field.len":      "{field} ต้องมีความยาว {param}",
+		"field.oneof":    "{field} ต้องเป็นค่าใดค่าหนึ่งใน [{param}]",
+		"field.type":     "{field} ต้องเป็นชนิด {type}",
+		"field.invalid":  "{field} ไม่ผ่านเงื่อนไข {rule}",
+
+		"field.bcp47_language_tag": "{field} ต้องเป็นแท็กภาษาตาม BCP 47",
+		"field.isbn":               "{field} ต้องเป็น ISBN-10 หรือ ISBN-13 ที่มีเลขตรวจสอบถูกต้อง",
+		"field.publication_date":   "{field} ต้องเป็นปี เดือน หรือวันในรูปแบบ YYYY, YYYY-MM หรือ YYYY-MM-DD ตั้งแต่ปี {min} เป็นต้นไป",
+
+		"field.format":               "{field} ต้องเป็น {param} ที่ถูกต้อง",
+		"field.const":                "{field} ต้องเท่ากับ {param}",
+		"field.enum":                 "{field} ต้องเป็นค่าใดค่าหนึ่งใน {param}",
+		"field.pattern":              "{field} ต้องตรงกับรูปแบบ {param}",
+		"field.minLength":            "{field} ต้องยาวอย่างน้อย {param} ตัวอักษร",
+		"field.maxLength":            "{field} ต้องยาวไม่เกิน {param} ตัวอักษร",
+		"field.minimum":              "{field} ต้องมีค่ามากกว่าหรือเท่ากับ {param}",
+		"field.maximum":              "{field} ต้องมีค่าน้อยกว่าหรือเท่ากับ {param}",
+		"field.minItems":             "{field} ต้องมีอย่างน้อย {param} รายการ",
+		"field.maxItems":             "{field} ต้องมีไม่เกิน {param} รายการ",
+		"field.oneOf":                "{field} ต้องตรงกับ schema เพียงหนึ่งเดียว แต่ตรงกับ {param}",
+		"field.additionalProperties": "ไม่อนุญาตให้ระบุ {field}",
+		"field.status":               "{field} สถานะ {param} ไม่ได้ระบุไว้ในเอกสาร API",
+		"field.contentType":          "{field} ชนิดเนื้อหา {param} ไม่ได้ระบุไว้ในเอกสาร API",
+	},
+}
+
+// Supported reports whether lang has a message catalog
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// translate renders the message for key in lang, falling back to the
+// default language, returns false when no catalog knows the key
+func translate(lang string, key string, params Params) (string, bool) {
+	tmpl, ok := catalogs[lang][key]
+	if !ok {
+		tmpl, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		return "", false
+	}
+
+	pairs := make([]string, 0, len(params)*2)
+	for k, v := range params {
+		pairs = append(pairs, "{"+k+"}", fmt.Sprint(v))
+	}
+
+	return strings.NewReplacer(pairs...).Replace(tmpl), true
+}
@@ -0,0 +1,60 @@
+package apperror
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalize(t *testing.T) {
+	t.Run("Every code is translated", func(t *testing.T) {
+		for key := range catalogs[DefaultLanguage] {
+			for lang, catalog := range catalogs {
+				assert.Contains(t, catalog, key, "%s is missing from the %s catalog", key, lang)
+			}
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		err := NewConflict("book", "title")
+
+		assert.Equal(t, "conflict", err.Code)
+		assert.Equal(t, "resource: book with value: title already exists", err.Error())
+		assert.Equal(t, "book ที่มีค่า title มีอยู่แล้ว", err.Localize(Thai))
+		assert.Equal(t, err.Error(), err.Localize("fr"))
+	})
+
+	t.Run("Error with its own code", func(t *testing.T) {
+		err := NewBadRequestCode("invalid_isbn", Params{"isbn": "123"})
+
+		assert.Equal(t, BadRequest, err.Type)
+		assert.Equal(t, "invalid_isbn", err.Code)
+		assert.Equal(t, "123 is not an ISBN-10 or ISBN-13 with a valid check digit", err.Error())
+		assert.Equal(t, "123 ไม่ใช่ ISBN-10 หรือ ISBN-13 ที่มีเลขตรวจสอบถูกต้อง", err.Localize(Thai))
+	})
+
+	t.Run("Error without code", func(t *testing.T) {
+		err := &Error{Type: Internal, Message: "custom"}
+
+		assert.Equal(t, "custom", err.Localize(Thai))
+	})
+
+	t.Run("FieldError", func(t *testing.T) {
+		f := NewFieldError("body", "pages", "/pages", "min", 0, Params{"param": "1"})
+
+		assert.Equal(t, "pages must be at least 1", f.Message)
+		assert.Equal(t, "pages ต้องมีค่าอย่างน้อย 1", f.Localize(Thai))
+	})
+
+	t.Run("FieldError - unknown rule", func(t *testing.T) {
+		f := NewFieldError("body", "card", "/card", "luhn_checksum", "123", Params{"param": ""})
+
+		assert.Equal(t, "card failed on the luhn_checksum rule", f.Message)
+	})
+
+	t.Run("FieldError - not localizable", func(t *testing.T) {
+		f := FieldError{Field: "id", Rule: "format", Message: "must be a valid uuid"}
+
+		assert.Equal(t, "must be a valid uuid", f.Localize(Thai))
+	})
+}
@@ -0,0 +1,82 @@
+// Package applog carries the request ID in the context and adds it to
+// every log record written with that context
+package applog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Formats of the log output
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, if any
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// New builds a logger writing to w in format, json (default) or text,
+// from level on, debug, info (default), warn or error. Records logged with a context carrying
+// a request ID get a request_id attribute.
+func New(w io.Writer, level string, format string) (*slog.Logger, error) {
+	var l slog.Level
+	if level == "" {
+		level = l.String()
+	}
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("unknown log level %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: l}
+
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "", FormatJSON:
+		h = slog.NewJSONHandler(w, opts)
+	case FormatText:
+		h = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+
+	return slog.New(NewHandler(h)), nil
+}
+
+// handler adds the request ID of the context to the records
+type handler struct {
+	slog.Handler
+}
+
+// NewHandler wraps h to add the request ID of the context to each record
+func NewHandler(h slog.Handler) slog.Handler {
+	return &handler{Handler: h}
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &handler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{Handler: h.Handler.WithGroup(name)}
+}
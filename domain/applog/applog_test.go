@@ -0,0 +1,64 @@
+package applog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Request ID", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, "", "")
+		assert.NoError(t, err)
+
+		logger.InfoContext(WithRequestID(context.Background(), "req-1"), "book created", "book_id", "1")
+
+		var record map[string]interface{}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, "req-1", record["request_id"])
+		assert.Equal(t, "1", record["book_id"])
+	})
+
+	t.Run("Request ID with attributes", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, _ := New(&buf, "info", FormatText)
+
+		logger.With("component", "repository").InfoContext(WithRequestID(context.Background(), "req-1"), "stored")
+
+		assert.Contains(t, buf.String(), "component=repository")
+		assert.Contains(t, buf.String(), "request_id=req-1")
+	})
+
+	t.Run("Without request ID", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, _ := New(&buf, "info", FormatJSON)
+
+		logger.Info("starting")
+
+		assert.NotContains(t, buf.String(), "request_id")
+	})
+
+	t.Run("Level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, _ := New(&buf, "WARN", FormatJSON)
+
+		logger.Info("hidden")
+		logger.Warn("shown")
+
+		assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+		assert.Contains(t, buf.String(), "shown")
+	})
+
+	t.Run("Invalid configuration", func(t *testing.T) {
+		_, err := New(&bytes.Buffer{}, "loud", FormatJSON)
+		assert.Error(t, err)
+
+		_, err = New(&bytes.Buffer{}, "info", "xml")
+		assert.Error(t, err)
+	})
+}
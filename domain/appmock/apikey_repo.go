@@ -0,0 +1,38 @@
+package appmock
+
+import (
+	"context"
+	"time"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) FetchAPIKeys(ctx context.Context) (*[]domain.APIKey, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*[]domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	args := m.Called(ctx, hash)
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchAPIKey(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
@@ -0,0 +1,32 @@
+package appmock
+
+import (
+	"context"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyUseCase) FetchAPIKeys(ctx context.Context) (*[]domain.APIKey, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*[]domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyUseCase) IssueAPIKey(ctx context.Context, name string, scopes []string) (*domain.IssuedAPIKey, error) {
+	args := m.Called(ctx, name, scopes)
+	return args.Get(0).(*domain.IssuedAPIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyUseCase) RevokeAPIKey(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyUseCase) AuthenticateAPIKey(ctx context.Context, key string) (*domain.APIKey, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
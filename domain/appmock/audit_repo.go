@@ -0,0 +1,22 @@
+package appmock
+
+import (
+	"context"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) AppendAuditEntry(ctx context.Context, entry *domain.AuditEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) FetchAuditEntries(ctx context.Context, filter domain.AuditFilter) (*[]domain.AuditEntry, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*[]domain.AuditEntry), args.Error(1)
+}
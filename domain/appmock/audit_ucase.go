@@ -0,0 +1,17 @@
+package appmock
+
+import (
+	"context"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAuditUseCase) FetchAuditEntries(ctx context.Context, filter domain.AuditFilter) (*[]domain.AuditEntry, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*[]domain.AuditEntry), args.Error(1)
+}
@@ -0,0 +1,42 @@
+package appmock
+
+import (
+	"context"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuthorRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuthorRepository) FetchAuthors(ctx context.Context, filter domain.AuthorFilter) (*[]domain.Author, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*[]domain.Author), args.Error(1)
+}
+
+func (m *MockAuthorRepository) GetAuthorByID(ctx context.Context, id string) (*domain.Author, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.Author), args.Error(1)
+}
+
+func (m *MockAuthorRepository) GetAuthorByName(ctx context.Context, name string) (*domain.Author, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).(*domain.Author), args.Error(1)
+}
+
+func (m *MockAuthorRepository) CreateAuthor(ctx context.Context, author *domain.Author) error {
+	args := m.Called(ctx, author)
+	return args.Error(0)
+}
+
+func (m *MockAuthorRepository) UpdateAuthor(ctx context.Context, id string, author *domain.Author) error {
+	args := m.Called(ctx, id, author)
+	return args.Error(0)
+}
+
+func (m *MockAuthorRepository) DeleteAuthor(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,47 @@
+package appmock
+
+import (
+	"context"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuthorUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAuthorUseCase) FetchAuthors(ctx context.Context, filter domain.AuthorFilter) (*[]domain.Author, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*[]domain.Author), args.Error(1)
+}
+
+func (m *MockAuthorUseCase) GetAuthorByID(ctx context.Context, id string) (*domain.Author, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.Author), args.Error(1)
+}
+
+func (m *MockAuthorUseCase) CreateAuthor(ctx context.Context, author *domain.Author) error {
+	args := m.Called(ctx, author)
+	return args.Error(0)
+}
+
+func (m *MockAuthorUseCase) UpdateAuthor(ctx context.Context, id string, author *domain.Author) error {
+	args := m.Called(ctx, id, author)
+	return args.Error(0)
+}
+
+func (m *MockAuthorUseCase) DeleteAuthor(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAuthorUseCase) FetchAuthorBooks(ctx context.Context, id string) (*[]domain.Book, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*[]domain.Book), args.Error(1)
+}
+
+func (m *MockAuthorUseCase) MigrateAuthors(ctx context.Context) (*domain.AuthorMigration, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*domain.AuthorMigration), args.Error(1)
+}
@@ -2,7 +2,9 @@ package appmock
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/krittawatcode/books/domain"
 	"github.com/stretchr/testify/mock"
 )
@@ -16,8 +18,8 @@ func (m *MockBookRepository) CreateBook(ctx context.Context, book *domain.Book)
 	return args.Error(0)
 }
 
-func (m *MockBookRepository) FetchBooks(ctx context.Context) (*[]domain.Book, error) {
-	args := m.Called(ctx)
+func (m *MockBookRepository) FetchBooks(ctx context.Context, filter domain.BookFilter) (*[]domain.Book, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).(*[]domain.Book), args.Error(1)
 }
 
@@ -26,12 +28,52 @@ func (m *MockBookRepository) GetBookByID(ctx context.Context, id string) (*domai
 	return args.Get(0).(*domain.Book), args.Error(1)
 }
 
+func (m *MockBookRepository) GetBookByISBN(ctx context.Context, isbn string) (*domain.Book, error) {
+	args := m.Called(ctx, isbn)
+	return args.Get(0).(*domain.Book), args.Error(1)
+}
+
+func (m *MockBookRepository) GetSeriesNeighbours(ctx context.Context, id string) ([]domain.SeriesEntry, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]domain.SeriesEntry), args.Error(1)
+}
+
 func (m *MockBookRepository) UpdateBook(ctx context.Context, id string, book *domain.Book) error {
 	args := m.Called(ctx, book)
 	return args.Error(0)
 }
 
-func (m *MockBookRepository) DeleteBook(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockBookRepository) DeleteBook(ctx context.Context, id string, deletedBy string) error {
+	args := m.Called(ctx, id, deletedBy)
 	return args.Error(0)
 }
+
+func (m *MockBookRepository) FetchBookHistory(ctx context.Context, id string) (*[]domain.BookRevision, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*[]domain.BookRevision), args.Error(1)
+}
+
+func (m *MockBookRepository) GetBookAsOf(ctx context.Context, id string, at time.Time) (*domain.Book, error) {
+	args := m.Called(ctx, id, at)
+	return args.Get(0).(*domain.Book), args.Error(1)
+}
+
+func (m *MockBookRepository) RevertBook(ctx context.Context, id string, revision int) (*domain.Book, error) {
+	args := m.Called(ctx, id, revision)
+	return args.Get(0).(*domain.Book), args.Error(1)
+}
+
+func (m *MockBookRepository) FetchTrash(ctx context.Context) (*[]domain.TrashedBook, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*[]domain.TrashedBook), args.Error(1)
+}
+
+func (m *MockBookRepository) RestoreBook(ctx context.Context, id string) (*domain.Book, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.Book), args.Error(1)
+}
+
+func (m *MockBookRepository) PurgeTrash(ctx context.Context, deletedBefore time.Time) ([]uuid.UUID, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
@@ -2,6 +2,7 @@ package appmock
 
 import (
 	"context"
+	"time"
 
 	"github.com/krittawatcode/books/domain"
 	"github.com/stretchr/testify/mock"
@@ -11,8 +12,8 @@ type MockBookUseCase struct {
 	mock.Mock
 }
 
-func (m *MockBookUseCase) FetchBooks(ctx context.Context) (*[]domain.Book, error) {
-	args := m.Called(ctx)
+func (m *MockBookUseCase) FetchBooks(ctx context.Context, filter domain.BookFilter) (*[]domain.Book, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).(*[]domain.Book), args.Error(1)
 }
 
@@ -21,6 +22,11 @@ func (m *MockBookUseCase) GetBookByID(ctx context.Context, id string) (*domain.B
 	return args.Get(0).(*domain.Book), args.Error(1)
 }
 
+func (m *MockBookUseCase) GetBookByISBN(ctx context.Context, isbn string) (*domain.Book, error) {
+	args := m.Called(ctx, isbn)
+	return args.Get(0).(*domain.Book), args.Error(1)
+}
+
 func (m *MockBookUseCase) CreateBook(ctx context.Context, book *domain.Book) error {
 	args := m.Called(ctx, book)
 	return args.Error(0)
@@ -35,3 +41,33 @@ func (m *MockBookUseCase) DeleteBook(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
+
+func (m *MockBookUseCase) FetchBookHistory(ctx context.Context, id string) (*[]domain.BookRevision, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*[]domain.BookRevision), args.Error(1)
+}
+
+func (m *MockBookUseCase) GetBookAsOf(ctx context.Context, id string, at time.Time) (*domain.Book, error) {
+	args := m.Called(ctx, id, at)
+	return args.Get(0).(*domain.Book), args.Error(1)
+}
+
+func (m *MockBookUseCase) RevertBook(ctx context.Context, id string, revision int) (*domain.Book, error) {
+	args := m.Called(ctx, id, revision)
+	return args.Get(0).(*domain.Book), args.Error(1)
+}
+
+func (m *MockBookUseCase) FetchTrash(ctx context.Context) (*[]domain.TrashedBook, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*[]domain.TrashedBook), args.Error(1)
+}
+
+func (m *MockBookUseCase) HasTrashedBooks(ctx context.Context, filter domain.BookFilter) (bool, error) {
+	args := m.Called(ctx, filter)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBookUseCase) RestoreBook(ctx context.Context, id string) (*domain.Book, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.Book), args.Error(1)
+}
@@ -0,0 +1,53 @@
+package appmock
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCopyRepository struct {
+	mock.Mock
+}
+
+func (m *MockCopyRepository) FetchCopies(ctx context.Context, filter domain.CopyFilter) (*[]domain.Copy, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*[]domain.Copy), args.Error(1)
+}
+
+func (m *MockCopyRepository) GetCopyByID(ctx context.Context, id string) (*domain.Copy, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.Copy), args.Error(1)
+}
+
+func (m *MockCopyRepository) GetCopyByBarcode(ctx context.Context, barcode string) (*domain.Copy, error) {
+	args := m.Called(ctx, barcode)
+	return args.Get(0).(*domain.Copy), args.Error(1)
+}
+
+func (m *MockCopyRepository) CreateCopy(ctx context.Context, cp *domain.Copy) error {
+	args := m.Called(ctx, cp)
+	return args.Error(0)
+}
+
+func (m *MockCopyRepository) UpdateCopy(ctx context.Context, id string, cp *domain.Copy) error {
+	args := m.Called(ctx, id, cp)
+	return args.Error(0)
+}
+
+func (m *MockCopyRepository) DeleteCopy(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCopyRepository) CountCopies(ctx context.Context, bookIDs []uuid.UUID) (map[uuid.UUID]domain.CopyCounts, error) {
+	args := m.Called(ctx, bookIDs)
+	return args.Get(0).(map[uuid.UUID]domain.CopyCounts), args.Error(1)
+}
+
+func (m *MockCopyRepository) DeleteBookCopies(ctx context.Context, bookIDs []uuid.UUID) (int, error) {
+	args := m.Called(ctx, bookIDs)
+	return args.Int(0), args.Error(1)
+}
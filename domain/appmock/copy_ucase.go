@@ -0,0 +1,42 @@
+package appmock
+
+import (
+	"context"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCopyUseCase struct {
+	mock.Mock
+}
+
+func (m *MockCopyUseCase) FetchCopies(ctx context.Context, bookID string, filter domain.CopyFilter) (*[]domain.Copy, error) {
+	args := m.Called(ctx, bookID, filter)
+	return args.Get(0).(*[]domain.Copy), args.Error(1)
+}
+
+func (m *MockCopyUseCase) GetCopyByID(ctx context.Context, bookID string, id string) (*domain.Copy, error) {
+	args := m.Called(ctx, bookID, id)
+	return args.Get(0).(*domain.Copy), args.Error(1)
+}
+
+func (m *MockCopyUseCase) GetCopyByBarcode(ctx context.Context, barcode string) (*domain.Copy, error) {
+	args := m.Called(ctx, barcode)
+	return args.Get(0).(*domain.Copy), args.Error(1)
+}
+
+func (m *MockCopyUseCase) CreateCopy(ctx context.Context, bookID string, cp *domain.Copy) error {
+	args := m.Called(ctx, bookID, cp)
+	return args.Error(0)
+}
+
+func (m *MockCopyUseCase) UpdateCopy(ctx context.Context, bookID string, id string, cp *domain.Copy) error {
+	args := m.Called(ctx, bookID, id, cp)
+	return args.Error(0)
+}
+
+func (m *MockCopyUseCase) DeleteCopy(ctx context.Context, bookID string, id string) error {
+	args := m.Called(ctx, bookID, id)
+	return args.Error(0)
+}
@@ -0,0 +1,37 @@
+package appmock
+
+import (
+	"context"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPublisherRepository struct {
+	mock.Mock
+}
+
+func (m *MockPublisherRepository) FetchPublishers(ctx context.Context, filter domain.PublisherFilter) (*[]domain.Publisher, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*[]domain.Publisher), args.Error(1)
+}
+
+func (m *MockPublisherRepository) GetPublisherByID(ctx context.Context, id string) (*domain.Publisher, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.Publisher), args.Error(1)
+}
+
+func (m *MockPublisherRepository) CreatePublisher(ctx context.Context, publisher *domain.Publisher) error {
+	args := m.Called(ctx, publisher)
+	return args.Error(0)
+}
+
+func (m *MockPublisherRepository) UpdatePublisher(ctx context.Context, id string, publisher *domain.Publisher) error {
+	args := m.Called(ctx, id, publisher)
+	return args.Error(0)
+}
+
+func (m *MockPublisherRepository) DeletePublisher(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,42 @@
+package appmock
+
+import (
+	"context"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPublisherUseCase struct {
+	mock.Mock
+}
+
+func (m *MockPublisherUseCase) FetchPublishers(ctx context.Context, filter domain.PublisherFilter) (*[]domain.Publisher, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*[]domain.Publisher), args.Error(1)
+}
+
+func (m *MockPublisherUseCase) GetPublisherByID(ctx context.Context, id string) (*domain.Publisher, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.Publisher), args.Error(1)
+}
+
+func (m *MockPublisherUseCase) CreatePublisher(ctx context.Context, publisher *domain.Publisher) error {
+	args := m.Called(ctx, publisher)
+	return args.Error(0)
+}
+
+func (m *MockPublisherUseCase) UpdatePublisher(ctx context.Context, id string, publisher *domain.Publisher) error {
+	args := m.Called(ctx, id, publisher)
+	return args.Error(0)
+}
+
+func (m *MockPublisherUseCase) DeletePublisher(ctx context.Context, id string, cascade bool) error {
+	args := m.Called(ctx, id, cascade)
+	return args.Error(0)
+}
+
+func (m *MockPublisherUseCase) FetchPublisherBooks(ctx context.Context, id string) (*[]domain.Book, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*[]domain.Book), args.Error(1)
+}
@@ -0,0 +1,37 @@
+package appmock
+
+import (
+	"context"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSeriesRepository struct {
+	mock.Mock
+}
+
+func (m *MockSeriesRepository) FetchSeries(ctx context.Context, filter domain.SeriesFilter) (*[]domain.Series, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*[]domain.Series), args.Error(1)
+}
+
+func (m *MockSeriesRepository) GetSeriesByID(ctx context.Context, id string) (*domain.Series, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.Series), args.Error(1)
+}
+
+func (m *MockSeriesRepository) CreateSeries(ctx context.Context, series *domain.Series) error {
+	args := m.Called(ctx, series)
+	return args.Error(0)
+}
+
+func (m *MockSeriesRepository) UpdateSeries(ctx context.Context, id string, series *domain.Series) error {
+	args := m.Called(ctx, id, series)
+	return args.Error(0)
+}
+
+func (m *MockSeriesRepository) DeleteSeries(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
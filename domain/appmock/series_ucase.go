@@ -0,0 +1,42 @@
+package appmock
+
+import (
+	"context"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSeriesUseCase struct {
+	mock.Mock
+}
+
+func (m *MockSeriesUseCase) FetchSeries(ctx context.Context, filter domain.SeriesFilter) (*[]domain.Series, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*[]domain.Series), args.Error(1)
+}
+
+func (m *MockSeriesUseCase) GetSeriesByID(ctx context.Context, id string) (*domain.Series, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.Series), args.Error(1)
+}
+
+func (m *MockSeriesUseCase) CreateSeries(ctx context.Context, series *domain.Series) error {
+	args := m.Called(ctx, series)
+	return args.Error(0)
+}
+
+func (m *MockSeriesUseCase) UpdateSeries(ctx context.Context, id string, series *domain.Series) error {
+	args := m.Called(ctx, id, series)
+	return args.Error(0)
+}
+
+func (m *MockSeriesUseCase) DeleteSeries(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSeriesUseCase) FetchSeriesBooks(ctx context.Context, id string) (*[]domain.Book, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*[]domain.Book), args.Error(1)
+}
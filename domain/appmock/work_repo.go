@@ -0,0 +1,37 @@
+package appmock
+
+import (
+	"context"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWorkRepository struct {
+	mock.Mock
+}
+
+func (m *MockWorkRepository) FetchWorks(ctx context.Context, filter domain.WorkFilter) (*[]domain.Work, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*[]domain.Work), args.Error(1)
+}
+
+func (m *MockWorkRepository) GetWorkByID(ctx context.Context, id string) (*domain.Work, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.Work), args.Error(1)
+}
+
+func (m *MockWorkRepository) CreateWork(ctx context.Context, work *domain.Work) error {
+	args := m.Called(ctx, work)
+	return args.Error(0)
+}
+
+func (m *MockWorkRepository) UpdateWork(ctx context.Context, id string, work *domain.Work) error {
+	args := m.Called(ctx, id, work)
+	return args.Error(0)
+}
+
+func (m *MockWorkRepository) DeleteWork(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,57 @@
+package appmock
+
+import (
+	"context"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWorkUseCase struct {
+	mock.Mock
+}
+
+func (m *MockWorkUseCase) FetchWorks(ctx context.Context, filter domain.WorkFilter) (*[]domain.Work, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*[]domain.Work), args.Error(1)
+}
+
+func (m *MockWorkUseCase) GetWorkByID(ctx context.Context, id string) (*domain.Work, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*domain.Work), args.Error(1)
+}
+
+func (m *MockWorkUseCase) CreateWork(ctx context.Context, work *domain.Work) error {
+	args := m.Called(ctx, work)
+	return args.Error(0)
+}
+
+func (m *MockWorkUseCase) UpdateWork(ctx context.Context, id string, work *domain.Work) error {
+	args := m.Called(ctx, id, work)
+	return args.Error(0)
+}
+
+func (m *MockWorkUseCase) DeleteWork(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWorkUseCase) FetchEditions(ctx context.Context, id string) (*[]domain.Book, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*[]domain.Book), args.Error(1)
+}
+
+func (m *MockWorkUseCase) SuggestWorks(ctx context.Context, minScore float64) (*[]domain.WorkSuggestion, error) {
+	args := m.Called(ctx, minScore)
+	return args.Get(0).(*[]domain.WorkSuggestion), args.Error(1)
+}
+
+func (m *MockWorkUseCase) MergeWorks(ctx context.Context, id string, merge *domain.WorkMerge) (*domain.Work, error) {
+	args := m.Called(ctx, id, merge)
+	return args.Get(0).(*domain.Work), args.Error(1)
+}
+
+func (m *MockWorkUseCase) SplitWork(ctx context.Context, id string, split *domain.WorkSplit) (*domain.Work, error) {
+	args := m.Called(ctx, id, split)
+	return args.Get(0).(*domain.Work), args.Error(1)
+}
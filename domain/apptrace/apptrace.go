@@ -0,0 +1,37 @@
+// Package apptrace records the outcome of operations on their spans
+package apptrace
+
+import (
+	"github.com/krittawatcode/books/domain/apperror"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName names the tracers of this module
+const InstrumentationName = "github.com/krittawatcode/books"
+
+// Attributes describing an apperror
+const (
+	ErrorTypeKey = attribute.Key("app.error.type")
+	ErrorCodeKey = attribute.Key("app.error.code")
+)
+
+// End records err, if any, on span and ends it
+func End(span trace.Span, err error) {
+	RecordError(span, err)
+	span.End()
+}
+
+// RecordError marks span as failed with the apperror type of err,
+// errors which are not apperrors are recorded as internal
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	e := apperror.Wrap(err)
+	span.SetAttributes(ErrorTypeKey.String(string(e.Type)), ErrorCodeKey.String(e.Code))
+	span.RecordError(err)
+	span.SetStatus(codes.Error, string(e.Type))
+}
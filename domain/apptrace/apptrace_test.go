@@ -0,0 +1,45 @@
+package apptrace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEnd(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+		wantType string
+	}{
+		{name: "Success", err: nil, wantCode: codes.Unset},
+		{name: "AppError", err: apperror.NewConflict("title", "Go"), wantCode: codes.Error, wantType: string(apperror.Conflict)},
+		{name: "OtherError", err: errors.New("connection refused"), wantCode: codes.Error, wantType: string(apperror.Internal)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+			_, span := tp.Tracer("test").Start(context.Background(), "op")
+			End(span, tt.err)
+
+			spans := sr.Ended()
+			assert.Len(t, spans, 1)
+			assert.Equal(t, tt.wantCode, spans[0].Status().Code)
+			assert.Equal(t, tt.wantType, spans[0].Status().Description)
+			if tt.err != nil {
+				assert.Contains(t, spans[0].Attributes(), ErrorTypeKey.String(tt.wantType))
+				assert.Len(t, spans[0].Events(), 1)
+			}
+		})
+	}
+}
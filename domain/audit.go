@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction is the kind of change an AuditEntry or a BookRevision
+// records
+type AuditAction string
+
+const (
+	AuditActionCreate  AuditAction = "create"
+	AuditActionUpdate  AuditAction = "update"
+	AuditActionDelete  AuditAction = "delete"
+	AuditActionRevert  AuditAction = "revert"
+	AuditActionRestore AuditAction = "restore"
+)
+
+// ActorAnonymous is the actor of changes made without credentials
+const ActorAnonymous = "anonymous"
+
+// FieldChange is the value of a book field before and after a change,
+// nil when the book did not exist
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditEntry records who changed which book and how, entries are never
+// updated or removed
+type AuditEntry struct {
+	ID         uuid.UUID     `json:"id"`
+	Time       time.Time     `json:"time"`
+	Action     AuditAction   `json:"action"`
+	BookID     uuid.UUID     `json:"book_id"`
+	Actor      string        `json:"actor"`                 // subject of the Principal, or ActorAnonymous
+	AuthMethod string        `json:"auth_method,omitempty"` // eg. AuthMethodJWT
+	RequestID  string        `json:"request_id,omitempty"`
+	Changes    []FieldChange `json:"changes"`
+}
+
+// AuditFilter selects audit entries, zero fields match everything.
+// From is inclusive and To exclusive
+type AuditFilter struct {
+	BookID string
+	Actor  string
+	From   time.Time
+	To     time.Time
+}
+
+// Match reports whether e is selected by f
+func (f AuditFilter) Match(e *AuditEntry) bool {
+	switch {
+	case f.BookID != "" && e.BookID.String() != f.BookID:
+		return false
+	case f.Actor != "" && e.Actor != f.Actor:
+		return false
+	case !f.From.IsZero() && e.Time.Before(f.From):
+		return false
+	case !f.To.IsZero() && !e.Time.Before(f.To):
+		return false
+	}
+	return true
+}
+
+type AuditUseCase interface {
+	FetchAuditEntries(ctx context.Context, filter AuditFilter) (*[]AuditEntry, error)
+}
+
+// AuditRepository stores the audit entries in the order they are
+// appended, it has no way to change them
+type AuditRepository interface {
+	AppendAuditEntry(ctx context.Context, entry *AuditEntry) error
+	FetchAuditEntries(ctx context.Context, filter AuditFilter) (*[]AuditEntry, error)
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ContributorRole is what an author did for a book
+type ContributorRole string
+
+const (
+	ContributorAuthor      ContributorRole = "author"
+	ContributorEditor      ContributorRole = "editor"
+	ContributorTranslator  ContributorRole = "translator"
+	ContributorIllustrator ContributorRole = "illustrator"
+)
+
+// ContributorRoles lists every ContributorRole
+var ContributorRoles = []ContributorRole{ContributorAuthor, ContributorEditor, ContributorTranslator, ContributorIllustrator}
+
+// Author is a person credited for books, linked to them through the
+// Contributors of each book
+type Author struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `binding:"required,max=255" json:"name"`
+	Bio  string    `binding:"omitempty,max=10000" json:"bio,omitempty"`
+}
+
+// Contributor links a book to an author in a role, an author may hold
+// several roles for the same book
+type Contributor struct {
+	AuthorID uuid.UUID       `binding:"required" json:"author_id"`
+	Role     ContributorRole `binding:"required,oneof=author editor translator illustrator" json:"role"`
+}
+
+// AuthorKey folds the spelling variants of a name together, ignoring
+// case, spacing, punctuation and Unicode normalization, so "J.K. Rowling"
+// and "j. k. rowling" have the same key
+func AuthorKey(name string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKC.String(name) {
+		if keyRune(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// keyRune reports whether r counts in a key: letters, digits and marks,
+// as the vowel and tone marks of Thai tell names apart
+func keyRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// AuthorFilter selects authors, the zero value matches every author
+type AuthorFilter struct {
+	Name string // part of the name, case-insensitive
+}
+
+// Match reports whether a is selected by f
+func (f AuthorFilter) Match(a *Author) bool {
+	return f.Name == "" || containsFold(a.Name, f.Name)
+}
+
+// AuthorMigration reports the books whose author string was converted
+// to contributors
+type AuthorMigration struct {
+	Books   int                      `json:"books"`   // books given contributors
+	Authors int                      `json:"authors"` // authors created
+	Failed  []AuthorMigrationFailure `json:"failed"`
+}
+
+// AuthorMigrationFailure is a book which could not be migrated
+type AuthorMigrationFailure struct {
+	BookID uuid.UUID `json:"book_id"`
+	Error  string    `json:"error"`
+}
+
+type AuthorUseCase interface {
+	FetchAuthors(ctx context.Context, filter AuthorFilter) (*[]Author, error)
+	GetAuthorByID(ctx context.Context, id string) (*Author, error)
+	CreateAuthor(ctx context.Context, author *Author) error
+	UpdateAuthor(ctx context.Context, id string, author *Author) error
+	// DeleteAuthor fails with a conflict while books are linked to the
+	// author
+	DeleteAuthor(ctx context.Context, id string) error
+	// FetchAuthorBooks lists the books the author contributed to
+	FetchAuthorBooks(ctx context.Context, id string) (*[]Book, error)
+	// MigrateAuthors links every book without contributors to the
+	// authors named by its Author string, creating the missing ones
+	MigrateAuthors(ctx context.Context) (*AuthorMigration, error)
+}
+
+type AuthorRepository interface {
+	// FetchAuthors lists the matching authors by name, empty when none
+	// match
+	FetchAuthors(ctx context.Context, filter AuthorFilter) (*[]Author, error)
+	GetAuthorByID(ctx context.Context, id string) (*Author, error)
+	// GetAuthorByName finds the author whose name has the AuthorKey of
+	// name
+	GetAuthorByName(ctx context.Context, name string) (*Author, error)
+	// CreateAuthor and UpdateAuthor reject an author with the AuthorKey
+	// of another one with a conflict
+	CreateAuthor(ctx context.Context, author *Author) error
+	UpdateAuthor(ctx context.Context, id string, author *Author) error
+	DeleteAuthor(ctx context.Context, id string) error
+}
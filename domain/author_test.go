@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorKey(t *testing.T) {
+	assert.Equal(t, "jkrowling", AuthorKey("J.K. Rowling"))
+	assert.Equal(t, AuthorKey("J.K. Rowling"), AuthorKey(" j. k.  rowling "))
+	assert.Equal(t, "gabrielgarcíamárquez", AuthorKey("Gabriel García Márquez"))
+	assert.NotEqual(t, AuthorKey("Gabriel García Márquez"), AuthorKey("Gabriel Garcia Marquez"))
+	// decomposed accents
+	assert.Equal(t, AuthorKey("Gabriel García Márquez"), AuthorKey("Gabriel Garci\u0301a Ma\u0301rquez"))
+	// Thai names differing only by a tone mark
+	assert.Equal(t, "บุญส่งศรีสุข", AuthorKey("บุญส่ง ศรีสุข"))
+	assert.NotEqual(t, AuthorKey("บุญส่ง ศรีสุข"), AuthorKey("บุญส้ง ศรีสุข"))
+}
+
+func TestBookFilter_AuthorID(t *testing.T) {
+	author := uuid.New()
+	book := &Book{Contributors: []Contributor{{AuthorID: uuid.New(), Role: ContributorAuthor}, {AuthorID: author, Role: ContributorTranslator}}}
+
+	assert.True(t, BookFilter{AuthorID: author}.Match(book))
+	assert.False(t, BookFilter{AuthorID: uuid.New()}.Match(book))
+	assert.True(t, BookFilter{}.Match(book))
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "context"
+
+// Permissions checked by the use cases
+const (
+	PermissionBooksRead     = "books:read"
+	PermissionBooksWrite    = "books:write"
+	PermissionBooksDelete   = "books:delete"
+	PermissionAPIKeysManage = "api_keys:manage"
+	PermissionAuditRead     = "audit:read"
+)
+
+// Permissions lists every permission checked by the use cases
+var Permissions = []string{PermissionBooksRead, PermissionBooksWrite, PermissionBooksDelete, PermissionAPIKeysManage, PermissionAuditRead}
+
+// Roles of the default policy
+const (
+	RoleAnonymous = "anonymous" // callers without credentials
+	RoleViewer    = "viewer"
+	RoleEditor    = "editor"
+	RoleAdmin     = "admin"
+)
+
+// Authorizer decides whether the caller of ctx holds a permission
+type Authorizer interface {
+	// Authorize returns nil when allowed, an Authorization error for
+	// anonymous callers and a Forbidden error otherwise
+	Authorize(ctx context.Context, permission string) error
+}
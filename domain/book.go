@@ -2,29 +2,208 @@ package domain
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// BookFormat is the physical or digital form of a book
+type BookFormat string
+
+const (
+	FormatHardcover BookFormat = "hardcover"
+	FormatPaperback BookFormat = "paperback"
+	FormatEbook     BookFormat = "ebook"
+	FormatAudio     BookFormat = "audio"
+)
+
+// BookFormats lists every BookFormat
+var BookFormats = []BookFormat{FormatHardcover, FormatPaperback, FormatEbook, FormatAudio}
+
+// Valid reports whether f is one of BookFormats
+func (f BookFormat) Valid() bool {
+	for _, format := range BookFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Book is a title of the catalog, only Title, Author and
+// PublicationDate are required
 type Book struct {
-	ID              uuid.UUID `json:"id"`
-	Title           string    `binding:"required" json:"title"`
-	Author          string    `binding:"required" json:"author"`
-	PublicationYear string    `binding:"required" json:"publication_year"`
+	ID              uuid.UUID       `json:"id"`
+	Title           string          `binding:"required" json:"title"`
+	Author          string          `binding:"required" json:"author"`
+	PublicationDate PublicationDate `binding:"required,publication_date" json:"publication_year"` // named for the clients of PublicationYear
+	ISBN            string          `binding:"omitempty,isbn" json:"isbn,omitempty"`              // ISBN-10 or ISBN-13, stored as 13 digits
+	Publisher       string          `binding:"omitempty,max=255" json:"publisher,omitempty"`
+	PublisherID     *uuid.UUID      `json:"publisher_id,omitempty"`                                    // the publisher of the catalog, Publisher stays the name as printed
+	Language        string          `binding:"omitempty,bcp47_language_tag" json:"language,omitempty"` // BCP 47 tag, eg. en-GB
+	Pages           int             `binding:"omitempty,min=1,max=100000" json:"pages,omitempty"`
+	Description     string          `binding:"omitempty,max=10000" json:"description,omitempty"`
+	Genres          []string        `binding:"omitempty,max=20,dive,required,max=64" json:"genres,omitempty"`
+	Subjects        []string        `binding:"omitempty,max=50,dive,required,max=128" json:"subjects,omitempty"`
+	Edition         string          `binding:"omitempty,max=64" json:"edition,omitempty"` // eg. 2nd
+	Format          BookFormat      `binding:"omitempty,oneof=hardcover paperback ebook audio" json:"format,omitempty"`
+	// Contributors are the authors of the catalog credited for the
+	// book, Author stays the name as printed
+	Contributors []Contributor `binding:"omitempty,max=50,dive" json:"contributors,omitempty"`
+	Series       []SeriesEntry `binding:"omitempty,max=10,dive" json:"series,omitempty"`
+	WorkID       *uuid.UUID    `json:"work_id,omitempty"` // the work the book is an edition of
+	// Copies counts the copies of the book when it is read, it is never
+	// stored
+	Copies *CopyCounts `json:"copies,omitempty"`
+}
+
+// BookFilter selects books, zero fields match every book. Strings match
+// case-insensitively, Genre and Subject match any of the book's
+type BookFilter struct {
+	Title     string // part of the title
+	Author    string // part of the author
+	ISBN      string
+	Publisher string
+	Language  string
+	Format    BookFormat
+	Genre     string
+	Subject   string
+	// PublishedAfter and PublishedBefore select the books dated wholly
+	// after or before a date, at the precision of each
+	PublishedAfter  PublicationDate
+	PublishedBefore PublicationDate
+	AuthorID        uuid.UUID // one of the contributors, in any role
+	PublisherID     uuid.UUID // the publisher itself, not its imprints
+	// SeriesID selects the books of a series, which the repository
+	// lists in reading order
+	SeriesID uuid.UUID
+	WorkID   uuid.UUID // the editions of a work
+}
+
+// Match reports whether b is selected by f
+func (f BookFilter) Match(b *Book) bool {
+	switch {
+	case f.Title != "" && !containsFold(b.Title, f.Title):
+		return false
+	case f.Author != "" && !containsFold(b.Author, f.Author):
+		return false
+	case f.ISBN != "" && !strings.EqualFold(b.ISBN, f.ISBN):
+		return false
+	case f.Publisher != "" && !strings.EqualFold(b.Publisher, f.Publisher):
+		return false
+	case f.Language != "" && !strings.EqualFold(b.Language, f.Language):
+		return false
+	case f.Format != "" && b.Format != f.Format:
+		return false
+	case f.Genre != "" && !anyFold(b.Genres, f.Genre):
+		return false
+	case f.Subject != "" && !anyFold(b.Subjects, f.Subject):
+		return false
+	case f.PublishedAfter != "" && (!b.PublicationDate.Valid() || b.PublicationDate.Start().Before(f.PublishedAfter.End())):
+		return false
+	case f.PublishedBefore != "" && (!b.PublicationDate.Valid() || b.PublicationDate.End().After(f.PublishedBefore.Start())):
+		return false
+	case f.AuthorID != uuid.Nil && !b.HasContributor(f.AuthorID):
+		return false
+	case f.PublisherID != uuid.Nil && (b.PublisherID == nil || *b.PublisherID != f.PublisherID):
+		return false
+	case f.SeriesID != uuid.Nil && !b.InSeries(f.SeriesID):
+		return false
+	case f.WorkID != uuid.Nil && (b.WorkID == nil || *b.WorkID != f.WorkID):
+		return false
+	}
+	return true
+}
+
+// HasContributor reports whether the author is one of the contributors
+// of b
+func (b *Book) HasContributor(authorID uuid.UUID) bool {
+	for _, c := range b.Contributors {
+		if c.AuthorID == authorID {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(s string, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func anyFold(values []string, v string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// TrashedBook is a deleted book, kept in the trash until it is restored
+// or purged
+type TrashedBook struct {
+	Book
+	DeletedAt time.Time `json:"deleted_at"`
+	DeletedBy string    `json:"deleted_by"` // actor of the delete, as in AuditEntry
+}
+
+// BookRevision is the state of a book after a change, revisions of a
+// book are numbered from 1. The revision of a deletion holds the state
+// the book was deleted in
+type BookRevision struct {
+	Revision int         `json:"revision"`
+	Time     time.Time   `json:"time"`
+	Action   AuditAction `json:"action"`
+	Book     Book        `json:"book"`
 }
 
 type BookUseCase interface {
-	FetchBooks(ctx context.Context) (*[]Book, error)
+	FetchBooks(ctx context.Context, filter BookFilter) (*[]Book, error)
 	GetBookByID(ctx context.Context, id string) (*Book, error)
+	// GetBookByISBN accepts an ISBN-10 or ISBN-13, hyphens allowed
+	GetBookByISBN(ctx context.Context, isbn string) (*Book, error)
 	CreateBook(ctx context.Context, book *Book) error
 	UpdateBook(ctx context.Context, id string, book *Book) error
+	// DeleteBook fails with a conflict while the book has active copies
 	DeleteBook(ctx context.Context, id string) error
+	FetchBookHistory(ctx context.Context, id string) (*[]BookRevision, error)
+	GetBookAsOf(ctx context.Context, id string, at time.Time) (*Book, error)
+	RevertBook(ctx context.Context, id string, revision int) (*Book, error)
+	FetchTrash(ctx context.Context) (*[]TrashedBook, error)
+	// HasTrashedBooks reports whether trashed books are selected by
+	// filter, for those who may read the books but not the trash
+	HasTrashedBooks(ctx context.Context, filter BookFilter) (bool, error)
+	RestoreBook(ctx context.Context, id string) (*Book, error)
 }
 
 type BookRepository interface {
-	FetchBooks(ctx context.Context) (*[]Book, error)
+	FetchBooks(ctx context.Context, filter BookFilter) (*[]Book, error)
 	GetBookByID(ctx context.Context, id string) (*Book, error)
+	// GetBookByISBN looks a book up by its normalized ISBN-13
+	GetBookByISBN(ctx context.Context, isbn string) (*Book, error)
+	// GetSeriesNeighbours returns the series entries of a book with the
+	// books read before and after it in each series set
+	GetSeriesNeighbours(ctx context.Context, id string) ([]SeriesEntry, error)
+	// CreateBook and UpdateBook reject a book with the ISBN of another
+	// one with a conflict
 	CreateBook(ctx context.Context, book *Book) error
 	UpdateBook(ctx context.Context, id string, book *Book) error
-	DeleteBook(ctx context.Context, id string) error
+	// DeleteBook moves a book to the trash, hiding it from the reads
+	DeleteBook(ctx context.Context, id string, deletedBy string) error
+	// FetchBookHistory lists the revisions of a book, deleted or not,
+	// oldest first
+	FetchBookHistory(ctx context.Context, id string) (*[]BookRevision, error)
+	// GetBookAsOf returns the book as it was at a point in time
+	GetBookAsOf(ctx context.Context, id string, at time.Time) (*Book, error)
+	// RevertBook restores a book, trashed or not, to the state of one of
+	// its revisions, recording a new revision
+	RevertBook(ctx context.Context, id string, revision int) (*Book, error)
+	// FetchTrash lists the trashed books, most recently deleted first
+	FetchTrash(ctx context.Context) (*[]TrashedBook, error)
+	// RestoreBook takes a book out of the trash
+	RestoreBook(ctx context.Context, id string) (*Book, error)
+	// PurgeTrash removes the books trashed before a point in time for
+	// good, history included, and returns the IDs of those removed
+	PurgeTrash(ctx context.Context, deletedBefore time.Time) ([]uuid.UUID, error)
 }
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CopyStatus is where a physical copy of a book stands
+type CopyStatus string
+
+const (
+	CopyAvailable CopyStatus = "available"
+	CopyOnLoan    CopyStatus = "on_loan"
+	CopyLost      CopyStatus = "lost"
+	CopyRepair    CopyStatus = "repair"
+)
+
+// CopyStatuses lists every CopyStatus
+var CopyStatuses = []CopyStatus{CopyAvailable, CopyOnLoan, CopyLost, CopyRepair}
+
+// Valid reports whether s is one of CopyStatuses
+func (s CopyStatus) Valid() bool {
+	for _, status := range CopyStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Active reports whether a copy in status s is still held, that is any
+// status but lost
+func (s CopyStatus) Active() bool {
+	return s != CopyLost
+}
+
+// Copy is a physical copy of a book, identified by the barcode on it
+type Copy struct {
+	ID              uuid.UUID  `json:"id"`
+	BookID          uuid.UUID  `json:"book_id"` // the book of the path
+	Barcode         string     `binding:"required,max=64" json:"barcode"`
+	Location        string     `binding:"omitempty,max=255" json:"location,omitempty"`  // shelf, eg. 2F-A12
+	Condition       string     `binding:"omitempty,max=255" json:"condition,omitempty"` // eg. good, spine creased
+	AcquisitionDate string     `binding:"omitempty,datetime=2006-01-02" json:"acquisition_date,omitempty"`
+	Status          CopyStatus `binding:"omitempty,oneof=available on_loan lost repair" json:"status"` // available when not given
+}
+
+// CopyFilter selects copies, zero fields match every copy
+type CopyFilter struct {
+	BookID uuid.UUID
+	Status CopyStatus
+}
+
+// Match reports whether c is selected by f
+func (f CopyFilter) Match(c *Copy) bool {
+	switch {
+	case f.BookID != uuid.Nil && c.BookID != f.BookID:
+		return false
+	case f.Status != "" && c.Status != f.Status:
+		return false
+	}
+	return true
+}
+
+// CopyCounts counts the copies of a book, lost copies left out
+type CopyCounts struct {
+	Available int `json:"available"`
+	Total     int `json:"total"`
+}
+
+// Add counts c unless it is lost
+func (n *CopyCounts) Add(c *Copy) {
+	n.add(c, 1)
+}
+
+// Remove takes c back out of the counts
+func (n *CopyCounts) Remove(c *Copy) {
+	n.add(c, -1)
+}
+
+func (n *CopyCounts) add(c *Copy, delta int) {
+	if !c.Status.Active() {
+		return
+	}
+	n.Total += delta
+	if c.Status == CopyAvailable {
+		n.Available += delta
+	}
+}
+
+type CopyUseCase interface {
+	// FetchCopies lists the copies of the book matching filter, whose
+	// BookID is the book's
+	FetchCopies(ctx context.Context, bookID string, filter CopyFilter) (*[]Copy, error)
+	GetCopyByID(ctx context.Context, bookID string, id string) (*Copy, error)
+	GetCopyByBarcode(ctx context.Context, barcode string) (*Copy, error)
+	CreateCopy(ctx context.Context, bookID string, cp *Copy) error
+	UpdateCopy(ctx context.Context, bookID string, id string, cp *Copy) error
+	DeleteCopy(ctx context.Context, bookID string, id string) error
+}
+
+type CopyRepository interface {
+	// FetchCopies lists the matching copies by barcode, empty when none
+	// match
+	FetchCopies(ctx context.Context, filter CopyFilter) (*[]Copy, error)
+	GetCopyByID(ctx context.Context, id string) (*Copy, error)
+	GetCopyByBarcode(ctx context.Context, barcode string) (*Copy, error)
+	// CreateCopy and UpdateCopy reject a copy with the barcode of another
+	// one with a conflict
+	CreateCopy(ctx context.Context, cp *Copy) error
+	UpdateCopy(ctx context.Context, id string, cp *Copy) error
+	DeleteCopy(ctx context.Context, id string) error
+	// CountCopies counts the copies of each of the books, a book without
+	// copies is left out
+	CountCopies(ctx context.Context, bookIDs []uuid.UUID) (map[uuid.UUID]CopyCounts, error)
+	// DeleteBookCopies removes every copy of the books and returns how
+	// many were removed
+	DeleteBookCopies(ctx context.Context, bookIDs []uuid.UUID) (int, error)
+}
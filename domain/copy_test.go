@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyStatus_Valid(t *testing.T) {
+	for _, s := range CopyStatuses {
+		assert.True(t, s.Valid(), s)
+	}
+	assert.False(t, CopyStatus("borrowed").Valid())
+	assert.False(t, CopyStatus("").Valid())
+}
+
+func TestCopyCounts_Add(t *testing.T) {
+	var n CopyCounts
+	for _, s := range []CopyStatus{CopyAvailable, CopyAvailable, CopyOnLoan, CopyRepair, CopyLost} {
+		n.Add(&Copy{Status: s})
+	}
+
+	assert.Equal(t, CopyCounts{Available: 2, Total: 4}, n)
+}
+
+func TestCopyFilter_Match(t *testing.T) {
+	book := uuid.New()
+	copy := &Copy{BookID: book, Status: CopyOnLoan}
+
+	assert.True(t, CopyFilter{}.Match(copy))
+	assert.True(t, CopyFilter{BookID: book, Status: CopyOnLoan}.Match(copy))
+	assert.False(t, CopyFilter{BookID: uuid.New()}.Match(copy))
+	assert.False(t, CopyFilter{Status: CopyAvailable}.Match(copy))
+}
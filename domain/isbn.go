@@ -0,0 +1,83 @@
+package domain
+
+import "strings"
+
+// NormalizeISBN validates the checksum of an ISBN-10 or ISBN-13, hyphens
+// and spaces allowed, and returns it as 13 digits. ISBN-10s are
+// converted to their 978 prefixed ISBN-13
+func NormalizeISBN(isbn string) (string, bool) {
+	digits := strings.NewReplacer("-", "", " ", "").Replace(isbn)
+
+	switch len(digits) {
+	case 10:
+		if !validISBN10(digits) {
+			return "", false
+		}
+		isbn13 := "978" + digits[:9]
+		return isbn13 + string(isbn13CheckDigit(isbn13)), true
+	case 13:
+		if !isDigits(digits) || (digits[:3] != "978" && digits[:3] != "979") {
+			return "", false
+		}
+		if isbn13CheckDigit(digits[:12]) != digits[12] {
+			return "", false
+		}
+		return digits, true
+	}
+
+	return "", false
+}
+
+// ValidISBN reports whether isbn is an ISBN-10 or ISBN-13 with a valid
+// checksum
+func ValidISBN(isbn string) bool {
+	_, ok := NormalizeISBN(isbn)
+	return ok
+}
+
+// validISBN10 checks the weighted sum of the 10 digits, the last of
+// which may be X for 10, is a multiple of 11
+func validISBN10(digits string) bool {
+	if !isDigits(digits[:9]) {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += (10 - i) * int(digits[i]-'0')
+	}
+	switch c := digits[9]; {
+	case c == 'X' || c == 'x':
+		sum += 10
+	case c >= '0' && c <= '9':
+		sum += int(c - '0')
+	default:
+		return false
+	}
+
+	return sum%11 == 0
+}
+
+// isbn13CheckDigit computes the check digit of the first 12 digits of
+// an ISBN-13, weighted 1 and 3 alternately
+func isbn13CheckDigit(digits string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		w := 1
+		if i%2 == 1 {
+			w = 3
+		}
+		sum += w * int(digits[i]-'0')
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
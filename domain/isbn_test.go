@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		name  string
+		isbn  string
+		want  string
+		valid bool
+	}{
+		{name: "ISBN-13", isbn: "9780134190440", want: "9780134190440", valid: true},
+		{name: "ISBN-13 with hyphens", isbn: "978-0-13-419044-0", want: "9780134190440", valid: true},
+		{name: "ISBN-13 with 979 prefix", isbn: "979-10-90636-07-1", want: "9791090636071", valid: true},
+		{name: "ISBN-10 converted", isbn: "0-13-419044-0", want: "9780134190440", valid: true},
+		{name: "ISBN-10 with X check digit", isbn: "0-8044-2957-X", want: "9780804429573", valid: true},
+		{name: "ISBN-10 with spaces and lower x", isbn: "0 8044 2957 x", want: "9780804429573", valid: true},
+		{name: "ISBN-13 bad checksum", isbn: "9780134190441"},
+		{name: "ISBN-10 bad checksum", isbn: "0134190441"},
+		{name: "ISBN-13 unknown prefix", isbn: "9770134190440"},
+		{name: "X inside an ISBN-10", isbn: "01341X0440"},
+		{name: "Letters", isbn: "978013419044a"},
+		{name: "Too short", isbn: "978013419"},
+		{name: "Empty", isbn: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NormalizeISBN(tt.isbn)
+
+			assert.Equal(t, tt.valid, ok)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.valid, ValidISBN(tt.isbn))
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "context"
+
+// How a Principal was authenticated
+const (
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "api_key"
+)
+
+// Principal is the authenticated caller of a request, as verified
+// by the delivery layer
+type Principal struct {
+	Subject string                 // who the caller is, eg. the sub claim
+	Method  string                 // how the caller was authenticated, eg. AuthMethodJWT
+	Roles   []string               // roles granted to the caller
+	Scopes  []string               // scopes granted to the caller
+	Claims  map[string]interface{} // every verified claim, when authenticated by a token
+}
+
+type principalKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx carrying p
+func ContextWithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal of the request, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
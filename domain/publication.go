@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// MinPublicationYear is the earliest year a book may be dated
+const MinPublicationYear = 1450
+
+// DefaultPublicationGracePeriod is how far in the future a book may be
+// dated by default, so forthcoming books can be catalogued
+const DefaultPublicationGracePeriod = 2 * 365 * 24 * time.Hour
+
+// publicationLayouts are the layouts of a PublicationDate, from the least
+// to the most precise
+var publicationLayouts = []string{"2006", "2006-01", "2006-01-02"}
+
+// PublicationDate is the date a book was published, as precise as it is
+// known: a year (1994), a month (1994-05) or a day (1994-05-17). It is
+// serialized as that string, as the PublicationYear string used to be
+type PublicationDate string
+
+// Parse returns the first day the date denotes, and the layout it is in
+func (d PublicationDate) Parse() (time.Time, string, error) {
+	for _, layout := range publicationLayouts {
+		if len(d) != len(layout) {
+			continue
+		}
+		if t, err := time.Parse(layout, string(d)); err == nil {
+			return t, layout, nil
+		}
+	}
+
+	return time.Time{}, "", fmt.Errorf("publication date %q is not in the form YYYY, YYYY-MM or YYYY-MM-DD", string(d))
+}
+
+// Valid reports whether d is well-formed and not earlier than
+// MinPublicationYear
+func (d PublicationDate) Valid() bool {
+	start, _, err := d.Parse()
+	return err == nil && start.Year() >= MinPublicationYear
+}
+
+// Start returns the first day d denotes, the zero time when d is invalid
+func (d PublicationDate) Start() time.Time {
+	start, _, _ := d.Parse()
+	return start
+}
+
+// End returns the day after the last day d denotes, the zero time when
+// d is invalid
+func (d PublicationDate) End() time.Time {
+	start, layout, err := d.Parse()
+	if err != nil {
+		return time.Time{}
+	}
+
+	switch layout {
+	case "2006":
+		return start.AddDate(1, 0, 0)
+	case "2006-01":
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// Year returns the year of d, 0 when d is invalid
+func (d PublicationDate) Year() int {
+	return d.Start().Year()
+}
+
+// Compare orders dates chronologically by their first day, a less
+// precise date before a more precise one on the same day. Invalid dates
+// come first
+func (d PublicationDate) Compare(o PublicationDate) int {
+	if c := d.Start().Compare(o.Start()); c != 0 {
+		return c
+	}
+	switch {
+	case len(d) < len(o):
+		return -1
+	case len(d) > len(o):
+		return 1
+	}
+	return 0
+}
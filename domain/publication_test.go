@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicationDate(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		date  PublicationDate
+		valid bool
+		start time.Time
+		end   time.Time
+	}{
+		{name: "Year", date: "1994", valid: true, start: date(1994, 1, 1), end: date(1995, 1, 1)},
+		{name: "Month", date: "1994-05", valid: true, start: date(1994, 5, 1), end: date(1994, 6, 1)},
+		{name: "Day", date: "1994-05-17", valid: true, start: date(1994, 5, 17), end: date(1994, 5, 18)},
+		{name: "Leap day", date: "2024-02-29", valid: true, start: date(2024, 2, 29), end: date(2024, 3, 1)},
+		{name: "Earliest year", date: "1450", valid: true, start: date(1450, 1, 1), end: date(1451, 1, 1)},
+		{name: "Too early", date: "1449", start: date(1449, 1, 1), end: date(1450, 1, 1)},
+		{name: "Not a date", date: "abc"},
+		{name: "Empty", date: ""},
+		{name: "Unpadded month", date: "1994-5"},
+		{name: "Month out of range", date: "1994-13"},
+		{name: "Not a leap day", date: "2023-02-29"},
+		{name: "Five digit year", date: "19940"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.valid, tt.date.Valid())
+			assert.Equal(t, tt.start, tt.date.Start())
+			assert.Equal(t, tt.end, tt.date.End())
+		})
+	}
+
+	t.Run("Compare", func(t *testing.T) {
+		// chronological, not lexical, and less precise first on the same day
+		dates := []PublicationDate{"abc", "1994", "1994-01", "1994-01-01", "1994-01-02", "1994-02", "2001"}
+		for i := range dates {
+			for j := range dates {
+				want := 0
+				if i < j {
+					want = -1
+				} else if i > j {
+					want = 1
+				}
+				assert.Equal(t, want, dates[i].Compare(dates[j]), "%s and %s", dates[i], dates[j])
+			}
+		}
+	})
+}
+
+func TestBookFilter_Published(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter BookFilter
+		date   PublicationDate
+		want   bool
+	}{
+		{name: "After a year", filter: BookFilter{PublishedAfter: "1999"}, date: "2000", want: true},
+		{name: "Not after the same year", filter: BookFilter{PublishedAfter: "1999"}, date: "1999-12-31"},
+		{name: "Year spanning the bound", filter: BookFilter{PublishedAfter: "1999-06"}, date: "1999"},
+		{name: "After a day", filter: BookFilter{PublishedAfter: "1999-06-15"}, date: "1999-06-16", want: true},
+		{name: "Before a year", filter: BookFilter{PublishedBefore: "2000"}, date: "1999-12", want: true},
+		{name: "Not before the same year", filter: BookFilter{PublishedBefore: "2000"}, date: "2000-01-01"},
+		{name: "Between", filter: BookFilter{PublishedAfter: "1989", PublishedBefore: "2000"}, date: "1994", want: true},
+		{name: "Outside", filter: BookFilter{PublishedAfter: "1989", PublishedBefore: "2000"}, date: "2001"},
+		{name: "Invalid date never matches", filter: BookFilter{PublishedBefore: "2000"}, date: "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Match(&Book{PublicationDate: tt.date}))
+		})
+	}
+}
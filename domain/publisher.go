@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Publisher publishes books of the catalog, an imprint is a publisher
+// with the publisher it belongs to as its parent
+type Publisher struct {
+	ID       uuid.UUID  `json:"id"`
+	Name     string     `binding:"required,max=255" json:"name"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty"` // set for imprints
+}
+
+// PublisherFilter selects publishers, zero fields match every publisher
+type PublisherFilter struct {
+	Name     string    // part of the name, case-insensitive
+	ParentID uuid.UUID // the imprints of a publisher
+}
+
+// Match reports whether p is selected by f
+func (f PublisherFilter) Match(p *Publisher) bool {
+	switch {
+	case f.Name != "" && !containsFold(p.Name, f.Name):
+		return false
+	case f.ParentID != uuid.Nil && (p.ParentID == nil || *p.ParentID != f.ParentID):
+		return false
+	}
+	return true
+}
+
+type PublisherUseCase interface {
+	FetchPublishers(ctx context.Context, filter PublisherFilter) (*[]Publisher, error)
+	GetPublisherByID(ctx context.Context, id string) (*Publisher, error)
+	// CreatePublisher and UpdatePublisher reject a parent which is not in
+	// the catalog or would make the publisher an imprint of itself
+	CreatePublisher(ctx context.Context, publisher *Publisher) error
+	UpdatePublisher(ctx context.Context, id string, publisher *Publisher) error
+	// DeletePublisher fails with a conflict while the publisher has
+	// imprints or books, unless cascade, which deletes its imprints and
+	// moves their books and its own to the trash, detached from the
+	// publisher. It fails with a conflict before any change while a
+	// trashed book belongs to one of them, or a book to trash has active
+	// copies
+	DeletePublisher(ctx context.Context, id string, cascade bool) error
+	// FetchPublisherBooks lists the books of the publisher and of its
+	// imprints
+	FetchPublisherBooks(ctx context.Context, id string) (*[]Book, error)
+}
+
+type PublisherRepository interface {
+	// FetchPublishers lists the matching publishers by name, empty when
+	// none match
+	FetchPublishers(ctx context.Context, filter PublisherFilter) (*[]Publisher, error)
+	GetPublisherByID(ctx context.Context, id string) (*Publisher, error)
+	// CreatePublisher and UpdatePublisher reject a publisher named as
+	// another one, ignoring case, with a conflict
+	CreatePublisher(ctx context.Context, publisher *Publisher) error
+	UpdatePublisher(ctx context.Context, id string, publisher *Publisher) error
+	DeletePublisher(ctx context.Context, id string) error
+}
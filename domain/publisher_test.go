@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublisherFilter(t *testing.T) {
+	parent := uuid.New()
+	imprint := &Publisher{Name: "Puffin", ParentID: &parent}
+
+	assert.True(t, PublisherFilter{}.Match(imprint))
+	assert.True(t, PublisherFilter{Name: "puff", ParentID: parent}.Match(imprint))
+	assert.False(t, PublisherFilter{ParentID: uuid.New()}.Match(imprint))
+	assert.False(t, PublisherFilter{ParentID: parent}.Match(&Publisher{Name: "Penguin Books"}))
+}
+
+func TestBookFilter_PublisherID(t *testing.T) {
+	publisher := uuid.New()
+
+	assert.True(t, BookFilter{PublisherID: publisher}.Match(&Book{PublisherID: &publisher}))
+	assert.False(t, BookFilter{PublisherID: uuid.New()}.Match(&Book{PublisherID: &publisher}))
+	assert.False(t, BookFilter{PublisherID: publisher}.Match(&Book{}))
+}
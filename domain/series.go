@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// Series is a sequence of books meant to be read in order
+type Series struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `binding:"required,max=255" json:"name"`
+	Description string    `binding:"omitempty,max=10000" json:"description,omitempty"`
+}
+
+// SeriesEntry places a book in a series, no two books of the catalog
+// share a position in the same series
+type SeriesEntry struct {
+	SeriesID uuid.UUID `binding:"required" json:"series_id"`
+	Position float64   `binding:"min=0" json:"position"` // reading order, eg. 2.5 for a novella between 2 and 3
+	// Previous and Next are the neighbours of the book in the series,
+	// set when a single book is read or written and never stored
+	Previous *SeriesNeighbour `json:"previous,omitempty"`
+	Next     *SeriesNeighbour `json:"next,omitempty"`
+}
+
+// SeriesNeighbour is the book read before or after another one
+type SeriesNeighbour struct {
+	ID       uuid.UUID `json:"id"`
+	Title    string    `json:"title"`
+	Position float64   `json:"position"`
+}
+
+// SeriesEntry returns the entry of b in the series, if b belongs to it
+func (b *Book) SeriesEntry(seriesID uuid.UUID) (SeriesEntry, bool) {
+	for _, e := range b.Series {
+		if e.SeriesID == seriesID {
+			return e, true
+		}
+	}
+	return SeriesEntry{}, false
+}
+
+// InSeries reports whether b belongs to the series
+func (b *Book) InSeries(seriesID uuid.UUID) bool {
+	_, ok := b.SeriesEntry(seriesID)
+	return ok
+}
+
+// SortBySeries sorts books, all of the series, in reading order
+func SortBySeries(books []Book, seriesID uuid.UUID) {
+	sort.SliceStable(books, func(i, j int) bool {
+		a, _ := books[i].SeriesEntry(seriesID)
+		b, _ := books[j].SeriesEntry(seriesID)
+		return a.Position < b.Position
+	})
+}
+
+// SeriesFilter selects series, the zero value matches every series
+type SeriesFilter struct {
+	Name string // part of the name, case-insensitive
+}
+
+// Match reports whether s is selected by f
+func (f SeriesFilter) Match(s *Series) bool {
+	return f.Name == "" || containsFold(s.Name, f.Name)
+}
+
+type SeriesUseCase interface {
+	FetchSeries(ctx context.Context, filter SeriesFilter) (*[]Series, error)
+	GetSeriesByID(ctx context.Context, id string) (*Series, error)
+	CreateSeries(ctx context.Context, series *Series) error
+	UpdateSeries(ctx context.Context, id string, series *Series) error
+	// DeleteSeries fails with a conflict while books, trashed or not,
+	// belong to the series
+	DeleteSeries(ctx context.Context, id string) error
+	// FetchSeriesBooks lists the books of the series in reading order
+	FetchSeriesBooks(ctx context.Context, id string) (*[]Book, error)
+}
+
+type SeriesRepository interface {
+	// FetchSeries lists the matching series by name, empty when none
+	// match
+	FetchSeries(ctx context.Context, filter SeriesFilter) (*[]Series, error)
+	GetSeriesByID(ctx context.Context, id string) (*Series, error)
+	// CreateSeries and UpdateSeries reject a series named as another
+	// one, ignoring case, with a conflict
+	CreateSeries(ctx context.Context, series *Series) error
+	UpdateSeries(ctx context.Context, id string, series *Series) error
+	DeleteSeries(ctx context.Context, id string) error
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortBySeries(t *testing.T) {
+	series := uuid.New()
+	entry := func(position float64) []SeriesEntry {
+		return []SeriesEntry{{SeriesID: uuid.New(), Position: 0}, {SeriesID: series, Position: position}}
+	}
+	books := []Book{{Title: "3", Series: entry(3)}, {Title: "2.5", Series: entry(2.5)}, {Title: "0", Series: entry(0)}, {Title: "2", Series: entry(2)}}
+
+	SortBySeries(books, series)
+
+	var titles []string
+	for _, b := range books {
+		titles = append(titles, b.Title)
+	}
+	assert.Equal(t, []string{"0", "2", "2.5", "3"}, titles)
+}
+
+func TestBookFilter_SeriesID(t *testing.T) {
+	series := uuid.New()
+	book := &Book{Series: []SeriesEntry{{SeriesID: series, Position: 1}}}
+
+	assert.True(t, BookFilter{SeriesID: series}.Match(book))
+	assert.False(t, BookFilter{SeriesID: uuid.New()}.Match(book))
+	assert.False(t, BookFilter{SeriesID: series}.Match(&Book{}))
+}
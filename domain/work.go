@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Work is the creation editions and translations are books of, linked
+// to it through the WorkID of each book
+type Work struct {
+	ID    uuid.UUID `json:"id"`
+	Title string    `binding:"required,max=255" json:"title"`
+}
+
+// WorkFilter selects works, the zero value matches every work
+type WorkFilter struct {
+	Title string // part of the title, case-insensitive
+}
+
+// Match reports whether w is selected by f
+func (f WorkFilter) Match(w *Work) bool {
+	return f.Title == "" || containsFold(w.Title, f.Title)
+}
+
+// WorkMerge lists the works merged into another one
+type WorkMerge struct {
+	WorkIDs []uuid.UUID `binding:"required,min=1,max=50" json:"work_ids"`
+}
+
+// WorkSplit lists the editions moved out of a work into a new one,
+// titled after the first of them unless Title is set
+type WorkSplit struct {
+	BookIDs []uuid.UUID `binding:"required,min=1,max=500" json:"book_ids"`
+	Title   string      `binding:"omitempty,max=255" json:"title,omitempty"`
+}
+
+// WorkSuggestion is a group of books which look like editions of the
+// same work without all being grouped in one yet
+type WorkSuggestion struct {
+	Score   float64     `json:"score"` // title similarity of the least similar pair linking the group
+	Books   []Book      `json:"books"`
+	WorkIDs []uuid.UUID `json:"work_ids"` // the works some of the books belong to already
+}
+
+// DefaultWorkSuggestionScore is the title similarity from which books by
+// the same author are suggested as editions of a work
+const DefaultWorkSuggestionScore = 0.6
+
+// SameEdition reports whether b and o are the same edition of a work:
+// the same title, author and publication date, edition, format and
+// language. Books with different ISBNs are never the same edition
+func (b *Book) SameEdition(o *Book) bool {
+	if b.ISBN != "" && o.ISBN != "" && b.ISBN != o.ISBN {
+		return false
+	}
+	return b.Title == o.Title && b.Author == o.Author && b.PublicationDate == o.PublicationDate &&
+		strings.EqualFold(b.Edition, o.Edition) && b.Format == o.Format && strings.EqualFold(b.Language, o.Language)
+}
+
+// SameAuthor reports whether b and o have an author in common, by
+// AuthorKey of their Author or by a contributor credited as author of
+// both
+func (b *Book) SameAuthor(o *Book) bool {
+	if AuthorKey(b.Author) != "" && AuthorKey(b.Author) == AuthorKey(o.Author) {
+		return true
+	}
+	for _, c := range b.Contributors {
+		if c.Role != ContributorAuthor {
+			continue
+		}
+		for _, oc := range o.Contributors {
+			if oc == c {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TitleSimilarity scores from 0 to 1 how alike two titles are, as the
+// share of their words in common. Case, punctuation, leading articles,
+// subtitles and parenthesized notes are ignored, so "Dune (40th
+// Anniversary Edition)" and "dune" score 1
+func TitleSimilarity(a string, b string) float64 {
+	wa, wb := titleWords(a), titleWords(b)
+	if len(wa) == 0 || len(wb) == 0 {
+		return 0
+	}
+
+	common := 0
+	for w := range wa {
+		if wb[w] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(wa)+len(wb)-common)
+}
+
+// titleWords returns the set of the words of the main title, compared
+// as author names are by AuthorKey
+func titleWords(title string) map[string]bool {
+	title = norm.NFKC.String(title)
+	if i := strings.IndexAny(title, ":(["); i > 0 {
+		title = title[:i]
+	}
+
+	words := map[string]bool{}
+	for i, w := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-'
+	}) {
+		w = strings.Map(func(r rune) rune {
+			if keyRune(r) {
+				return r
+			}
+			return -1
+		}, w)
+		if w == "" || i == 0 && (w == "the" || w == "a" || w == "an") {
+			continue
+		}
+		words[w] = true
+	}
+	return words
+}
+
+type WorkUseCase interface {
+	FetchWorks(ctx context.Context, filter WorkFilter) (*[]Work, error)
+	GetWorkByID(ctx context.Context, id string) (*Work, error)
+	CreateWork(ctx context.Context, work *Work) error
+	UpdateWork(ctx context.Context, id string, work *Work) error
+	// DeleteWork fails with a conflict while books, trashed or not, are
+	// editions of the work
+	DeleteWork(ctx context.Context, id string) error
+	// FetchEditions lists the editions of the work by publication date
+	FetchEditions(ctx context.Context, id string) (*[]Book, error)
+	// SuggestWorks groups the books by the same author whose titles score
+	// at least minScore with TitleSimilarity, leaving out the groups
+	// whose books are editions of one work already
+	SuggestWorks(ctx context.Context, minScore float64) (*[]WorkSuggestion, error)
+	// MergeWorks moves the editions of the merged works to the work id
+	// and deletes them, each once however often it is listed. Every work
+	// is checked before the first change
+	MergeWorks(ctx context.Context, id string, merge *WorkMerge) (*Work, error)
+	// SplitWork moves editions of the work id to a new work
+	SplitWork(ctx context.Context, id string, split *WorkSplit) (*Work, error)
+}
+
+type WorkRepository interface {
+	// FetchWorks lists the matching works by title, empty when none
+	// match
+	FetchWorks(ctx context.Context, filter WorkFilter) (*[]Work, error)
+	GetWorkByID(ctx context.Context, id string) (*Work, error)
+	CreateWork(ctx context.Context, work *Work) error
+	UpdateWork(ctx context.Context, id string, work *Work) error
+	DeleteWork(ctx context.Context, id string) error
+}
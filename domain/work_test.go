@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"Dune", "dune", 1},
+		{"Dune (40th Anniversary Edition)", "Dune", 1},
+		{"The Hobbit", "Hobbit: or There and Back Again", 1},
+		{"Pride and Prejudice", "Pride & Prejudice", 2.0 / 3.0},
+		{"Dune Messiah", "Children of Dune", 0.25},
+		{"Dune", "Emma", 0},
+		{"", "Dune", 0},
+		{"Café", "Cafe\u0301", 1},
+		// Thai titles differing only by a tone mark
+		{"ป่า", "ป้า", 0},
+		{"ข้าว ป่า", "ข้าว ป้า", 1.0 / 3.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" / "+tt.b, func(t *testing.T) {
+			assert.InDelta(t, tt.want, TitleSimilarity(tt.a, tt.b), 1e-9)
+			assert.InDelta(t, tt.want, TitleSimilarity(tt.b, tt.a), 1e-9)
+		})
+	}
+}
+
+func TestBook_SameEdition(t *testing.T) {
+	book := Book{Title: "Dune", Author: "Frank Herbert", PublicationDate: "1965", Edition: "1st", Format: FormatHardcover, Language: "en"}
+	with := func(change func(b *Book)) *Book {
+		b := book
+		change(&b)
+		return &b
+	}
+
+	assert.True(t, book.SameEdition(with(func(b *Book) {})))
+	assert.True(t, book.SameEdition(with(func(b *Book) { b.Edition, b.Language = "1ST", "EN" })))
+	assert.True(t, book.SameEdition(with(func(b *Book) { b.ISBN = "9780441172719" })))
+	assert.False(t, book.SameEdition(with(func(b *Book) { b.Format = FormatPaperback })))
+	assert.False(t, book.SameEdition(with(func(b *Book) { b.Edition = "2nd" })))
+	assert.False(t, book.SameEdition(with(func(b *Book) { b.Language = "fr" })))
+	assert.False(t, book.SameEdition(with(func(b *Book) { b.PublicationDate = "1966" })))
+
+	isbn := with(func(b *Book) { b.ISBN = "9780441172719" })
+	assert.False(t, isbn.SameEdition(with(func(b *Book) { b.ISBN = "9780340960196" })))
+}
+
+func TestBook_SameAuthor(t *testing.T) {
+	herbert := uuid.New()
+	book := &Book{Author: "Frank Herbert", Contributors: []Contributor{{AuthorID: herbert, Role: ContributorAuthor}}}
+
+	assert.True(t, book.SameAuthor(&Book{Author: "frank  herbert"}))
+	assert.True(t, book.SameAuthor(&Book{Author: "F. Herbert", Contributors: []Contributor{{AuthorID: herbert, Role: ContributorAuthor}}}))
+	assert.False(t, book.SameAuthor(&Book{Author: "F. Herbert", Contributors: []Contributor{{AuthorID: herbert, Role: ContributorTranslator}}}))
+	assert.False(t, book.SameAuthor(&Book{Author: "Jane Austen"}))
+}
+
+func TestBookFilter_WorkID(t *testing.T) {
+	work := uuid.New()
+	book := &Book{WorkID: &work}
+
+	assert.True(t, BookFilter{WorkID: work}.Match(book))
+	assert.False(t, BookFilter{WorkID: uuid.New()}.Match(book))
+	assert.False(t, BookFilter{WorkID: work}.Match(&Book{}))
+}
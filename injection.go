@@ -1,40 +1,371 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/krittawatcode/books/delivery/auth"
 	"github.com/krittawatcode/books/delivery/handler"
+	"github.com/krittawatcode/books/delivery/middleware"
+	"github.com/krittawatcode/books/delivery/response"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/applog"
 	"github.com/krittawatcode/books/repository"
 	"github.com/krittawatcode/books/usecase"
+	_ "github.com/lib/pq" // AUDIT_SQL_DRIVER=postgres
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	_ "modernc.org/sqlite" // AUDIT_SQL_DRIVER=sqlite
 )
 
-func inject() (*gin.Engine, error) {
-	bookRepo := repository.NewInMemoryBookRepository()
-	bookUsecase := usecase.NewBookUseCase(bookRepo)
+// inject wires the application, the returned shutdown flushes what is
+// still buffered, such as spans, and must be called once the server stopped
+func inject() (*gin.Engine, func(context.Context) error, error) {
+	// structured logs, every record logged with a request context
+	// carries its request ID
+	logger, err := applog.New(os.Stdout, os.Getenv("LOG_LEVEL"), os.Getenv("LOG_FORMAT"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not configure logging: %w", err)
+	}
+	slog.SetDefault(logger)
+
+	// Prometheus metrics of the routes and the repository, served at /metrics
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	httpMetrics := middleware.NewHTTPMetrics(registry)
+
+	// traces of the requests, continued from the W3C traceparent of callers
+	tp, err := tracerProvider()
+	if err != nil {
+		return nil, nil, err
+	}
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	bookRepo := repository.NewMetricsBookRepository(repository.NewInMemoryBookRepository(), registry)
+	bookRepo = repository.NewTracingBookRepository(bookRepo, tp)
+
+	auditRepo, err := auditRepository()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// cap request bodies, routes such as imports may be given more room
+	bodyLimit := middleware.DefaultBodyLimit
+	if s := os.Getenv("BODY_LIMIT"); s != "" {
+		l, err := strconv.ParseInt(s, 0, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not parse BODY_LIMIT as int: %w", err)
+		}
+		bodyLimit = l
+	}
+	bodyLimits, err := middleware.ParseBodyLimits(os.Getenv("BODY_LIMIT_ROUTES"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not parse BODY_LIMIT_ROUTES: %w", err)
+	}
 
 	// initialize gin.Engine
-	router := gin.Default()
+	router := gin.New()
+	router.Use(middleware.RequestID(), middleware.Tracing(tp, otel.GetTextMapPropagator()), middleware.AccessLog(logger), httpMetrics.Handler(), middleware.Recovery(), middleware.BodyLimit(bodyLimit, bodyLimits))
+
+	errorFormat, err := response.ParseFormat(os.Getenv("ERROR_FORMAT"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not parse ERROR_FORMAT: %w", err)
+	}
+	response.SetDefaultFormat(errorFormat)
+	apperror.CaptureStack = os.Getenv("ERROR_STACKTRACE") == "true"
 
 	booksPath := os.Getenv("BOOKS_PATH")
 	handlerTimeout := os.Getenv("HANDLER_TIMEOUT")
 	ht, err := strconv.ParseInt(handlerTimeout, 0, 64)
 	if err != nil {
-		return nil, fmt.Errorf("could not parse HANDLER_TIMEOUT as int: %w", err)
+		return nil, nil, fmt.Errorf("could not parse HANDLER_TIMEOUT as int: %w", err)
 	}
 	timeout := time.Duration(time.Duration(ht) * time.Second)
 
+	// validate against the OpenAPI document, responses too in development
+	doc := handler.NewOpenAPIDocument(booksPath)
+	validator := middleware.OpenAPIValidator(doc, middleware.OpenAPIOptions{
+		ValidateResponses: os.Getenv("APP_ENV") == "development",
+	})
+
+	opts := []handler.Option{handler.WithMiddleware(validator)}
+	ucOpts := []usecase.Option{usecase.WithTracerProvider(tp)}
+
+	// writes require a bearer token or an API key, what each caller may
+	// do is decided by the use cases
+	jwtAuth, err := jwtAuthenticator()
+	if err != nil {
+		return nil, nil, err
+	}
+	// an open catalog has to be asked for, a missing key must not start
+	// one by mistake
+	authDisabled := os.Getenv("AUTH_DISABLED") == "true"
+	switch {
+	case jwtAuth == nil && !authDisabled:
+		return nil, nil, errors.New("no JWT key configured: set JWT_HMAC_SECRET_FILE, JWT_RSA_PUBLIC_KEY_FILE or JWT_JWKS_URL, or AUTH_DISABLED=true to run without authentication")
+	case jwtAuth != nil && authDisabled:
+		return nil, nil, errors.New("AUTH_DISABLED=true is set along with a JWT key")
+	case authDisabled:
+		slog.Warn("authentication is disabled, anyone can change the catalog; unset AUTH_DISABLED and configure a JWT key outside development")
+	}
+	if jwtAuth != nil {
+		policy := usecase.DefaultPolicy()
+		if path := os.Getenv("RBAC_POLICY_FILE"); path != "" {
+			if policy, err = usecase.LoadPolicy(path); err != nil {
+				return nil, nil, fmt.Errorf("could not load RBAC_POLICY_FILE: %w", err)
+			}
+		}
+		ucOpts = append(ucOpts, usecase.WithAuthorizer(usecase.NewRBAC(policy)))
+
+		apiKeyUsecase := usecase.NewAPIKeyUseCase(repository.NewInMemoryAPIKeyRepository(), ucOpts...)
+		apiKeyAuth := auth.NewAPIKeyAuthenticator(apiKeyUsecase)
+
+		opts = append(opts,
+			handler.WithReadMiddleware(middleware.OptionalAuth(jwtAuth, apiKeyAuth)),
+			handler.WithWriteMiddleware(middleware.RequireAuth(jwtAuth, apiKeyAuth)),
+		)
+		handler.NewAPIKeyHandler(router, apiKeyUsecase, timeout, middleware.RequireAuth(jwtAuth), validator)
+		handler.NewAuditHandler(router, usecase.NewAuditUseCase(auditRepo, ucOpts...), timeout, middleware.RequireAuth(jwtAuth), validator)
+	}
+
+	// limit each client separately for reads and writes, after auth so
+	// authenticated clients are limited by key or user instead of IP
+	rateLimits := middleware.NewMemoryRateLimitStore()
+	if s := os.Getenv("RATE_LIMIT_READ"); s != "" {
+		rate, err := middleware.ParseRate(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not parse RATE_LIMIT_READ: %w", err)
+		}
+		opts = append(opts, handler.WithReadMiddleware(middleware.RateLimit(rateLimits, "read", rate)))
+	}
+	if s := os.Getenv("RATE_LIMIT_WRITE"); s != "" {
+		rate, err := middleware.ParseRate(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not parse RATE_LIMIT_WRITE: %w", err)
+		}
+		opts = append(opts, handler.WithWriteMiddleware(middleware.RateLimit(rateLimits, "write", rate)))
+	}
+
 	// inject dependencies
-	handler.NewBookHandler(router, bookUsecase, booksPath, timeout)
+	// audit every change, whichever route it comes through
+	authorRepo := repository.NewInMemoryAuthorRepository()
+	publisherRepo := repository.NewInMemoryPublisherRepository()
+	seriesRepo := repository.NewInMemorySeriesRepository()
+	workRepo := repository.NewInMemoryWorkRepository()
+	copyRepo := repository.NewInMemoryCopyRepository()
+	bookOpts := append(ucOpts[:len(ucOpts):len(ucOpts)],
+		usecase.WithAuthorRepository(authorRepo),
+		usecase.WithPublisherRepository(publisherRepo),
+		usecase.WithSeriesRepository(seriesRepo),
+		usecase.WithWorkRepository(workRepo),
+		usecase.WithCopyRepository(copyRepo),
+	)
+	if s := os.Getenv("PUBLICATION_GRACE_PERIOD"); s != "" {
+		grace, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not parse PUBLICATION_GRACE_PERIOD: %w", err)
+		}
+		bookOpts = append(bookOpts, usecase.WithPublicationGracePeriod(grace))
+	}
+	bookUsecase := usecase.NewAuditedBookUseCase(usecase.NewBookUseCase(bookRepo, bookOpts...), bookRepo, auditRepo)
+
+	// empty the trash of the books deleted longer than the retention ago
+	purger, err := trashPurger(bookRepo, copyRepo)
+	if err != nil {
+		return nil, nil, err
+	}
+	purgeCtx, stopPurger := context.WithCancel(context.Background())
+	go purger.Run(purgeCtx)
+	handler.NewBookHandler(router, bookUsecase, booksPath, timeout, opts...)
+	handler.NewAuthorHandler(router, usecase.NewAuthorUseCase(authorRepo, bookUsecase, ucOpts...), timeout, opts...)
+	handler.NewPublisherHandler(router, usecase.NewPublisherUseCase(publisherRepo, bookUsecase, ucOpts...), timeout, opts...)
+	handler.NewSeriesHandler(router, usecase.NewSeriesUseCase(seriesRepo, bookUsecase, ucOpts...), timeout, opts...)
+	handler.NewWorkHandler(router, usecase.NewWorkUseCase(workRepo, bookUsecase, ucOpts...), timeout, opts...)
+	handler.NewCopyHandler(router, usecase.NewCopyUseCase(copyRepo, bookUsecase, ucOpts...), booksPath, timeout, opts...)
+	handler.NewDocsHandler(router, doc)
+
+	router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(registry, promhttp.HandlerOpts{})))
 
 	// setup health check
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "running"})
 	})
 
-	return router, nil
+	shutdown := func(ctx context.Context) error {
+		stopPurger()
+		return tp.Shutdown(ctx)
+	}
+
+	return router, shutdown, nil
+}
+
+// trashPurger purges the books trashed longer than TRASH_RETENTION ago
+// every TRASH_PURGE_INTERVAL, both Go durations such as 720h
+func trashPurger(bookRepo domain.BookRepository, copyRepo domain.CopyRepository) (*usecase.TrashPurger, error) {
+	retention := usecase.DefaultTrashRetention
+	if s := os.Getenv("TRASH_RETENTION"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse TRASH_RETENTION: %w", err)
+		}
+		retention = d
+	}
+
+	interval := time.Hour
+	if s := os.Getenv("TRASH_PURGE_INTERVAL"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("could not parse TRASH_PURGE_INTERVAL as a positive duration: %q", s)
+		}
+		interval = d
+	}
+
+	return usecase.NewTrashPurger(bookRepo, copyRepo, retention, interval), nil
+}
+
+// auditRepository keeps the audit log as chosen by AUDIT_STORE: memory,
+// file at the AUDIT_FILE path, or sql through the AUDIT_SQL_DRIVER
+// driver, postgres or sqlite, at AUDIT_SQL_DSN
+func auditRepository() (domain.AuditRepository, error) {
+	switch store := os.Getenv("AUDIT_STORE"); store {
+	case "", "memory":
+		return repository.NewInMemoryAuditRepository(), nil
+	case "file":
+		path := os.Getenv("AUDIT_FILE")
+		if path == "" {
+			return nil, fmt.Errorf("AUDIT_FILE is required with AUDIT_STORE=file")
+		}
+		return repository.NewFileAuditRepository(path)
+	case "sql":
+		driver := os.Getenv("AUDIT_SQL_DRIVER")
+		if driver != "postgres" && driver != "sqlite" {
+			return nil, fmt.Errorf("AUDIT_SQL_DRIVER must be postgres or sqlite with AUDIT_STORE=sql, not %q", driver)
+		}
+		db, err := sql.Open(driver, os.Getenv("AUDIT_SQL_DSN"))
+		if err != nil {
+			return nil, fmt.Errorf("could not open the audit database: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, err := db.ExecContext(ctx, repository.AuditSchema); err != nil {
+			return nil, fmt.Errorf("could not create the audit table: %w", err)
+		}
+
+		placeholder := repository.QuestionPlaceholder
+		if driver == "postgres" {
+			placeholder = repository.DollarPlaceholder
+		}
+		return repository.NewSQLAuditRepository(db, placeholder), nil
+	default:
+		return nil, fmt.Errorf("unknown AUDIT_STORE %q", store)
+	}
+}
+
+// tracerProvider exports spans as chosen by OTEL_TRACES_EXPORTER: none,
+// otlp to the OTEL_EXPORTER_OTLP_* endpoint, or console to stdout or the
+// TRACES_FILE path
+func tracerProvider() (*sdktrace.TracerProvider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName("books")))
+	if err != nil {
+		return nil, fmt.Errorf("could not describe the trace resource: %w", err)
+	}
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES still win
+	if res, err = resource.Merge(res, resource.Environment()); err != nil {
+		return nil, fmt.Errorf("could not describe the trace resource: %w", err)
+	}
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+
+	switch exporter := os.Getenv("OTEL_TRACES_EXPORTER"); exporter {
+	case "", "none":
+	case "otlp":
+		exp, err := otlptracehttp.New(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("could not create the OTLP trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	case "console":
+		w := io.Writer(os.Stdout)
+		if path := os.Getenv("TRACES_FILE"); path != "" {
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if err != nil {
+				return nil, fmt.Errorf("could not open TRACES_FILE: %w", err)
+			}
+			w = f
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(w))
+		if err != nil {
+			return nil, fmt.Errorf("could not create the console trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	default:
+		return nil, fmt.Errorf("unknown OTEL_TRACES_EXPORTER %q", exporter)
+	}
+
+	return sdktrace.NewTracerProvider(opts...), nil
+}
+
+// jwtAuthenticator verifies bearer tokens with the keys configured by
+// JWT_HMAC_SECRET_FILE, JWT_RSA_PUBLIC_KEY_FILE and JWT_JWKS_URL,
+// it returns nil when none is set
+func jwtAuthenticator() (*auth.JWTAuthenticator, error) {
+	var keys auth.Keys
+
+	if path := os.Getenv("JWT_HMAC_SECRET_FILE"); path != "" {
+		key, err := auth.LoadHMACKeyFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not load JWT_HMAC_SECRET_FILE: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if path := os.Getenv("JWT_RSA_PUBLIC_KEY_FILE"); path != "" {
+		key, err := auth.LoadRSAPublicKeyFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not load JWT_RSA_PUBLIC_KEY_FILE: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if url := os.Getenv("JWT_JWKS_URL"); url != "" {
+		keys = append(keys, auth.NewJWKS(url, &http.Client{Timeout: 5 * time.Second}, time.Hour))
+	}
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	var leeway time.Duration
+	if s := os.Getenv("JWT_LEEWAY"); s != "" {
+		l, err := strconv.ParseInt(s, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse JWT_LEEWAY as int: %w", err)
+		}
+		leeway = time.Duration(l) * time.Second
+	}
+
+	return auth.NewJWTAuthenticator(keys, auth.JWTOptions{
+		Issuer:   os.Getenv("JWT_ISSUER"),
+		Audience: os.Getenv("JWT_AUDIENCE"),
+		Leeway:   leeway,
+	}), nil
 }
@@ -10,11 +10,10 @@ import (
 	"time"
 )
 
-// @title Vote Items API
 func main() {
 	log.Println("Starting server...")
 
-	router, err := inject()
+	router, shutdown, err := inject()
 	if err != nil {
 		log.Fatalf("Unable to inject data sources: %v\n", err)
 	}
@@ -51,4 +50,9 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v\n", err)
 	}
+
+	// flush the spans of the last requests
+	if err := shutdown(ctx); err != nil {
+		log.Printf("Failed to flush telemetry: %v\n", err)
+	}
 }
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+type InMemoryAPIKeyRepository struct {
+	keys []domain.APIKey
+	mu   sync.Mutex
+}
+
+func NewInMemoryAPIKeyRepository() domain.APIKeyRepository {
+	return &InMemoryAPIKeyRepository{
+		keys: []domain.APIKey{},
+	}
+}
+
+func (r *InMemoryAPIKeyRepository) FetchAPIKeys(ctx context.Context) (*[]domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make([]domain.APIKey, len(r.keys))
+	copy(keys, r.keys)
+
+	return &keys, nil
+}
+
+func (r *InMemoryAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range r.keys {
+		if key.Hash == hash {
+			return &key, nil
+		}
+	}
+
+	return nil, apperror.NewNotFound("APIKey", "hash", "")
+}
+
+func (r *InMemoryAPIKeyRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key.ID = uuid.New()
+	r.keys = append(r.keys, *key)
+
+	return nil
+}
+
+func (r *InMemoryAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, key := range r.keys {
+		if key.ID.String() == id {
+			if key.RevokedAt == nil {
+				r.keys[i].RevokedAt = &at
+			}
+			return nil
+		}
+	}
+
+	return apperror.NewNotFound("APIKey", "ID", id)
+}
+
+func (r *InMemoryAPIKeyRepository) TouchAPIKey(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, key := range r.keys {
+		if key.ID.String() == id {
+			r.keys[i].LastUsedAt = &at
+			return nil
+		}
+	}
+
+	return apperror.NewNotFound("APIKey", "ID", id)
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryAPIKeyRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create and get by hash", func(t *testing.T) {
+		repo := NewInMemoryAPIKeyRepository()
+		key := &domain.APIKey{Name: "import", Hash: "abc", Scopes: []string{domain.ScopeBooksRead}}
+
+		err := repo.CreateAPIKey(ctx, key)
+		assert.Nil(t, err)
+		assert.NotEqual(t, uuid.Nil, key.ID)
+
+		found, err := repo.GetAPIKeyByHash(ctx, "abc")
+		assert.Nil(t, err)
+		assert.Equal(t, key.ID, found.ID)
+
+		keys, err := repo.FetchAPIKeys(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, *keys, 1)
+	})
+
+	t.Run("Unknown hash", func(t *testing.T) {
+		repo := NewInMemoryAPIKeyRepository()
+
+		_, err := repo.GetAPIKeyByHash(ctx, "abc")
+		assert.Equal(t, apperror.NotFound, err.(*apperror.Error).Type)
+	})
+
+	t.Run("Revoke and touch", func(t *testing.T) {
+		repo := NewInMemoryAPIKeyRepository()
+		key := &domain.APIKey{Name: "import", Hash: "abc"}
+		repo.CreateAPIKey(ctx, key)
+
+		revokedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		assert.Nil(t, repo.RevokeAPIKey(ctx, key.ID.String(), revokedAt))
+		// revoking again keeps the first revocation
+		assert.Nil(t, repo.RevokeAPIKey(ctx, key.ID.String(), revokedAt.Add(time.Hour)))
+		assert.Nil(t, repo.TouchAPIKey(ctx, key.ID.String(), revokedAt))
+
+		found, _ := repo.GetAPIKeyByHash(ctx, "abc")
+		assert.Equal(t, revokedAt, *found.RevokedAt)
+		assert.Equal(t, revokedAt, *found.LastUsedAt)
+	})
+
+	t.Run("Revoke unknown key", func(t *testing.T) {
+		repo := NewInMemoryAPIKeyRepository()
+
+		err := repo.RevokeAPIKey(ctx, uuid.New().String(), time.Now())
+		assert.Equal(t, apperror.NotFound, err.(*apperror.Error).Type)
+	})
+}
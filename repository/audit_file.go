@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+// FileAuditRepository appends the audit entries to a file, one JSON
+// object per line. The file is only ever appended to
+type FileAuditRepository struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileAuditRepository keeps the audit entries in the file at path,
+// which is created when missing
+func NewFileAuditRepository(path string) (domain.AuditRepository, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open audit log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("could not open audit log: %w", err)
+	}
+
+	return &FileAuditRepository{path: path}, nil
+}
+
+func (r *FileAuditRepository) AppendAuditEntry(ctx context.Context, entry *domain.AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return apperror.NewInternal().WithCause(fmt.Errorf("could not encode audit entry: %w", err))
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, err := os.OpenFile(r.path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return apperror.NewInternal().WithCause(fmt.Errorf("could not open audit log: %w", err))
+	}
+	defer f.Close()
+
+	// a single write, so a crash cannot leave half an entry before the next
+	if _, err := f.Write(line); err != nil {
+		return apperror.NewInternal().WithCause(fmt.Errorf("could not append audit entry: %w", err))
+	}
+	if err := f.Sync(); err != nil {
+		return apperror.NewInternal().WithCause(fmt.Errorf("could not sync audit log: %w", err))
+	}
+
+	return nil
+}
+
+func (r *FileAuditRepository) FetchAuditEntries(ctx context.Context, filter domain.AuditFilter) (*[]domain.AuditEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, err := os.Open(r.path)
+	if err != nil {
+		return nil, apperror.NewInternal().WithCause(fmt.Errorf("could not open audit log: %w", err))
+	}
+	defer f.Close()
+
+	entries := []domain.AuditEntry{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var e domain.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, apperror.NewInternal().WithCause(fmt.Errorf("could not decode line %d of audit log: %w", n, err))
+		}
+		if filter.Match(&e) {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, apperror.NewInternal().WithCause(fmt.Errorf("could not read audit log: %w", err))
+	}
+
+	return &entries, nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/krittawatcode/books/domain"
+)
+
+type InMemoryAuditRepository struct {
+	entries []domain.AuditEntry
+	mu      sync.Mutex
+}
+
+func NewInMemoryAuditRepository() domain.AuditRepository {
+	return &InMemoryAuditRepository{
+		entries: []domain.AuditEntry{},
+	}
+}
+
+func (r *InMemoryAuditRepository) AppendAuditEntry(ctx context.Context, entry *domain.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, *entry)
+
+	return nil
+}
+
+func (r *InMemoryAuditRepository) FetchAuditEntries(ctx context.Context, filter domain.AuditFilter) (*[]domain.AuditEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := []domain.AuditEntry{}
+	for i := range r.entries {
+		if filter.Match(&r.entries[i]) {
+			entries = append(entries, r.entries[i])
+		}
+	}
+
+	return &entries, nil
+}
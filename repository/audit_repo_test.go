@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAuditRepository checks the filters of repo, which must be empty
+func testAuditRepository(t *testing.T, repo domain.AuditRepository) {
+	ctx := context.Background()
+	bookID := uuid.New()
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	entries := []domain.AuditEntry{
+		{ID: uuid.New(), Time: day, Action: domain.AuditActionCreate, BookID: bookID, Actor: "alice", AuthMethod: domain.AuthMethodJWT, RequestID: "req-1",
+			Changes: []domain.FieldChange{{Field: "title", Before: nil, After: "Go"}}},
+		{ID: uuid.New(), Time: day.Add(time.Hour), Action: domain.AuditActionUpdate, BookID: bookID, Actor: "bob",
+			Changes: []domain.FieldChange{{Field: "title", Before: "Go", After: "Go 2"}}},
+		{ID: uuid.New(), Time: day.Add(2 * time.Hour), Action: domain.AuditActionCreate, BookID: uuid.New(), Actor: "alice",
+			Changes: []domain.FieldChange{}},
+	}
+	for i := range entries {
+		require.NoError(t, repo.AppendAuditEntry(ctx, &entries[i]))
+	}
+
+	tests := []struct {
+		name   string
+		filter domain.AuditFilter
+		want   []domain.AuditEntry
+	}{
+		{name: "All", filter: domain.AuditFilter{}, want: entries},
+		{name: "Book", filter: domain.AuditFilter{BookID: bookID.String()}, want: entries[:2]},
+		{name: "Actor", filter: domain.AuditFilter{Actor: "alice"}, want: []domain.AuditEntry{entries[0], entries[2]}},
+		{name: "From is inclusive", filter: domain.AuditFilter{From: day.Add(time.Hour)}, want: entries[1:]},
+		{name: "To is exclusive", filter: domain.AuditFilter{To: day.Add(time.Hour)}, want: entries[:1]},
+		{name: "No match", filter: domain.AuditFilter{Actor: "carol"}, want: []domain.AuditEntry{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.FetchAuditEntries(ctx, tt.filter)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, *got)
+		})
+	}
+}
+
+func TestInMemoryAuditRepository(t *testing.T) {
+	testAuditRepository(t, NewInMemoryAuditRepository())
+}
+
+func TestFileAuditRepository(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	repo, err := NewFileAuditRepository(path)
+	require.NoError(t, err)
+	testAuditRepository(t, repo)
+
+	t.Run("Entries survive a restart", func(t *testing.T) {
+		reopened, err := NewFileAuditRepository(path)
+		require.NoError(t, err)
+
+		got, err := reopened.FetchAuditEntries(context.Background(), domain.AuditFilter{})
+		assert.NoError(t, err)
+		assert.Len(t, *got, 3)
+	})
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+// AuditSchema creates the table of SQLAuditRepository, the statements
+// only append to and read from it, so it can be granted INSERT and
+// SELECT only
+const AuditSchema = `CREATE TABLE IF NOT EXISTS audit_entries (
+	id          VARCHAR(36) PRIMARY KEY,
+	occurred_at TIMESTAMP NOT NULL,
+	action      VARCHAR(16) NOT NULL,
+	book_id     VARCHAR(36) NOT NULL,
+	actor       VARCHAR(255) NOT NULL,
+	auth_method VARCHAR(16) NOT NULL,
+	request_id  VARCHAR(128) NOT NULL,
+	changes     TEXT NOT NULL
+)`
+
+// Placeholder returns the bind parameter n, counted from 1, of a SQL
+// statement
+type Placeholder func(n int) string
+
+var (
+	// QuestionPlaceholder is used by MySQL and SQLite, eg. ?
+	QuestionPlaceholder Placeholder = func(int) string { return "?" }
+	// DollarPlaceholder is used by PostgreSQL, eg. $1
+	DollarPlaceholder Placeholder = func(n int) string { return "$" + strconv.Itoa(n) }
+)
+
+// SQLAuditRepository keeps the audit entries in the audit_entries table
+// of AuditSchema
+type SQLAuditRepository struct {
+	db          *sql.DB
+	placeholder Placeholder
+}
+
+// NewSQLAuditRepository keeps the audit entries in db, whose driver
+// must be registered by the binary, with the bind parameters of
+// placeholder
+func NewSQLAuditRepository(db *sql.DB, placeholder Placeholder) domain.AuditRepository {
+	return &SQLAuditRepository{db: db, placeholder: placeholder}
+}
+
+func (r *SQLAuditRepository) AppendAuditEntry(ctx context.Context, entry *domain.AuditEntry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return apperror.NewInternal().WithCause(fmt.Errorf("could not encode audit changes: %w", err))
+	}
+
+	p := r.placeholder
+	query := fmt.Sprintf(
+		"INSERT INTO audit_entries (id, occurred_at, action, book_id, actor, auth_method, request_id, changes) VALUES (%s, %s, %s, %s, %s, %s, %s, %s)",
+		p(1), p(2), p(3), p(4), p(5), p(6), p(7), p(8),
+	)
+	_, err = r.db.ExecContext(ctx, query,
+		entry.ID.String(), entry.Time, string(entry.Action), entry.BookID.String(),
+		entry.Actor, entry.AuthMethod, entry.RequestID, string(changes),
+	)
+	if err != nil {
+		return apperror.NewInternal().WithCause(fmt.Errorf("could not insert audit entry: %w", err))
+	}
+
+	return nil
+}
+
+func (r *SQLAuditRepository) FetchAuditEntries(ctx context.Context, filter domain.AuditFilter) (*[]domain.AuditEntry, error) {
+	query, args := auditQuery(filter, r.placeholder)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, apperror.NewInternal().WithCause(fmt.Errorf("could not query audit entries: %w", err))
+	}
+	defer rows.Close()
+
+	entries := []domain.AuditEntry{}
+	for rows.Next() {
+		var (
+			e          domain.AuditEntry
+			id, bookID string
+			action     string
+			changes    string
+		)
+		if err := rows.Scan(&id, &e.Time, &action, &bookID, &e.Actor, &e.AuthMethod, &e.RequestID, &changes); err != nil {
+			return nil, apperror.NewInternal().WithCause(fmt.Errorf("could not scan audit entry: %w", err))
+		}
+		if err := e.ID.UnmarshalText([]byte(id)); err != nil {
+			return nil, apperror.NewInternal().WithCause(fmt.Errorf("could not parse audit entry ID: %w", err))
+		}
+		if err := e.BookID.UnmarshalText([]byte(bookID)); err != nil {
+			return nil, apperror.NewInternal().WithCause(fmt.Errorf("could not parse book ID of audit entry %s: %w", id, err))
+		}
+		if err := json.Unmarshal([]byte(changes), &e.Changes); err != nil {
+			return nil, apperror.NewInternal().WithCause(fmt.Errorf("could not decode changes of audit entry %s: %w", id, err))
+		}
+		e.Action = domain.AuditAction(action)
+		e.Time = e.Time.UTC()
+
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperror.NewInternal().WithCause(fmt.Errorf("could not read audit entries: %w", err))
+	}
+
+	return &entries, nil
+}
+
+// auditQuery selects the audit entries matching filter, oldest first
+func auditQuery(filter domain.AuditFilter, p Placeholder) (string, []interface{}) {
+	var (
+		where []string
+		args  []interface{}
+	)
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		where = append(where, cond+" "+p(len(args)))
+	}
+
+	if filter.BookID != "" {
+		add("book_id =", filter.BookID)
+	}
+	if filter.Actor != "" {
+		add("actor =", filter.Actor)
+	}
+	if !filter.From.IsZero() {
+		add("occurred_at >=", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("occurred_at <", filter.To)
+	}
+
+	query := "SELECT id, occurred_at, action, book_id, actor, auth_method, request_id, changes FROM audit_entries"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY occurred_at, id"
+
+	return query, args
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// openAuditDB opens an empty in-memory SQLite database with the audit
+// table of AuditSchema
+func openAuditDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	// every connection would open a database of its own
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(AuditSchema)
+	require.NoError(t, err)
+	return db
+}
+
+func TestSQLAuditRepository(t *testing.T) {
+	testAuditRepository(t, NewSQLAuditRepository(openAuditDB(t), QuestionPlaceholder))
+
+	t.Run("Append only", func(t *testing.T) {
+		ctx := context.Background()
+		repo := NewSQLAuditRepository(openAuditDB(t), QuestionPlaceholder)
+		entry := domain.AuditEntry{ID: uuid.New(), Time: time.Now().UTC(), Action: domain.AuditActionDelete, BookID: uuid.New(), Actor: "alice", Changes: []domain.FieldChange{}}
+		require.NoError(t, repo.AppendAuditEntry(ctx, &entry))
+
+		// the ID is the primary key, an entry cannot be written twice
+		assert.Error(t, repo.AppendAuditEntry(ctx, &entry))
+	})
+
+	t.Run("Closed database", func(t *testing.T) {
+		db := openAuditDB(t)
+		repo := NewSQLAuditRepository(db, QuestionPlaceholder)
+		db.Close()
+
+		_, err := repo.FetchAuditEntries(context.Background(), domain.AuditFilter{})
+		assert.Error(t, err)
+	})
+}
+
+func TestAuditQuery(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	const columns = "SELECT id, occurred_at, action, book_id, actor, auth_method, request_id, changes FROM audit_entries"
+
+	tests := []struct {
+		name        string
+		filter      domain.AuditFilter
+		placeholder Placeholder
+		query       string
+		args        []interface{}
+	}{
+		{
+			name:        "All",
+			placeholder: QuestionPlaceholder,
+			query:       columns + " ORDER BY occurred_at, id",
+		},
+		{
+			name:        "Question",
+			filter:      domain.AuditFilter{BookID: "b", Actor: "alice", From: from, To: to},
+			placeholder: QuestionPlaceholder,
+			query:       columns + " WHERE book_id = ? AND actor = ? AND occurred_at >= ? AND occurred_at < ? ORDER BY occurred_at, id",
+			args:        []interface{}{"b", "alice", from, to},
+		},
+		{
+			name:        "Dollar",
+			filter:      domain.AuditFilter{Actor: "alice", To: to},
+			placeholder: DollarPlaceholder,
+			query:       columns + " WHERE actor = $1 AND occurred_at < $2 ORDER BY occurred_at, id",
+			args:        []interface{}{"alice", to},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := auditQuery(tt.filter, tt.placeholder)
+
+			assert.Equal(t, tt.query, query)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
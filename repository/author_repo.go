@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+type InMemoryAuthorRepository struct {
+	authors []domain.Author
+	mu      sync.Mutex
+}
+
+func NewInMemoryAuthorRepository() domain.AuthorRepository {
+	return &InMemoryAuthorRepository{
+		authors: []domain.Author{},
+	}
+}
+
+func (r *InMemoryAuthorRepository) FetchAuthors(ctx context.Context, filter domain.AuthorFilter) (*[]domain.Author, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	authors := []domain.Author{}
+	for i := range r.authors {
+		if filter.Match(&r.authors[i]) {
+			authors = append(authors, r.authors[i])
+		}
+	}
+	sort.SliceStable(authors, func(i, j int) bool {
+		return authors[i].Name < authors[j].Name
+	})
+
+	return &authors, nil
+}
+
+func (r *InMemoryAuthorRepository) GetAuthorByID(ctx context.Context, id string) (*domain.Author, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, author := range r.authors {
+		if author.ID.String() == id {
+			return &author, nil
+		}
+	}
+
+	return nil, apperror.NewNotFound("Author", "ID", id)
+}
+
+func (r *InMemoryAuthorRepository) GetAuthorByName(ctx context.Context, name string) (*domain.Author, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := domain.AuthorKey(name)
+	for _, author := range r.authors {
+		if domain.AuthorKey(author.Name) == key {
+			return &author, nil
+		}
+	}
+
+	return nil, apperror.NewNotFound("Author", "name", name)
+}
+
+func (r *InMemoryAuthorRepository) CreateAuthor(ctx context.Context, author *domain.Author) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkDuplicate(author, uuid.Nil); err != nil {
+		return err
+	}
+
+	author.ID = uuid.New()
+	r.authors = append(r.authors, *author)
+
+	return nil
+}
+
+func (r *InMemoryAuthorRepository) UpdateAuthor(ctx context.Context, id string, author *domain.Author) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, a := range r.authors {
+		if a.ID.String() == id {
+			if err := r.checkDuplicate(author, a.ID); err != nil {
+				return err
+			}
+			author.ID = a.ID
+			r.authors[i] = *author
+			return nil
+		}
+	}
+
+	return apperror.NewNotFound("Author", "ID", id)
+}
+
+func (r *InMemoryAuthorRepository) DeleteAuthor(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, author := range r.authors {
+		if author.ID.String() == id {
+			r.authors = append(r.authors[:i], r.authors[i+1:]...)
+			return nil
+		}
+	}
+
+	return apperror.NewNotFound("Author", "ID", id)
+}
+
+// checkDuplicate rejects author when an author other than except has a
+// name with the same domain.AuthorKey, r.mu must be held
+func (r *InMemoryAuthorRepository) checkDuplicate(author *domain.Author, except uuid.UUID) error {
+	key := domain.AuthorKey(author.Name)
+	for _, a := range r.authors {
+		if a.ID != except && domain.AuthorKey(a.Name) == key {
+			return apperror.NewConflict("author", a.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryAuthorRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create, fetch by name and get", func(t *testing.T) {
+		repo := NewInMemoryAuthorRepository()
+		tolkien := &domain.Author{Name: "J. R. R. Tolkien"}
+		austen := &domain.Author{Name: "Jane Austen"}
+		assert.Nil(t, repo.CreateAuthor(ctx, tolkien))
+		assert.Nil(t, repo.CreateAuthor(ctx, austen))
+		assert.NotEqual(t, uuid.Nil, tolkien.ID)
+
+		authors, err := repo.FetchAuthors(ctx, domain.AuthorFilter{})
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Author{*tolkien, *austen}, *authors)
+
+		authors, err = repo.FetchAuthors(ctx, domain.AuthorFilter{Name: "AUSTEN"})
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Author{*austen}, *authors)
+
+		found, err := repo.GetAuthorByID(ctx, austen.ID.String())
+		assert.Nil(t, err)
+		assert.Equal(t, austen, found)
+
+		found, err = repo.GetAuthorByName(ctx, "jrr tolkien")
+		assert.Nil(t, err)
+		assert.Equal(t, tolkien, found)
+	})
+
+	t.Run("Same name spelt differently", func(t *testing.T) {
+		repo := NewInMemoryAuthorRepository()
+		repo.CreateAuthor(ctx, &domain.Author{Name: "J.K. Rowling"})
+
+		err := repo.CreateAuthor(ctx, &domain.Author{Name: "j. k. rowling"})
+		assert.Equal(t, apperror.Conflict, err.(*apperror.Error).Type)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := NewInMemoryAuthorRepository()
+		rowling := &domain.Author{Name: "J.K. Rowling"}
+		galbraith := &domain.Author{Name: "Robert Galbraith"}
+		repo.CreateAuthor(ctx, rowling)
+		repo.CreateAuthor(ctx, galbraith)
+
+		// an author may respell its own name
+		err := repo.UpdateAuthor(ctx, rowling.ID.String(), &domain.Author{Name: "J. K. Rowling", Bio: "British author"})
+		assert.Nil(t, err)
+		found, _ := repo.GetAuthorByID(ctx, rowling.ID.String())
+		assert.Equal(t, "J. K. Rowling", found.Name)
+
+		err = repo.UpdateAuthor(ctx, galbraith.ID.String(), &domain.Author{Name: "JK Rowling"})
+		assert.Equal(t, apperror.Conflict, err.(*apperror.Error).Type)
+
+		err = repo.UpdateAuthor(ctx, uuid.New().String(), &domain.Author{Name: "Nobody"})
+		assert.Equal(t, apperror.NotFound, err.(*apperror.Error).Type)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := NewInMemoryAuthorRepository()
+		author := &domain.Author{Name: "Jane Austen"}
+		repo.CreateAuthor(ctx, author)
+
+		assert.Nil(t, repo.DeleteAuthor(ctx, author.ID.String()))
+
+		_, err := repo.GetAuthorByID(ctx, author.ID.String())
+		assert.Equal(t, apperror.NotFound, err.(*apperror.Error).Type)
+		err = repo.DeleteAuthor(ctx, author.ID.String())
+		assert.Equal(t, apperror.NotFound, err.(*apperror.Error).Type)
+	})
+}
@@ -2,7 +2,12 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/krittawatcode/books/domain"
@@ -10,30 +15,52 @@ import (
 )
 
 type InMemoryBookRepository struct {
-	books []domain.Book
-	mu    sync.Mutex
+	books     []domain.Book
+	trash     []domain.TrashedBook
+	revisions map[uuid.UUID][]domain.BookRevision    // every revision of each book, trashed ones included
+	series    map[uuid.UUID][]domain.SeriesNeighbour // the books of each series in reading order, trashed ones left out
+	mu        sync.Mutex
+	now       func() time.Time
 }
 
 func NewInMemoryBookRepository() domain.BookRepository {
 	return &InMemoryBookRepository{
-		books: []domain.Book{},
+		books:     []domain.Book{},
+		trash:     []domain.TrashedBook{},
+		revisions: map[uuid.UUID][]domain.BookRevision{},
+		series:    map[uuid.UUID][]domain.SeriesNeighbour{},
+		now:       time.Now,
 	}
 }
 
-func (r *InMemoryBookRepository) FetchBooks(ctx context.Context) (*[]domain.Book, error) {
+func (r *InMemoryBookRepository) FetchBooks(ctx context.Context, filter domain.BookFilter) (*[]domain.Book, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if len(r.books) == 0 {
+	books := []domain.Book{}
+	for i := range r.books {
+		if filter.Match(&r.books[i]) {
+			books = append(books, cloneBook(r.books[i]))
+		}
+	}
+
+	if len(books) == 0 {
 		return nil, apperror.NewNotFound("Book", "ID", "")
 	}
+	if filter.SeriesID != uuid.Nil {
+		domain.SortBySeries(books, filter.SeriesID)
+	}
 
-	return &r.books, nil
+	return &books, nil
 }
 
 func (r *InMemoryBookRepository) GetBookByID(ctx context.Context, id string) (*domain.Book, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	for _, book := range r.books {
 		if book.ID.String() == id {
+			book = cloneBook(book)
 			return &book, nil
 		}
 	}
@@ -41,19 +68,66 @@ func (r *InMemoryBookRepository) GetBookByID(ctx context.Context, id string) (*d
 	return nil, apperror.NewNotFound("Book", "ID", id)
 }
 
-func (r *InMemoryBookRepository) CreateBook(ctx context.Context, book *domain.Book) error {
+func (r *InMemoryBookRepository) GetBookByISBN(ctx context.Context, isbn string) (*domain.Book, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// check if book already exists
-	for _, b := range r.books {
-		if b.Title == book.Title && b.Author == book.Author && b.PublicationYear == book.PublicationYear {
-			return apperror.NewConflict("book", "title, author, and publication year")
+	for _, book := range r.books {
+		if book.ISBN != "" && book.ISBN == isbn {
+			book = cloneBook(book)
+			return &book, nil
+		}
+	}
+
+	return nil, apperror.NewNotFound("Book", "ISBN", isbn)
+}
+
+// GetSeriesNeighbours looks the neighbours of the book up in the series
+// index, without going through the books of each series
+func (r *InMemoryBookRepository) GetSeriesNeighbours(ctx context.Context, id string) ([]domain.SeriesEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bookID, err := uuid.Parse(id)
+	i := r.indexOf(bookID)
+	if err != nil || i < 0 {
+		return nil, apperror.NewNotFound("Book", "ID", id)
+	}
+
+	book := &r.books[i]
+	entries := make([]domain.SeriesEntry, len(book.Series))
+	for k, e := range book.Series {
+		// positions are unique within a series, j is the book's own
+		books := r.series[e.SeriesID]
+		j := sort.Search(len(books), func(j int) bool { return books[j].Position >= e.Position })
+		if j > 0 {
+			previous := books[j-1]
+			e.Previous = &previous
+		}
+		if j < len(books)-1 {
+			next := books[j+1]
+			e.Next = &next
 		}
+		entries[k] = e
+	}
+
+	return entries, nil
+}
+
+func (r *InMemoryBookRepository) CreateBook(ctx context.Context, book *domain.Book) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// check if book already exists, trashed books are left out so a
+	// deleted book can be created again; restoring checks instead
+	if err := r.checkDuplicate(ctx, book, uuid.Nil); err != nil {
+		return err
 	}
 
 	book.ID = uuid.New()
-	r.books = append(r.books, *book)
+	r.books = append(r.books, cloneBook(*book))
+	r.index(book)
+	r.addRevision(domain.AuditActionCreate, *book, r.now())
 
 	return nil
 }
@@ -64,8 +138,17 @@ func (r *InMemoryBookRepository) UpdateBook(ctx context.Context, id string, book
 
 	for i, b := range r.books {
 		if b.ID.String() == id {
+			if err := r.checkISBN(ctx, book, b.ID); err != nil {
+				return err
+			}
+			if err := r.checkSeries(ctx, book, b.ID); err != nil {
+				return err
+			}
 			book.ID = b.ID
-			r.books[i] = *book
+			r.unindex(&b)
+			r.books[i] = cloneBook(*book)
+			r.index(book)
+			r.addRevision(domain.AuditActionUpdate, *book, r.now())
 			return nil
 		}
 	}
@@ -73,16 +156,290 @@ func (r *InMemoryBookRepository) UpdateBook(ctx context.Context, id string, book
 	return apperror.NewNotFound("Book", "ID", id)
 }
 
-func (r *InMemoryBookRepository) DeleteBook(ctx context.Context, id string) error {
+func (r *InMemoryBookRepository) DeleteBook(ctx context.Context, id string, deletedBy string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	for i, book := range r.books {
 		if book.ID.String() == id {
+			now := r.now()
 			r.books = append(r.books[:i], r.books[i+1:]...)
+			r.unindex(&book)
+			r.trash = append(r.trash, domain.TrashedBook{Book: book, DeletedAt: now.UTC(), DeletedBy: deletedBy})
+			r.addRevision(domain.AuditActionDelete, book, now)
 			return nil
 		}
 	}
 
 	return apperror.NewNotFound("Book", "ID", id)
 }
+
+func (r *InMemoryBookRepository) FetchBookHistory(ctx context.Context, id string) (*[]domain.BookRevision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	revisions := r.revisionsOf(id)
+	if len(revisions) == 0 {
+		return nil, apperror.NewNotFound("Book", "ID", id)
+	}
+
+	history := make([]domain.BookRevision, len(revisions))
+	for i, rev := range revisions {
+		rev.Book = cloneBook(rev.Book)
+		history[i] = rev
+	}
+
+	return &history, nil
+}
+
+func (r *InMemoryBookRepository) GetBookAsOf(ctx context.Context, id string, at time.Time) (*domain.Book, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	revisions := r.revisionsOf(id)
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if revisions[i].Time.After(at) {
+			continue
+		}
+		if revisions[i].Action == domain.AuditActionDelete {
+			break
+		}
+
+		book := cloneBook(revisions[i].Book)
+		return &book, nil
+	}
+
+	return nil, apperror.NewNotFound("Book", "ID", id)
+}
+
+func (r *InMemoryBookRepository) RevertBook(ctx context.Context, id string, revision int) (*domain.Book, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	revisions := r.revisionsOf(id)
+	if len(revisions) == 0 {
+		return nil, apperror.NewNotFound("Book", "ID", id)
+	}
+	if revision < 1 || revision > len(revisions) {
+		return nil, apperror.NewNotFound("BookRevision", "revision", strconv.Itoa(revision))
+	}
+	if revisions[revision-1].Action == domain.AuditActionDelete {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("revision %d deleted the book, revert to an earlier one", revision))
+	}
+
+	book := cloneBook(revisions[revision-1].Book)
+	if err := r.checkDuplicate(ctx, &book, book.ID); err != nil {
+		return nil, err
+	}
+
+	// a trashed book is taken out of the trash
+	if i := r.indexOf(book.ID); i >= 0 {
+		r.unindex(&r.books[i])
+		r.books[i] = book
+	} else {
+		r.untrash(book.ID)
+		r.books = append(r.books, book)
+	}
+	r.index(&book)
+	r.addRevision(domain.AuditActionRevert, book, r.now())
+
+	book = cloneBook(book)
+	return &book, nil
+}
+
+func (r *InMemoryBookRepository) FetchTrash(ctx context.Context) (*[]domain.TrashedBook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	trash := make([]domain.TrashedBook, len(r.trash))
+	for i, b := range r.trash {
+		b.Book = cloneBook(b.Book)
+		trash[len(r.trash)-1-i] = b
+	}
+
+	return &trash, nil
+}
+
+func (r *InMemoryBookRepository) RestoreBook(ctx context.Context, id string) (*domain.Book, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, t := range r.trash {
+		if t.ID.String() != id {
+			continue
+		}
+
+		book := t.Book
+		if err := r.checkDuplicate(ctx, &book, book.ID); err != nil {
+			return nil, err
+		}
+
+		r.untrash(book.ID)
+		r.books = append(r.books, book)
+		r.index(&book)
+		r.addRevision(domain.AuditActionRestore, book, r.now())
+
+		book = cloneBook(book)
+		return &book, nil
+	}
+
+	return nil, apperror.NewNotFound("TrashedBook", "ID", id)
+}
+
+func (r *InMemoryBookRepository) PurgeTrash(ctx context.Context, deletedBefore time.Time) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := []uuid.UUID{}
+	kept := r.trash[:0]
+	for _, t := range r.trash {
+		if t.DeletedAt.Before(deletedBefore) {
+			delete(r.revisions, t.ID)
+			purged = append(purged, t.ID)
+			continue
+		}
+		kept = append(kept, t)
+	}
+	r.trash = kept
+
+	return purged, nil
+}
+
+// checkDuplicate rejects book when a book of the catalog other than
+// except is the same edition, has the same ISBN or the same series
+// position, r.mu must be held. Other editions of a work, eg. the
+// paperback of a hardcover or a translation, are not duplicates
+func (r *InMemoryBookRepository) checkDuplicate(ctx context.Context, book *domain.Book, except uuid.UUID) error {
+	for _, b := range r.books {
+		if b.ID != except && b.SameEdition(book) {
+			slog.DebugContext(ctx, "duplicate book rejected", "book_id", b.ID.String())
+			return apperror.NewConflict("book", "title, author, publication year and edition")
+		}
+	}
+	if err := r.checkISBN(ctx, book, except); err != nil {
+		return err
+	}
+	return r.checkSeries(ctx, book, except)
+}
+
+// checkISBN rejects book when a book of the catalog other than except
+// has the same ISBN, r.mu must be held
+func (r *InMemoryBookRepository) checkISBN(ctx context.Context, book *domain.Book, except uuid.UUID) error {
+	if book.ISBN == "" {
+		return nil
+	}
+	for _, b := range r.books {
+		if b.ID != except && b.ISBN == book.ISBN {
+			slog.DebugContext(ctx, "duplicate ISBN rejected", "book_id", b.ID.String())
+			return apperror.NewConflict("book ISBN", book.ISBN)
+		}
+	}
+	return nil
+}
+
+// checkSeries rejects book when a book of the catalog other than except
+// has the same position in one of its series, so the reading order of
+// each series stays total. Trashed books are left out, their positions
+// are checked again when restored. r.mu must be held
+func (r *InMemoryBookRepository) checkSeries(ctx context.Context, book *domain.Book, except uuid.UUID) error {
+	for _, e := range book.Series {
+		for _, b := range r.books {
+			if other, ok := b.SeriesEntry(e.SeriesID); ok && b.ID != except && other.Position == e.Position {
+				slog.DebugContext(ctx, "duplicate series position rejected", "book_id", b.ID.String())
+				return apperror.NewConflict("series position", strconv.FormatFloat(e.Position, 'f', -1, 64))
+			}
+		}
+	}
+	return nil
+}
+
+// indexOf returns the index of the book id in r.books, or -1, r.mu must
+// be held
+func (r *InMemoryBookRepository) indexOf(id uuid.UUID) int {
+	for i, b := range r.books {
+		if b.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// index adds book to the series index at its position in each of its
+// series, r.mu must be held
+func (r *InMemoryBookRepository) index(book *domain.Book) {
+	for _, e := range book.Series {
+		books := r.series[e.SeriesID]
+		j := sort.Search(len(books), func(j int) bool { return books[j].Position > e.Position })
+		books = append(books, domain.SeriesNeighbour{})
+		copy(books[j+1:], books[j:])
+		books[j] = domain.SeriesNeighbour{ID: book.ID, Title: book.Title, Position: e.Position}
+		r.series[e.SeriesID] = books
+	}
+}
+
+// unindex removes book from the series index, r.mu must be held
+func (r *InMemoryBookRepository) unindex(book *domain.Book) {
+	for _, e := range book.Series {
+		books := r.series[e.SeriesID]
+		for j := range books {
+			if books[j].ID == book.ID {
+				books = append(books[:j], books[j+1:]...)
+				break
+			}
+		}
+		if len(books) == 0 {
+			delete(r.series, e.SeriesID)
+			continue
+		}
+		r.series[e.SeriesID] = books
+	}
+}
+
+// untrash removes the book id from the trash, if there, r.mu must be held
+func (r *InMemoryBookRepository) untrash(id uuid.UUID) {
+	for i, t := range r.trash {
+		if t.ID == id {
+			r.trash = append(r.trash[:i], r.trash[i+1:]...)
+			return
+		}
+	}
+}
+
+// revisionsOf returns the revisions of the book id, r.mu must be held
+func (r *InMemoryBookRepository) revisionsOf(id string) []domain.BookRevision {
+	bookID, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+	return r.revisions[bookID]
+}
+
+// addRevision records the state of book after action, r.mu must be held
+func (r *InMemoryBookRepository) addRevision(action domain.AuditAction, book domain.Book, at time.Time) {
+	r.revisions[book.ID] = append(r.revisions[book.ID], domain.BookRevision{
+		Revision: len(r.revisions[book.ID]) + 1,
+		Time:     at.UTC(),
+		Action:   action,
+		Book:     cloneBook(book),
+	})
+}
+
+// cloneBook copies the slices and IDs of book, so the books stored and
+// those handed to the callers do not change along with each other. Every
+// book going into or out of the repository is cloned
+func cloneBook(book domain.Book) domain.Book {
+	// s[:0:0] keeps a nil slice nil and an empty one empty
+	book.Genres = append(book.Genres[:0:0], book.Genres...)
+	book.Subjects = append(book.Subjects[:0:0], book.Subjects...)
+	book.Contributors = append(book.Contributors[:0:0], book.Contributors...)
+	book.Series = append(book.Series[:0:0], book.Series...)
+	if book.PublisherID != nil {
+		publisherID := *book.PublisherID
+		book.PublisherID = &publisherID
+	}
+	if book.WorkID != nil {
+		workID := *book.WorkID
+		book.WorkID = &workID
+	}
+	return book
+}
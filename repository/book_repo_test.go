@@ -2,11 +2,16 @@ package repository
 
 import (
 	"context"
+	"net/http"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFetchBooks(t *testing.T) {
@@ -19,7 +24,7 @@ func TestFetchBooks(t *testing.T) {
 		assert.Nil(t, err)
 
 		// Fetch books from the repository
-		books, err := repo.FetchBooks(context.Background())
+		books, err := repo.FetchBooks(context.Background(), domain.BookFilter{})
 		assert.Nil(t, err)
 
 		// Check that the fetched books include the added book
@@ -30,12 +35,54 @@ func TestFetchBooks(t *testing.T) {
 		repo := NewInMemoryBookRepository()
 
 		// Fetch books from the repository
-		books, err := repo.FetchBooks(context.Background())
+		books, err := repo.FetchBooks(context.Background(), domain.BookFilter{})
 
 		// Check that an error is returned and that no books are fetched
 		assert.Error(t, err)
 		assert.Nil(t, books)
 	})
+
+	t.Run("Filtered", func(t *testing.T) {
+		repo := NewInMemoryBookRepository()
+		ctx := context.Background()
+		gopl := &domain.Book{Title: "The Go Programming Language", Author: "Alan Donovan", PublicationDate: "2015",
+			ISBN: "9780134190440", Language: "en", Format: domain.FormatPaperback, Genres: []string{"Programming"}}
+		dune := &domain.Book{Title: "Dune", Author: "Frank Herbert", PublicationDate: "1965",
+			Language: "en", Format: domain.FormatEbook, Genres: []string{"Science Fiction"}, Subjects: []string{"Arrakis"}}
+		require.NoError(t, repo.CreateBook(ctx, gopl))
+		require.NoError(t, repo.CreateBook(ctx, dune))
+
+		tests := []struct {
+			name   string
+			filter domain.BookFilter
+			want   []domain.Book
+		}{
+			{name: "Title part", filter: domain.BookFilter{Title: "go prog"}, want: []domain.Book{*gopl}},
+			{name: "Author part", filter: domain.BookFilter{Author: "herbert"}, want: []domain.Book{*dune}},
+			{name: "Language", filter: domain.BookFilter{Language: "EN"}, want: []domain.Book{*gopl, *dune}},
+			{name: "Format", filter: domain.BookFilter{Format: domain.FormatEbook}, want: []domain.Book{*dune}},
+			{name: "Genre", filter: domain.BookFilter{Genre: "science fiction"}, want: []domain.Book{*dune}},
+			{name: "Subject", filter: domain.BookFilter{Subject: "arrakis"}, want: []domain.Book{*dune}},
+			{name: "Published before", filter: domain.BookFilter{PublishedBefore: "2000"}, want: []domain.Book{*dune}},
+			{name: "Published after", filter: domain.BookFilter{PublishedAfter: "1965"}, want: []domain.Book{*gopl}},
+			{name: "Every field", filter: domain.BookFilter{ISBN: "9780134190440", Format: domain.FormatPaperback}, want: []domain.Book{*gopl}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				books, err := repo.FetchBooks(ctx, tt.filter)
+
+				assert.NoError(t, err)
+				assert.ElementsMatch(t, tt.want, *books)
+			})
+		}
+
+		t.Run("No match", func(t *testing.T) {
+			_, err := repo.FetchBooks(ctx, domain.BookFilter{Genre: "Poetry"})
+
+			assert.Equal(t, http.StatusNotFound, apperror.Status(err))
+		})
+	})
 }
 
 func TestGetBookByID(t *testing.T) {
@@ -58,12 +105,200 @@ func TestGetBookByID(t *testing.T) {
 		assert.Error(t, err)
 		assert.Nil(t, fetchedBook)
 	})
+
+	t.Run("Lists are not shared", func(t *testing.T) {
+		ctx := context.Background()
+		repo := NewInMemoryBookRepository()
+		book := &domain.Book{Title: "Go", Author: "Rob", PublicationDate: "2015", Genres: []string{"Programming"}, Subjects: []string{"Go"}}
+		require.NoError(t, repo.CreateBook(ctx, book))
+
+		// changed in place by the caller, and through every read
+		book.Subjects[0] = "Rust"
+		fetched, _ := repo.GetBookByID(ctx, book.ID.String())
+		fetched.Genres[0] = "Fiction"
+		books, _ := repo.FetchBooks(ctx, domain.BookFilter{})
+		(*books)[0].Subjects[0] = "C"
+		update := &domain.Book{Title: "Go", Author: "Rob", PublicationDate: "2015", Genres: []string{"Programming"}, Subjects: []string{"Go"}}
+		require.NoError(t, repo.UpdateBook(ctx, book.ID.String(), update))
+		update.Genres[0] = "Poetry"
+
+		fetched, _ = repo.GetBookByID(ctx, book.ID.String())
+		assert.Equal(t, []string{"Programming"}, fetched.Genres)
+		assert.Equal(t, []string{"Go"}, fetched.Subjects)
+	})
+}
+
+func TestISBN(t *testing.T) {
+	ctx := context.Background()
+	const isbn = "9780134190440"
+
+	// newRepo returns a repository holding a book with isbn
+	newRepo := func(t *testing.T) (domain.BookRepository, *domain.Book) {
+		repo := NewInMemoryBookRepository()
+		book := &domain.Book{Title: "The Go Programming Language", Author: "Alan Donovan", PublicationDate: "2015", ISBN: isbn}
+		require.NoError(t, repo.CreateBook(ctx, book))
+
+		return repo, book
+	}
+
+	t.Run("GetBookByISBN", func(t *testing.T) {
+		repo, book := newRepo(t)
+
+		found, err := repo.GetBookByISBN(ctx, isbn)
+		assert.NoError(t, err)
+		assert.Equal(t, book, found)
+
+		_, err = repo.GetBookByISBN(ctx, "9780804429573")
+		assert.Equal(t, http.StatusNotFound, apperror.Status(err))
+	})
+
+	t.Run("Conflict on create", func(t *testing.T) {
+		repo, _ := newRepo(t)
+
+		err := repo.CreateBook(ctx, &domain.Book{Title: "Another title", Author: "Alan Donovan", PublicationDate: "2016", ISBN: isbn})
+
+		assert.Equal(t, http.StatusConflict, apperror.Status(err))
+	})
+
+	t.Run("Conflict on update", func(t *testing.T) {
+		repo, book := newRepo(t)
+		other := &domain.Book{Title: "Dune", Author: "Frank Herbert", PublicationDate: "1965"}
+		require.NoError(t, repo.CreateBook(ctx, other))
+
+		err := repo.UpdateBook(ctx, other.ID.String(), &domain.Book{Title: "Dune", Author: "Frank Herbert", PublicationDate: "1965", ISBN: isbn})
+		assert.Equal(t, http.StatusConflict, apperror.Status(err))
+
+		// a book keeps its own ISBN
+		err = repo.UpdateBook(ctx, book.ID.String(), &domain.Book{Title: "The Go Programming Language", Author: "Alan Donovan", PublicationDate: "2015", ISBN: isbn, Pages: 380})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Trashed books", func(t *testing.T) {
+		repo, book := newRepo(t)
+		require.NoError(t, repo.DeleteBook(ctx, book.ID.String(), "alice"))
+
+		_, err := repo.GetBookByISBN(ctx, isbn)
+		assert.Equal(t, http.StatusNotFound, apperror.Status(err))
+
+		// the ISBN is free again, and restoring the trashed book conflicts
+		require.NoError(t, repo.CreateBook(ctx, &domain.Book{Title: "The Go Programming Language", Author: "Alan A. A. Donovan", PublicationDate: "2015", ISBN: isbn}))
+		_, err = repo.RestoreBook(ctx, book.ID.String())
+		assert.Equal(t, http.StatusConflict, apperror.Status(err))
+	})
+}
+
+func TestSeries(t *testing.T) {
+	ctx := context.Background()
+	discworld := uuid.New()
+
+	// newRepo returns a repository holding books of the series at
+	// positions 2, 1 and 2.5, created out of order
+	newRepo := func(t *testing.T) (domain.BookRepository, []*domain.Book) {
+		repo := NewInMemoryBookRepository()
+		var books []*domain.Book
+		for _, b := range []struct {
+			title    string
+			position float64
+		}{{"The Light Fantastic", 2}, {"The Colour of Magic", 1}, {"Troll Bridge", 2.5}} {
+			book := &domain.Book{Title: b.title, Author: "Terry Pratchett", PublicationDate: "1986", Series: []domain.SeriesEntry{{SeriesID: discworld, Position: b.position}}}
+			require.NoError(t, repo.CreateBook(ctx, book))
+			books = append(books, book)
+		}
+
+		return repo, books
+	}
+	titles := func(books *[]domain.Book) []string {
+		var titles []string
+		for _, b := range *books {
+			titles = append(titles, b.Title)
+		}
+		return titles
+	}
+
+	t.Run("Reading order", func(t *testing.T) {
+		repo, _ := newRepo(t)
+
+		books, err := repo.FetchBooks(ctx, domain.BookFilter{SeriesID: discworld})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"The Colour of Magic", "The Light Fantastic", "Troll Bridge"}, titles(books))
+	})
+
+	t.Run("Position taken", func(t *testing.T) {
+		repo, books := newRepo(t)
+
+		err := repo.CreateBook(ctx, &domain.Book{Title: "Equal Rites", Author: "Terry Pratchett", PublicationDate: "1987", Series: []domain.SeriesEntry{{SeriesID: discworld, Position: 2.5}}})
+		assert.Equal(t, http.StatusConflict, apperror.Status(err))
+
+		update := *books[0]
+		update.Series = []domain.SeriesEntry{{SeriesID: discworld, Position: 1}}
+		err = repo.UpdateBook(ctx, books[0].ID.String(), &update)
+		assert.Equal(t, http.StatusConflict, apperror.Status(err))
+
+		// a book keeps its own position, and the same position in
+		// another series is free
+		update.Series = []domain.SeriesEntry{{SeriesID: discworld, Position: 2}, {SeriesID: uuid.New(), Position: 1}}
+		assert.NoError(t, repo.UpdateBook(ctx, books[0].ID.String(), &update))
+	})
+
+	t.Run("Deleted books", func(t *testing.T) {
+		repo, books := newRepo(t)
+		require.NoError(t, repo.DeleteBook(ctx, books[0].ID.String(), "alice"))
+
+		// the order of the others holds, and the position is free again
+		found, err := repo.FetchBooks(ctx, domain.BookFilter{SeriesID: discworld})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"The Colour of Magic", "Troll Bridge"}, titles(found))
+
+		require.NoError(t, repo.CreateBook(ctx, &domain.Book{Title: "Equal Rites", Author: "Terry Pratchett", PublicationDate: "1987", Series: []domain.SeriesEntry{{SeriesID: discworld, Position: 2}}}))
+		_, err = repo.RestoreBook(ctx, books[0].ID.String())
+		assert.Equal(t, http.StatusConflict, apperror.Status(err))
+	})
+
+	t.Run("Neighbours", func(t *testing.T) {
+		repo, books := newRepo(t)
+		neighbour := func(b *domain.Book, position float64) *domain.SeriesNeighbour {
+			return &domain.SeriesNeighbour{ID: b.ID, Title: b.Title, Position: position}
+		}
+		witches := uuid.New()
+
+		entries, err := repo.GetSeriesNeighbours(ctx, books[0].ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.SeriesEntry{{SeriesID: discworld, Position: 2, Previous: neighbour(books[1], 1), Next: neighbour(books[2], 2.5)}}, entries)
+
+		// moved to the end, renamed and added to another series
+		update := *books[1]
+		update.Title = "The Colour of Magic (Revised)"
+		update.Series = []domain.SeriesEntry{{SeriesID: discworld, Position: 3}, {SeriesID: witches, Position: 1}}
+		require.NoError(t, repo.UpdateBook(ctx, books[1].ID.String(), &update))
+		entries, err = repo.GetSeriesNeighbours(ctx, books[1].ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.SeriesEntry{{SeriesID: discworld, Position: 3, Previous: neighbour(books[2], 2.5)}, {SeriesID: witches, Position: 1}}, entries)
+
+		// trashed books are left out until restored
+		require.NoError(t, repo.DeleteBook(ctx, books[2].ID.String(), "alice"))
+		entries, _ = repo.GetSeriesNeighbours(ctx, books[1].ID.String())
+		assert.Equal(t, neighbour(books[0], 2), entries[0].Previous)
+		_, err = repo.RestoreBook(ctx, books[2].ID.String())
+		require.NoError(t, err)
+		entries, _ = repo.GetSeriesNeighbours(ctx, books[1].ID.String())
+		assert.Equal(t, neighbour(books[2], 2.5), entries[0].Previous)
+
+		// reverted to its first revision, at the start again
+		_, err = repo.RevertBook(ctx, books[1].ID.String(), 1)
+		require.NoError(t, err)
+		entries, _ = repo.GetSeriesNeighbours(ctx, books[0].ID.String())
+		assert.Equal(t, neighbour(books[1], 1), entries[0].Previous)
+
+		_, err = repo.GetSeriesNeighbours(ctx, "not-an-id")
+		assert.Equal(t, http.StatusNotFound, apperror.Status(err))
+	})
 }
 
 func TestCreateBook(t *testing.T) {
 	t.Run("Failure - Book already exists", func(t *testing.T) {
 		repo := NewInMemoryBookRepository()
-		book := &domain.Book{Title: "Test Book", Author: "Test Author", PublicationYear: "2021"}
+		book := &domain.Book{Title: "Test Book", Author: "Test Author", PublicationDate: "2021"}
 		err := repo.CreateBook(context.Background(), book)
 		assert.Nil(t, err)
 
@@ -71,9 +306,26 @@ func TestCreateBook(t *testing.T) {
 		err = repo.CreateBook(context.Background(), book)
 		assert.Error(t, err)
 	})
+	t.Run("Other editions of the same book", func(t *testing.T) {
+		repo := NewInMemoryBookRepository()
+		hardcover := &domain.Book{Title: "Dune", Author: "Frank Herbert", PublicationDate: "1965", Format: domain.FormatHardcover, ISBN: "9780801950773"}
+		assert.Nil(t, repo.CreateBook(context.Background(), hardcover))
+
+		for _, book := range []*domain.Book{
+			{Title: "Dune", Author: "Frank Herbert", PublicationDate: "1965", Format: domain.FormatPaperback},
+			{Title: "Dune", Author: "Frank Herbert", PublicationDate: "1965", Format: domain.FormatHardcover, Edition: "2nd"},
+			{Title: "Dune", Author: "Frank Herbert", PublicationDate: "1965", Format: domain.FormatHardcover, Language: "fr"},
+			{Title: "Dune", Author: "Frank Herbert", PublicationDate: "1965", Format: domain.FormatHardcover, ISBN: "9780441172719"},
+		} {
+			assert.Nil(t, repo.CreateBook(context.Background(), book))
+		}
+
+		err := repo.CreateBook(context.Background(), &domain.Book{Title: "Dune", Author: "Frank Herbert", PublicationDate: "1965", Format: domain.FormatHardcover, Edition: "2ND"})
+		assert.Equal(t, apperror.Conflict, err.(*apperror.Error).Type)
+	})
 	t.Run("Concurrent calls", func(t *testing.T) {
 		repo := NewInMemoryBookRepository()
-		book := &domain.Book{Title: "Test Book", Author: "Test Author", PublicationYear: "2021"}
+		book := &domain.Book{Title: "Test Book", Author: "Test Author", PublicationDate: "2021"}
 
 		var wg sync.WaitGroup
 		errs := make(chan error)
@@ -105,7 +357,7 @@ func TestCreateBook(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		repo := NewInMemoryBookRepository()
-		book := &domain.Book{Title: "Test Book", Author: "Test Author", PublicationYear: "2021"}
+		book := &domain.Book{Title: "Test Book", Author: "Test Author", PublicationDate: "2021"}
 		err := repo.CreateBook(context.Background(), book)
 		assert.Nil(t, err)
 
@@ -138,8 +390,9 @@ func TestUpdateBook(t *testing.T) {
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				book.Title = "Updated Test Book"
-				err := repo.UpdateBook(context.Background(), book.ID.String(), book)
+				update := *book
+				update.Title = "Updated Test Book"
+				err := repo.UpdateBook(context.Background(), book.ID.String(), &update)
 				errs <- err
 			}()
 		}
@@ -180,7 +433,7 @@ func TestDeleteBook(t *testing.T) {
 		repo := NewInMemoryBookRepository()
 
 		// Try to delete a book with an ID that doesn't exist in the repository
-		err := repo.DeleteBook(context.Background(), "nonexistent-id")
+		err := repo.DeleteBook(context.Background(), "nonexistent-id", "alice")
 		assert.Error(t, err)
 	})
 
@@ -197,7 +450,7 @@ func TestDeleteBook(t *testing.T) {
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				err := repo.DeleteBook(context.Background(), book.ID.String())
+				err := repo.DeleteBook(context.Background(), book.ID.String(), "alice")
 				errs <- err
 			}()
 		}
@@ -222,7 +475,7 @@ func TestDeleteBook(t *testing.T) {
 		err := repo.CreateBook(context.Background(), book)
 		assert.Nil(t, err)
 
-		err = repo.DeleteBook(context.Background(), book.ID.String())
+		err = repo.DeleteBook(context.Background(), book.ID.String(), "alice")
 		assert.Nil(t, err)
 
 		deletedBook, err := repo.GetBookByID(context.Background(), book.ID.String())
@@ -230,3 +483,192 @@ func TestDeleteBook(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestBookHistory(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// newRepo returns a repository whose clock moves an hour per change,
+	// holding a book created, updated and deleted
+	newRepo := func(t *testing.T) (domain.BookRepository, string) {
+		repo := NewInMemoryBookRepository()
+		tick := start
+		repo.(*InMemoryBookRepository).now = func() time.Time {
+			tick = tick.Add(time.Hour)
+			return tick
+		}
+
+		book := &domain.Book{Title: "Go", Author: "Rob", PublicationDate: "2015"}
+		require.NoError(t, repo.CreateBook(ctx, book))
+		require.NoError(t, repo.UpdateBook(ctx, book.ID.String(), &domain.Book{Title: "Go 2", Author: "Rob", PublicationDate: "2015"}))
+		require.NoError(t, repo.DeleteBook(ctx, book.ID.String(), "alice"))
+
+		return repo, book.ID.String()
+	}
+
+	t.Run("FetchBookHistory", func(t *testing.T) {
+		repo, id := newRepo(t)
+
+		history, err := repo.FetchBookHistory(ctx, id)
+
+		assert.NoError(t, err)
+		assert.Len(t, *history, 3)
+		for i, action := range []domain.AuditAction{domain.AuditActionCreate, domain.AuditActionUpdate, domain.AuditActionDelete} {
+			assert.Equal(t, i+1, (*history)[i].Revision)
+			assert.Equal(t, action, (*history)[i].Action)
+			assert.Equal(t, start.Add(time.Duration(i+1)*time.Hour), (*history)[i].Time)
+		}
+		assert.Equal(t, "Go 2", (*history)[2].Book.Title)
+
+		_, err = repo.FetchBookHistory(ctx, uuid.New().String())
+		assert.Error(t, err)
+	})
+
+	t.Run("GetBookAsOf", func(t *testing.T) {
+		repo, id := newRepo(t)
+
+		tests := []struct {
+			name  string
+			at    time.Time
+			title string // empty when not found
+		}{
+			{name: "Before it was created", at: start},
+			{name: "Created", at: start.Add(time.Hour), title: "Go"},
+			{name: "Updated", at: start.Add(150 * time.Minute), title: "Go 2"},
+			{name: "Deleted", at: start.Add(3 * time.Hour)},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				book, err := repo.GetBookAsOf(ctx, id, tt.at)
+				if tt.title == "" {
+					assert.Error(t, err)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, tt.title, book.Title)
+			})
+		}
+	})
+
+	t.Run("RevertBook - Restores a deleted book", func(t *testing.T) {
+		repo, id := newRepo(t)
+
+		book, err := repo.RevertBook(ctx, id, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Go", book.Title)
+		current, err := repo.GetBookByID(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, book, current)
+
+		history, _ := repo.FetchBookHistory(ctx, id)
+		assert.Len(t, *history, 4)
+		assert.Equal(t, domain.AuditActionRevert, (*history)[3].Action)
+	})
+
+	t.Run("Revisions keep their lists", func(t *testing.T) {
+		repo := NewInMemoryBookRepository()
+		book := &domain.Book{Title: "Go", Author: "Rob", PublicationDate: "2015", Genres: []string{"Programming"}, Subjects: []string{"Go"}, Series: []domain.SeriesEntry{{SeriesID: uuid.New(), Position: 1}}}
+		require.NoError(t, repo.CreateBook(ctx, book))
+
+		// changed in place by the caller, and through the reads
+		book.Subjects[0] = "Rust"
+		book.Series[0].Position = 2
+		fetched, _ := repo.GetBookByID(ctx, book.ID.String())
+		fetched.Genres[0] = "Fiction"
+		history, _ := repo.FetchBookHistory(ctx, book.ID.String())
+		(*history)[0].Book.Genres[0] = "Poetry"
+
+		history, _ = repo.FetchBookHistory(ctx, book.ID.String())
+		assert.Equal(t, []string{"Programming"}, (*history)[0].Book.Genres)
+		assert.Equal(t, []string{"Go"}, (*history)[0].Book.Subjects)
+		assert.Equal(t, float64(1), (*history)[0].Book.Series[0].Position)
+	})
+
+	t.Run("RevertBook - Failure", func(t *testing.T) {
+		repo, id := newRepo(t)
+		require.NoError(t, repo.CreateBook(ctx, &domain.Book{Title: "Go", Author: "Rob", PublicationDate: "2015"}))
+
+		_, err := repo.RevertBook(ctx, id, 1)
+		assert.Equal(t, http.StatusConflict, apperror.Status(err))
+
+		_, err = repo.RevertBook(ctx, id, 3)
+		assert.Equal(t, http.StatusBadRequest, apperror.Status(err))
+
+		_, err = repo.RevertBook(ctx, id, 4)
+		assert.Equal(t, http.StatusNotFound, apperror.Status(err))
+	})
+}
+
+func TestTrash(t *testing.T) {
+	ctx := context.Background()
+	deletedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// newRepo returns a repository holding a book deleted by alice
+	newRepo := func(t *testing.T) (domain.BookRepository, *domain.Book) {
+		repo := NewInMemoryBookRepository()
+		repo.(*InMemoryBookRepository).now = func() time.Time { return deletedAt }
+
+		book := &domain.Book{Title: "Go", Author: "Rob", PublicationDate: "2015"}
+		require.NoError(t, repo.CreateBook(ctx, book))
+		require.NoError(t, repo.DeleteBook(ctx, book.ID.String(), "alice"))
+
+		return repo, book
+	}
+
+	t.Run("Deleted books are hidden and trashed", func(t *testing.T) {
+		repo, book := newRepo(t)
+
+		_, err := repo.GetBookByID(ctx, book.ID.String())
+		assert.Equal(t, http.StatusNotFound, apperror.Status(err))
+		_, err = repo.FetchBooks(ctx, domain.BookFilter{})
+		assert.Equal(t, http.StatusNotFound, apperror.Status(err))
+
+		trash, err := repo.FetchTrash(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.TrashedBook{{Book: *book, DeletedAt: deletedAt, DeletedBy: "alice"}}, *trash)
+	})
+
+	t.Run("RestoreBook", func(t *testing.T) {
+		repo, book := newRepo(t)
+
+		restored, err := repo.RestoreBook(ctx, book.ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, book, restored)
+
+		_, err = repo.GetBookByID(ctx, book.ID.String())
+		assert.NoError(t, err)
+		trash, _ := repo.FetchTrash(ctx)
+		assert.Empty(t, *trash)
+
+		_, err = repo.RestoreBook(ctx, book.ID.String())
+		assert.Equal(t, http.StatusNotFound, apperror.Status(err))
+	})
+
+	t.Run("Trashed books are not duplicates until restored", func(t *testing.T) {
+		repo, book := newRepo(t)
+
+		require.NoError(t, repo.CreateBook(ctx, &domain.Book{Title: "Go", Author: "Rob", PublicationDate: "2015"}))
+
+		_, err := repo.RestoreBook(ctx, book.ID.String())
+		assert.Equal(t, http.StatusConflict, apperror.Status(err))
+	})
+
+	t.Run("PurgeTrash", func(t *testing.T) {
+		repo, book := newRepo(t)
+
+		purged, err := repo.PurgeTrash(ctx, deletedAt)
+		assert.NoError(t, err)
+		assert.Empty(t, purged)
+
+		purged, err = repo.PurgeTrash(ctx, deletedAt.Add(time.Second))
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{book.ID}, purged)
+
+		trash, _ := repo.FetchTrash(ctx)
+		assert.Empty(t, *trash)
+		_, err = repo.FetchBookHistory(ctx, book.ID.String())
+		assert.Equal(t, http.StatusNotFound, apperror.Status(err))
+	})
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+type InMemoryCopyRepository struct {
+	copies []domain.Copy
+	counts map[uuid.UUID]domain.CopyCounts // by book, kept along with copies
+	mu     sync.Mutex
+}
+
+func NewInMemoryCopyRepository() domain.CopyRepository {
+	return &InMemoryCopyRepository{
+		copies: []domain.Copy{},
+		counts: map[uuid.UUID]domain.CopyCounts{},
+	}
+}
+
+func (r *InMemoryCopyRepository) FetchCopies(ctx context.Context, filter domain.CopyFilter) (*[]domain.Copy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copies := []domain.Copy{}
+	for i := range r.copies {
+		if filter.Match(&r.copies[i]) {
+			copies = append(copies, r.copies[i])
+		}
+	}
+	sort.SliceStable(copies, func(i, j int) bool {
+		return copies[i].Barcode < copies[j].Barcode
+	})
+
+	return &copies, nil
+}
+
+func (r *InMemoryCopyRepository) GetCopyByID(ctx context.Context, id string) (*domain.Copy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.copies {
+		if c.ID.String() == id {
+			return &c, nil
+		}
+	}
+
+	return nil, apperror.NewNotFound("Copy", "ID", id)
+}
+
+// GetCopyByBarcode looks a copy up by its barcode, ignoring case
+func (r *InMemoryCopyRepository) GetCopyByBarcode(ctx context.Context, barcode string) (*domain.Copy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.copies {
+		if strings.EqualFold(c.Barcode, barcode) {
+			return &c, nil
+		}
+	}
+
+	return nil, apperror.NewNotFound("Copy", "barcode", barcode)
+}
+
+func (r *InMemoryCopyRepository) CreateCopy(ctx context.Context, cp *domain.Copy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkDuplicate(cp, uuid.Nil); err != nil {
+		return err
+	}
+
+	cp.ID = uuid.New()
+	r.copies = append(r.copies, *cp)
+	r.count(cp, (*domain.CopyCounts).Add)
+
+	return nil
+}
+
+func (r *InMemoryCopyRepository) UpdateCopy(ctx context.Context, id string, cp *domain.Copy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, c := range r.copies {
+		if c.ID.String() == id {
+			if err := r.checkDuplicate(cp, c.ID); err != nil {
+				return err
+			}
+			cp.ID = c.ID
+			r.count(&c, (*domain.CopyCounts).Remove)
+			r.copies[i] = *cp
+			r.count(cp, (*domain.CopyCounts).Add)
+			return nil
+		}
+	}
+
+	return apperror.NewNotFound("Copy", "ID", id)
+}
+
+func (r *InMemoryCopyRepository) DeleteCopy(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, c := range r.copies {
+		if c.ID.String() == id {
+			r.copies = append(r.copies[:i], r.copies[i+1:]...)
+			r.count(&c, (*domain.CopyCounts).Remove)
+			return nil
+		}
+	}
+
+	return apperror.NewNotFound("Copy", "ID", id)
+}
+
+func (r *InMemoryCopyRepository) CountCopies(ctx context.Context, bookIDs []uuid.UUID) (map[uuid.UUID]domain.CopyCounts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := make(map[uuid.UUID]domain.CopyCounts, len(bookIDs))
+	for _, id := range bookIDs {
+		if n, ok := r.counts[id]; ok {
+			counts[id] = n
+		}
+	}
+
+	return counts, nil
+}
+
+func (r *InMemoryCopyRepository) DeleteBookCopies(ctx context.Context, bookIDs []uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	books := make(map[uuid.UUID]bool, len(bookIDs))
+	for _, id := range bookIDs {
+		books[id] = true
+		delete(r.counts, id)
+	}
+	kept := r.copies[:0]
+	for _, c := range r.copies {
+		if !books[c.BookID] {
+			kept = append(kept, c)
+		}
+	}
+	deleted := len(r.copies) - len(kept)
+	r.copies = kept
+
+	return deleted, nil
+}
+
+// count applies update to the counts of the book of c, r.mu must be held
+func (r *InMemoryCopyRepository) count(c *domain.Copy, update func(*domain.CopyCounts, *domain.Copy)) {
+	n := r.counts[c.BookID]
+	update(&n, c)
+	if n == (domain.CopyCounts{}) {
+		delete(r.counts, c.BookID)
+		return
+	}
+	r.counts[c.BookID] = n
+}
+
+// checkDuplicate rejects cp when a copy other than except has the same
+// barcode, ignoring case, r.mu must be held
+func (r *InMemoryCopyRepository) checkDuplicate(cp *domain.Copy, except uuid.UUID) error {
+	for _, c := range r.copies {
+		if c.ID != except && strings.EqualFold(c.Barcode, cp.Barcode) {
+			return apperror.NewConflictCode("duplicate_barcode", apperror.Params{"barcode": strconv.Quote(c.Barcode)})
+		}
+	}
+	return nil
+}
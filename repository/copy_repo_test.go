@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryCopyRepository(t *testing.T) {
+	ctx := context.Background()
+	book := uuid.New()
+
+	t.Run("Create, fetch and get", func(t *testing.T) {
+		repo := NewInMemoryCopyRepository()
+		second := &domain.Copy{BookID: book, Barcode: "B002", Status: domain.CopyOnLoan}
+		first := &domain.Copy{BookID: book, Barcode: "B001", Status: domain.CopyAvailable}
+		other := &domain.Copy{BookID: uuid.New(), Barcode: "B003", Status: domain.CopyAvailable}
+		for _, c := range []*domain.Copy{second, first, other} {
+			assert.Nil(t, repo.CreateCopy(ctx, c))
+		}
+		assert.NotEqual(t, uuid.Nil, first.ID)
+
+		copies, err := repo.FetchCopies(ctx, domain.CopyFilter{BookID: book})
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Copy{*first, *second}, *copies)
+
+		copies, err = repo.FetchCopies(ctx, domain.CopyFilter{Status: domain.CopyAvailable})
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Copy{*first, *other}, *copies)
+
+		found, err := repo.GetCopyByID(ctx, second.ID.String())
+		assert.Nil(t, err)
+		assert.Equal(t, second, found)
+
+		found, err = repo.GetCopyByBarcode(ctx, "b003")
+		assert.Nil(t, err)
+		assert.Equal(t, other, found)
+	})
+
+	t.Run("Same barcode in another case", func(t *testing.T) {
+		repo := NewInMemoryCopyRepository()
+		first := &domain.Copy{BookID: book, Barcode: "B001"}
+		second := &domain.Copy{BookID: book, Barcode: "B002"}
+		repo.CreateCopy(ctx, first)
+		repo.CreateCopy(ctx, second)
+
+		err := repo.CreateCopy(ctx, &domain.Copy{BookID: uuid.New(), Barcode: "b001"})
+		assert.ErrorIs(t, err, &apperror.Error{Type: apperror.Conflict, Code: "duplicate_barcode"})
+
+		err = repo.UpdateCopy(ctx, second.ID.String(), &domain.Copy{BookID: book, Barcode: "B001"})
+		assert.ErrorIs(t, err, &apperror.Error{Type: apperror.Conflict, Code: "duplicate_barcode"})
+
+		assert.Nil(t, repo.UpdateCopy(ctx, first.ID.String(), &domain.Copy{BookID: book, Barcode: "B001", Status: domain.CopyRepair}))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := NewInMemoryCopyRepository()
+		cp := &domain.Copy{BookID: book, Barcode: "B001"}
+		repo.CreateCopy(ctx, cp)
+
+		assert.Nil(t, repo.DeleteCopy(ctx, cp.ID.String()))
+
+		_, err := repo.GetCopyByID(ctx, cp.ID.String())
+		assert.Equal(t, apperror.NotFound, err.(*apperror.Error).Type)
+		_, err = repo.GetCopyByBarcode(ctx, "B001")
+		assert.Equal(t, apperror.NotFound, err.(*apperror.Error).Type)
+		err = repo.DeleteCopy(ctx, cp.ID.String())
+		assert.Equal(t, apperror.NotFound, err.(*apperror.Error).Type)
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		repo := NewInMemoryCopyRepository()
+		other, lost := uuid.New(), uuid.New()
+		first := &domain.Copy{BookID: book, Barcode: "B001", Status: domain.CopyAvailable}
+		second := &domain.Copy{BookID: book, Barcode: "B002", Status: domain.CopyAvailable}
+		for _, c := range []*domain.Copy{first, second, {BookID: other, Barcode: "B003", Status: domain.CopyOnLoan}, {BookID: lost, Barcode: "B004", Status: domain.CopyLost}} {
+			assert.Nil(t, repo.CreateCopy(ctx, c))
+		}
+		assert.Nil(t, repo.UpdateCopy(ctx, first.ID.String(), &domain.Copy{BookID: book, Barcode: "B001", Status: domain.CopyOnLoan}))
+		assert.Nil(t, repo.DeleteCopy(ctx, second.ID.String()))
+
+		counts, err := repo.CountCopies(ctx, []uuid.UUID{book, lost, uuid.New()})
+		assert.Nil(t, err)
+		// books without copies, lost ones aside, are left out
+		assert.Equal(t, map[uuid.UUID]domain.CopyCounts{book: {Total: 1}}, counts)
+	})
+
+	t.Run("Delete the copies of books", func(t *testing.T) {
+		repo := NewInMemoryCopyRepository()
+		other := uuid.New()
+		for _, c := range []*domain.Copy{{BookID: book, Barcode: "B001", Status: domain.CopyLost}, {BookID: other, Barcode: "B002"}, {BookID: book, Barcode: "B003"}} {
+			assert.Nil(t, repo.CreateCopy(ctx, c))
+		}
+
+		deleted, err := repo.DeleteBookCopies(ctx, []uuid.UUID{book})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, deleted)
+
+		copies, _ := repo.FetchCopies(ctx, domain.CopyFilter{})
+		assert.Len(t, *copies, 1)
+		assert.Equal(t, other, (*copies)[0].BookID)
+		counts, _ := repo.CountCopies(ctx, []uuid.UUID{book})
+		assert.Empty(t, counts)
+	})
+}
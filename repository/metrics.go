@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metricsBookRepository times every call to the wrapped repository
+type metricsBookRepository struct {
+	repo     domain.BookRepository
+	duration *prometheus.HistogramVec
+}
+
+// NewMetricsBookRepository reports the latency of each operation of repo
+// and the size of the catalog to reg
+func NewMetricsBookRepository(repo domain.BookRepository, reg prometheus.Registerer) domain.BookRepository {
+	r := &metricsBookRepository{
+		repo: repo,
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "book_repository_operation_duration_seconds",
+			Help:    "Latency of the book repository operations, by operation and outcome.",
+			Buckets: []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1},
+		}, []string{"operation", "outcome"}),
+	}
+
+	catalogSize := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "book_catalog_size",
+		Help: "Books in the catalog.",
+	}, func() float64 {
+		books, err := repo.FetchBooks(context.Background(), domain.BookFilter{})
+		if err != nil || books == nil {
+			return 0
+		}
+		return float64(len(*books))
+	})
+
+	reg.MustRegister(r.duration, catalogSize)
+
+	return r
+}
+
+func (r *metricsBookRepository) observe(operation string, start time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	r.duration.WithLabelValues(operation, outcome).Observe(time.Since(start).Seconds())
+}
+
+func (r *metricsBookRepository) FetchBooks(ctx context.Context, filter domain.BookFilter) (*[]domain.Book, error) {
+	start := time.Now()
+	books, err := r.repo.FetchBooks(ctx, filter)
+	r.observe("FetchBooks", start, err)
+
+	return books, err
+}
+
+func (r *metricsBookRepository) GetBookByID(ctx context.Context, id string) (*domain.Book, error) {
+	start := time.Now()
+	book, err := r.repo.GetBookByID(ctx, id)
+	r.observe("GetBookByID", start, err)
+
+	return book, err
+}
+
+func (r *metricsBookRepository) GetBookByISBN(ctx context.Context, isbn string) (*domain.Book, error) {
+	start := time.Now()
+	book, err := r.repo.GetBookByISBN(ctx, isbn)
+	r.observe("GetBookByISBN", start, err)
+
+	return book, err
+}
+
+func (r *metricsBookRepository) GetSeriesNeighbours(ctx context.Context, id string) ([]domain.SeriesEntry, error) {
+	start := time.Now()
+	entries, err := r.repo.GetSeriesNeighbours(ctx, id)
+	r.observe("GetSeriesNeighbours", start, err)
+
+	return entries, err
+}
+
+func (r *metricsBookRepository) CreateBook(ctx context.Context, book *domain.Book) error {
+	start := time.Now()
+	err := r.repo.CreateBook(ctx, book)
+	r.observe("CreateBook", start, err)
+
+	return err
+}
+
+func (r *metricsBookRepository) UpdateBook(ctx context.Context, id string, book *domain.Book) error {
+	start := time.Now()
+	err := r.repo.UpdateBook(ctx, id, book)
+	r.observe("UpdateBook", start, err)
+
+	return err
+}
+
+func (r *metricsBookRepository) DeleteBook(ctx context.Context, id string, deletedBy string) error {
+	start := time.Now()
+	err := r.repo.DeleteBook(ctx, id, deletedBy)
+	r.observe("DeleteBook", start, err)
+
+	return err
+}
+
+func (r *metricsBookRepository) FetchBookHistory(ctx context.Context, id string) (*[]domain.BookRevision, error) {
+	start := time.Now()
+	history, err := r.repo.FetchBookHistory(ctx, id)
+	r.observe("FetchBookHistory", start, err)
+
+	return history, err
+}
+
+func (r *metricsBookRepository) GetBookAsOf(ctx context.Context, id string, at time.Time) (*domain.Book, error) {
+	start := time.Now()
+	book, err := r.repo.GetBookAsOf(ctx, id, at)
+	r.observe("GetBookAsOf", start, err)
+
+	return book, err
+}
+
+func (r *metricsBookRepository) RevertBook(ctx context.Context, id string, revision int) (*domain.Book, error) {
+	start := time.Now()
+	book, err := r.repo.RevertBook(ctx, id, revision)
+	r.observe("RevertBook", start, err)
+
+	return book, err
+}
+
+func (r *metricsBookRepository) FetchTrash(ctx context.Context) (*[]domain.TrashedBook, error) {
+	start := time.Now()
+	trash, err := r.repo.FetchTrash(ctx)
+	r.observe("FetchTrash", start, err)
+
+	return trash, err
+}
+
+func (r *metricsBookRepository) RestoreBook(ctx context.Context, id string) (*domain.Book, error) {
+	start := time.Now()
+	book, err := r.repo.RestoreBook(ctx, id)
+	r.observe("RestoreBook", start, err)
+
+	return book, err
+}
+
+func (r *metricsBookRepository) PurgeTrash(ctx context.Context, deletedBefore time.Time) ([]uuid.UUID, error) {
+	start := time.Now()
+	purged, err := r.repo.PurgeTrash(ctx, deletedBefore)
+	r.observe("PurgeTrash", start, err)
+
+	return purged, err
+}
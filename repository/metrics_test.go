@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsBookRepository(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	repo := NewMetricsBookRepository(NewInMemoryBookRepository(), reg)
+	ctx := context.Background()
+
+	assert.Nil(t, repo.CreateBook(ctx, &domain.Book{Title: "Test Book", Author: "Test Author", PublicationDate: "2021"}))
+	assert.NotNil(t, repo.CreateBook(ctx, &domain.Book{Title: "Test Book", Author: "Test Author", PublicationDate: "2021"}))
+	repo.GetBookByID(ctx, "missing")
+
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP book_catalog_size Books in the catalog.
+# TYPE book_catalog_size gauge
+book_catalog_size 1
+`), "book_catalog_size"))
+
+	count, err := testutil.GatherAndCount(reg, "book_repository_operation_duration_seconds")
+	assert.NoError(t, err)
+	// CreateBook success and error, GetBookByID error
+	assert.Equal(t, 3, count)
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+type InMemoryPublisherRepository struct {
+	publishers []domain.Publisher
+	mu         sync.Mutex
+}
+
+func NewInMemoryPublisherRepository() domain.PublisherRepository {
+	return &InMemoryPublisherRepository{
+		publishers: []domain.Publisher{},
+	}
+}
+
+func (r *InMemoryPublisherRepository) FetchPublishers(ctx context.Context, filter domain.PublisherFilter) (*[]domain.Publisher, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	publishers := []domain.Publisher{}
+	for i := range r.publishers {
+		if filter.Match(&r.publishers[i]) {
+			publishers = append(publishers, r.publishers[i])
+		}
+	}
+	sort.SliceStable(publishers, func(i, j int) bool {
+		return publishers[i].Name < publishers[j].Name
+	})
+
+	return &publishers, nil
+}
+
+func (r *InMemoryPublisherRepository) GetPublisherByID(ctx context.Context, id string) (*domain.Publisher, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, publisher := range r.publishers {
+		if publisher.ID.String() == id {
+			return &publisher, nil
+		}
+	}
+
+	return nil, apperror.NewNotFound("Publisher", "ID", id)
+}
+
+func (r *InMemoryPublisherRepository) CreatePublisher(ctx context.Context, publisher *domain.Publisher) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkDuplicate(publisher, uuid.Nil); err != nil {
+		return err
+	}
+
+	publisher.ID = uuid.New()
+	r.publishers = append(r.publishers, *publisher)
+
+	return nil
+}
+
+func (r *InMemoryPublisherRepository) UpdatePublisher(ctx context.Context, id string, publisher *domain.Publisher) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, p := range r.publishers {
+		if p.ID.String() == id {
+			if err := r.checkDuplicate(publisher, p.ID); err != nil {
+				return err
+			}
+			publisher.ID = p.ID
+			r.publishers[i] = *publisher
+			return nil
+		}
+	}
+
+	return apperror.NewNotFound("Publisher", "ID", id)
+}
+
+func (r *InMemoryPublisherRepository) DeletePublisher(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, publisher := range r.publishers {
+		if publisher.ID.String() == id {
+			r.publishers = append(r.publishers[:i], r.publishers[i+1:]...)
+			return nil
+		}
+	}
+
+	return apperror.NewNotFound("Publisher", "ID", id)
+}
+
+// checkDuplicate rejects publisher when a publisher other than except has
+// the same name, ignoring case, r.mu must be held
+func (r *InMemoryPublisherRepository) checkDuplicate(publisher *domain.Publisher, except uuid.UUID) error {
+	name := strings.TrimSpace(publisher.Name)
+	for _, p := range r.publishers {
+		if p.ID != except && strings.EqualFold(strings.TrimSpace(p.Name), name) {
+			return apperror.NewConflict("publisher", p.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryPublisherRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create, fetch and get", func(t *testing.T) {
+		repo := NewInMemoryPublisherRepository()
+		penguin := &domain.Publisher{Name: "Penguin Books"}
+		assert.Nil(t, repo.CreatePublisher(ctx, penguin))
+		assert.NotEqual(t, uuid.Nil, penguin.ID)
+		puffin := &domain.Publisher{Name: "Puffin", ParentID: &penguin.ID}
+		assert.Nil(t, repo.CreatePublisher(ctx, puffin))
+
+		publishers, err := repo.FetchPublishers(ctx, domain.PublisherFilter{})
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Publisher{*penguin, *puffin}, *publishers)
+
+		publishers, err = repo.FetchPublishers(ctx, domain.PublisherFilter{ParentID: penguin.ID})
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Publisher{*puffin}, *publishers)
+
+		publishers, err = repo.FetchPublishers(ctx, domain.PublisherFilter{Name: "PENGUIN"})
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Publisher{*penguin}, *publishers)
+
+		found, err := repo.GetPublisherByID(ctx, puffin.ID.String())
+		assert.Nil(t, err)
+		assert.Equal(t, puffin, found)
+	})
+
+	t.Run("Same name in another case", func(t *testing.T) {
+		repo := NewInMemoryPublisherRepository()
+		repo.CreatePublisher(ctx, &domain.Publisher{Name: "Penguin Books"})
+
+		err := repo.CreatePublisher(ctx, &domain.Publisher{Name: "penguin books "})
+		assert.Equal(t, apperror.Conflict, err.(*apperror.Error).Type)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := NewInMemoryPublisherRepository()
+		penguin := &domain.Publisher{Name: "Penguin Books"}
+		vintage := &domain.Publisher{Name: "Vintage"}
+		repo.CreatePublisher(ctx, penguin)
+		repo.CreatePublisher(ctx, vintage)
+
+		err := repo.UpdatePublisher(ctx, penguin.ID.String(), &domain.Publisher{Name: "PENGUIN BOOKS"})
+		assert.Nil(t, err)
+
+		err = repo.UpdatePublisher(ctx, vintage.ID.String(), &domain.Publisher{Name: "Penguin Books"})
+		assert.Equal(t, apperror.Conflict, err.(*apperror.Error).Type)
+
+		err = repo.UpdatePublisher(ctx, uuid.New().String(), &domain.Publisher{Name: "Nobody"})
+		assert.Equal(t, apperror.NotFound, err.(*apperror.Error).Type)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := NewInMemoryPublisherRepository()
+		publisher := &domain.Publisher{Name: "Penguin Books"}
+		repo.CreatePublisher(ctx, publisher)
+
+		assert.Nil(t, repo.DeletePublisher(ctx, publisher.ID.String()))
+
+		_, err := repo.GetPublisherByID(ctx, publisher.ID.String())
+		assert.Equal(t, apperror.NotFound, err.(*apperror.Error).Type)
+	})
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+type InMemorySeriesRepository struct {
+	series []domain.Series
+	mu     sync.Mutex
+}
+
+func NewInMemorySeriesRepository() domain.SeriesRepository {
+	return &InMemorySeriesRepository{
+		series: []domain.Series{},
+	}
+}
+
+func (r *InMemorySeriesRepository) FetchSeries(ctx context.Context, filter domain.SeriesFilter) (*[]domain.Series, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	series := []domain.Series{}
+	for i := range r.series {
+		if filter.Match(&r.series[i]) {
+			series = append(series, r.series[i])
+		}
+	}
+	sort.SliceStable(series, func(i, j int) bool {
+		return series[i].Name < series[j].Name
+	})
+
+	return &series, nil
+}
+
+func (r *InMemorySeriesRepository) GetSeriesByID(ctx context.Context, id string) (*domain.Series, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, series := range r.series {
+		if series.ID.String() == id {
+			return &series, nil
+		}
+	}
+
+	return nil, apperror.NewNotFound("Series", "ID", id)
+}
+
+func (r *InMemorySeriesRepository) CreateSeries(ctx context.Context, series *domain.Series) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkDuplicate(series, uuid.Nil); err != nil {
+		return err
+	}
+
+	series.ID = uuid.New()
+	r.series = append(r.series, *series)
+
+	return nil
+}
+
+func (r *InMemorySeriesRepository) UpdateSeries(ctx context.Context, id string, series *domain.Series) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, s := range r.series {
+		if s.ID.String() == id {
+			if err := r.checkDuplicate(series, s.ID); err != nil {
+				return err
+			}
+			series.ID = s.ID
+			r.series[i] = *series
+			return nil
+		}
+	}
+
+	return apperror.NewNotFound("Series", "ID", id)
+}
+
+func (r *InMemorySeriesRepository) DeleteSeries(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, series := range r.series {
+		if series.ID.String() == id {
+			r.series = append(r.series[:i], r.series[i+1:]...)
+			return nil
+		}
+	}
+
+	return apperror.NewNotFound("Series", "ID", id)
+}
+
+// checkDuplicate rejects series when a series other than except has
+// the same name, ignoring case, r.mu must be held
+func (r *InMemorySeriesRepository) checkDuplicate(series *domain.Series, except uuid.UUID) error {
+	name := strings.TrimSpace(series.Name)
+	for _, s := range r.series {
+		if s.ID != except && strings.EqualFold(strings.TrimSpace(s.Name), name) {
+			return apperror.NewConflict("series", s.Name)
+		}
+	}
+	return nil
+}
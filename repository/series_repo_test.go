@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemorySeriesRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create, fetch and get", func(t *testing.T) {
+		repo := NewInMemorySeriesRepository()
+		discworld := &domain.Series{Name: "Discworld"}
+		earthsea := &domain.Series{Name: "Earthsea"}
+		assert.Nil(t, repo.CreateSeries(ctx, earthsea))
+		assert.Nil(t, repo.CreateSeries(ctx, discworld))
+		assert.NotEqual(t, uuid.Nil, discworld.ID)
+
+		series, err := repo.FetchSeries(ctx, domain.SeriesFilter{})
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Series{*discworld, *earthsea}, *series)
+
+		series, err = repo.FetchSeries(ctx, domain.SeriesFilter{Name: "SEA"})
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Series{*earthsea}, *series)
+
+		found, err := repo.GetSeriesByID(ctx, discworld.ID.String())
+		assert.Nil(t, err)
+		assert.Equal(t, discworld, found)
+	})
+
+	t.Run("Same name in another case", func(t *testing.T) {
+		repo := NewInMemorySeriesRepository()
+		discworld := &domain.Series{Name: "Discworld"}
+		earthsea := &domain.Series{Name: "Earthsea"}
+		repo.CreateSeries(ctx, discworld)
+		repo.CreateSeries(ctx, earthsea)
+
+		err := repo.CreateSeries(ctx, &domain.Series{Name: "discworld"})
+		assert.Equal(t, apperror.Conflict, err.(*apperror.Error).Type)
+
+		err = repo.UpdateSeries(ctx, earthsea.ID.String(), &domain.Series{Name: "DISCWORLD"})
+		assert.Equal(t, apperror.Conflict, err.(*apperror.Error).Type)
+
+		assert.Nil(t, repo.UpdateSeries(ctx, discworld.ID.String(), &domain.Series{Name: "DiscWorld", Description: "Novels by Terry Pratchett"}))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := NewInMemorySeriesRepository()
+		series := &domain.Series{Name: "Discworld"}
+		repo.CreateSeries(ctx, series)
+
+		assert.Nil(t, repo.DeleteSeries(ctx, series.ID.String()))
+
+		_, err := repo.GetSeriesByID(ctx, series.ID.String())
+		assert.Equal(t, apperror.NotFound, err.(*apperror.Error).Type)
+		err = repo.DeleteSeries(ctx, series.ID.String())
+		assert.Equal(t, apperror.NotFound, err.(*apperror.Error).Type)
+	})
+}
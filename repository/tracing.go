@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apptrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingBookRepository traces every call to the wrapped repository
+type tracingBookRepository struct {
+	repo   domain.BookRepository
+	tracer trace.Tracer
+}
+
+// NewTracingBookRepository records a span of each operation of repo with
+// a tracer of tp
+func NewTracingBookRepository(repo domain.BookRepository, tp trace.TracerProvider) domain.BookRepository {
+	return &tracingBookRepository{
+		repo:   repo,
+		tracer: tp.Tracer(apptrace.InstrumentationName),
+	}
+}
+
+func (r *tracingBookRepository) start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return r.tracer.Start(ctx, "BookRepository."+operation, trace.WithAttributes(attrs...))
+}
+
+func (r *tracingBookRepository) FetchBooks(ctx context.Context, filter domain.BookFilter) (*[]domain.Book, error) {
+	ctx, span := r.start(ctx, "FetchBooks")
+	books, err := r.repo.FetchBooks(ctx, filter)
+	apptrace.End(span, err)
+
+	return books, err
+}
+
+func (r *tracingBookRepository) GetBookByID(ctx context.Context, id string) (*domain.Book, error) {
+	ctx, span := r.start(ctx, "GetBookByID", attribute.String("book.id", id))
+	book, err := r.repo.GetBookByID(ctx, id)
+	apptrace.End(span, err)
+
+	return book, err
+}
+
+func (r *tracingBookRepository) GetBookByISBN(ctx context.Context, isbn string) (*domain.Book, error) {
+	ctx, span := r.start(ctx, "GetBookByISBN", attribute.String("book.isbn", isbn))
+	book, err := r.repo.GetBookByISBN(ctx, isbn)
+	apptrace.End(span, err)
+
+	return book, err
+}
+
+func (r *tracingBookRepository) GetSeriesNeighbours(ctx context.Context, id string) ([]domain.SeriesEntry, error) {
+	ctx, span := r.start(ctx, "GetSeriesNeighbours", attribute.String("book.id", id))
+	entries, err := r.repo.GetSeriesNeighbours(ctx, id)
+	apptrace.End(span, err)
+
+	return entries, err
+}
+
+func (r *tracingBookRepository) CreateBook(ctx context.Context, book *domain.Book) error {
+	ctx, span := r.start(ctx, "CreateBook")
+	err := r.repo.CreateBook(ctx, book)
+	apptrace.End(span, err)
+
+	return err
+}
+
+func (r *tracingBookRepository) UpdateBook(ctx context.Context, id string, book *domain.Book) error {
+	ctx, span := r.start(ctx, "UpdateBook", attribute.String("book.id", id))
+	err := r.repo.UpdateBook(ctx, id, book)
+	apptrace.End(span, err)
+
+	return err
+}
+
+func (r *tracingBookRepository) DeleteBook(ctx context.Context, id string, deletedBy string) error {
+	ctx, span := r.start(ctx, "DeleteBook", attribute.String("book.id", id))
+	err := r.repo.DeleteBook(ctx, id, deletedBy)
+	apptrace.End(span, err)
+
+	return err
+}
+
+func (r *tracingBookRepository) FetchBookHistory(ctx context.Context, id string) (*[]domain.BookRevision, error) {
+	ctx, span := r.start(ctx, "FetchBookHistory", attribute.String("book.id", id))
+	history, err := r.repo.FetchBookHistory(ctx, id)
+	apptrace.End(span, err)
+
+	return history, err
+}
+
+func (r *tracingBookRepository) GetBookAsOf(ctx context.Context, id string, at time.Time) (*domain.Book, error) {
+	ctx, span := r.start(ctx, "GetBookAsOf", attribute.String("book.id", id), attribute.String("book.as_of", at.Format(time.RFC3339Nano)))
+	book, err := r.repo.GetBookAsOf(ctx, id, at)
+	apptrace.End(span, err)
+
+	return book, err
+}
+
+func (r *tracingBookRepository) RevertBook(ctx context.Context, id string, revision int) (*domain.Book, error) {
+	ctx, span := r.start(ctx, "RevertBook", attribute.String("book.id", id), attribute.Int("book.revision", revision))
+	book, err := r.repo.RevertBook(ctx, id, revision)
+	apptrace.End(span, err)
+
+	return book, err
+}
+
+func (r *tracingBookRepository) FetchTrash(ctx context.Context) (*[]domain.TrashedBook, error) {
+	ctx, span := r.start(ctx, "FetchTrash")
+	trash, err := r.repo.FetchTrash(ctx)
+	apptrace.End(span, err)
+
+	return trash, err
+}
+
+func (r *tracingBookRepository) RestoreBook(ctx context.Context, id string) (*domain.Book, error) {
+	ctx, span := r.start(ctx, "RestoreBook", attribute.String("book.id", id))
+	book, err := r.repo.RestoreBook(ctx, id)
+	apptrace.End(span, err)
+
+	return book, err
+}
+
+func (r *tracingBookRepository) PurgeTrash(ctx context.Context, deletedBefore time.Time) ([]uuid.UUID, error) {
+	ctx, span := r.start(ctx, "PurgeTrash")
+	purged, err := r.repo.PurgeTrash(ctx, deletedBefore)
+	span.SetAttributes(attribute.Int("book.purged", len(purged)))
+	apptrace.End(span, err)
+
+	return purged, err
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/apptrace"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingBookRepository(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	repo := NewTracingBookRepository(NewInMemoryBookRepository(), tp)
+
+	// the spans of the repository are children of the caller's span
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	assert.NoError(t, repo.CreateBook(ctx, &domain.Book{Title: "Test Book", Author: "Test Author", PublicationDate: "2021"}))
+	_, err := repo.GetBookByID(ctx, "missing")
+	assert.Error(t, err)
+	parent.End()
+
+	spans := sr.Ended()
+	assert.Len(t, spans, 3)
+
+	assert.Equal(t, "BookRepository.CreateBook", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	assert.Equal(t, "BookRepository.GetBookByID", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Contains(t, spans[1].Attributes(), apptrace.ErrorTypeKey.String(string(apperror.NotFound)))
+}
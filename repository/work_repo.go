@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+type InMemoryWorkRepository struct {
+	works []domain.Work
+	mu    sync.Mutex
+}
+
+func NewInMemoryWorkRepository() domain.WorkRepository {
+	return &InMemoryWorkRepository{
+		works: []domain.Work{},
+	}
+}
+
+func (r *InMemoryWorkRepository) FetchWorks(ctx context.Context, filter domain.WorkFilter) (*[]domain.Work, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	works := []domain.Work{}
+	for i := range r.works {
+		if filter.Match(&r.works[i]) {
+			works = append(works, r.works[i])
+		}
+	}
+	sort.SliceStable(works, func(i, j int) bool {
+		return works[i].Title < works[j].Title
+	})
+
+	return &works, nil
+}
+
+func (r *InMemoryWorkRepository) GetWorkByID(ctx context.Context, id string) (*domain.Work, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, work := range r.works {
+		if work.ID.String() == id {
+			return &work, nil
+		}
+	}
+
+	return nil, apperror.NewNotFound("Work", "ID", id)
+}
+
+func (r *InMemoryWorkRepository) CreateWork(ctx context.Context, work *domain.Work) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	work.ID = uuid.New()
+	r.works = append(r.works, *work)
+
+	return nil
+}
+
+func (r *InMemoryWorkRepository) UpdateWork(ctx context.Context, id string, work *domain.Work) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, w := range r.works {
+		if w.ID.String() == id {
+			work.ID = w.ID
+			r.works[i] = *work
+			return nil
+		}
+	}
+
+	return apperror.NewNotFound("Work", "ID", id)
+}
+
+func (r *InMemoryWorkRepository) DeleteWork(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, work := range r.works {
+		if work.ID.String() == id {
+			r.works = append(r.works[:i], r.works[i+1:]...)
+			return nil
+		}
+	}
+
+	return apperror.NewNotFound("Work", "ID", id)
+}
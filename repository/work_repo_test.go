@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryWorkRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create, fetch and get", func(t *testing.T) {
+		repo := NewInMemoryWorkRepository()
+		emma := &domain.Work{Title: "Emma"}
+		dune := &domain.Work{Title: "Dune"}
+		assert.Nil(t, repo.CreateWork(ctx, emma))
+		assert.Nil(t, repo.CreateWork(ctx, dune))
+		assert.NotEqual(t, uuid.Nil, dune.ID)
+
+		works, err := repo.FetchWorks(ctx, domain.WorkFilter{})
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Work{*dune, *emma}, *works)
+
+		works, err = repo.FetchWorks(ctx, domain.WorkFilter{Title: "UN"})
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Work{*dune}, *works)
+
+		found, err := repo.GetWorkByID(ctx, dune.ID.String())
+		assert.Nil(t, err)
+		assert.Equal(t, dune, found)
+	})
+
+	t.Run("Same title", func(t *testing.T) {
+		repo := NewInMemoryWorkRepository()
+		assert.Nil(t, repo.CreateWork(ctx, &domain.Work{Title: "Poems"}))
+		assert.Nil(t, repo.CreateWork(ctx, &domain.Work{Title: "Poems"}))
+	})
+
+	t.Run("Update and delete", func(t *testing.T) {
+		repo := NewInMemoryWorkRepository()
+		work := &domain.Work{Title: "Dune"}
+		repo.CreateWork(ctx, work)
+
+		assert.Nil(t, repo.UpdateWork(ctx, work.ID.String(), &domain.Work{Title: "Dune Messiah"}))
+		found, _ := repo.GetWorkByID(ctx, work.ID.String())
+		assert.Equal(t, "Dune Messiah", found.Title)
+
+		assert.Nil(t, repo.DeleteWork(ctx, work.ID.String()))
+
+		_, err := repo.GetWorkByID(ctx, work.ID.String())
+		assert.Equal(t, apperror.NotFound, err.(*apperror.Error).Type)
+		err = repo.UpdateWork(ctx, work.ID.String(), &domain.Work{Title: "Dune"})
+		assert.Equal(t, apperror.NotFound, err.(*apperror.Error).Type)
+		err = repo.DeleteWork(ctx, work.ID.String())
+		assert.Equal(t, apperror.NotFound, err.(*apperror.Error).Type)
+	})
+}
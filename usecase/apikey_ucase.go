@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+// apiKeyPrefix marks the keys issued by this service, eg. bk_3q2+7w...
+const apiKeyPrefix = "bk_"
+
+// apiKeyPrefixLen is how much of a key is kept to tell keys apart
+const apiKeyPrefixLen = len(apiKeyPrefix) + 6
+
+type apiKeyUseCase struct {
+	options
+	apiKeyRepository domain.APIKeyRepository
+}
+
+func NewAPIKeyUseCase(apiKeyRepository domain.APIKeyRepository, opts ...Option) domain.APIKeyUseCase {
+	return &apiKeyUseCase{
+		options:          newOptions(opts),
+		apiKeyRepository: apiKeyRepository,
+	}
+}
+
+func (a *apiKeyUseCase) FetchAPIKeys(ctx context.Context) (*[]domain.APIKey, error) {
+	if err := a.authorize(ctx, domain.PermissionAPIKeysManage); err != nil {
+		return nil, err
+	}
+
+	return a.apiKeyRepository.FetchAPIKeys(ctx)
+}
+
+func (a *apiKeyUseCase) IssueAPIKey(ctx context.Context, name string, scopes []string) (*domain.IssuedAPIKey, error) {
+	if err := a.authorize(ctx, domain.PermissionAPIKeysManage); err != nil {
+		return nil, err
+	}
+
+	if err := validScopes(scopes); err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, apperror.NewInternal().WithCause(fmt.Errorf("could not generate API key: %w", err))
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	apiKey := domain.APIKey{
+		Name:      name,
+		Prefix:    key[:apiKeyPrefixLen],
+		Hash:      hashAPIKey(key),
+		Scopes:    scopes,
+		CreatedAt: a.now().UTC(),
+	}
+	if err := a.apiKeyRepository.CreateAPIKey(ctx, &apiKey); err != nil {
+		return nil, err
+	}
+
+	return &domain.IssuedAPIKey{APIKey: apiKey, Key: key}, nil
+}
+
+func (a *apiKeyUseCase) RevokeAPIKey(ctx context.Context, id string) error {
+	if err := a.authorize(ctx, domain.PermissionAPIKeysManage); err != nil {
+		return err
+	}
+
+	return a.apiKeyRepository.RevokeAPIKey(ctx, id, a.now().UTC())
+}
+
+// AuthenticateAPIKey returns the unrevoked APIKey matching key and
+// records its use
+func (a *apiKeyUseCase) AuthenticateAPIKey(ctx context.Context, key string) (*domain.APIKey, error) {
+	apiKey, err := a.apiKeyRepository.GetAPIKeyByHash(ctx, hashAPIKey(key))
+	if errors.Is(err, &apperror.Error{Type: apperror.NotFound}) {
+		return nil, apperror.NewAuthorization("invalid API key")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if apiKey.RevokedAt != nil {
+		return nil, apperror.NewAuthorization("invalid API key")
+	}
+
+	// a failed bookkeeping write should not fail the request
+	now := a.now().UTC()
+	if err := a.apiKeyRepository.TouchAPIKey(ctx, apiKey.ID.String(), now); err != nil {
+		slog.WarnContext(ctx, "could not record the use of an API key", "api_key_id", apiKey.ID.String(), "error", err)
+	} else {
+		apiKey.LastUsedAt = &now
+	}
+
+	return apiKey, nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func validScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return apperror.NewBadRequest("an API key needs at least one scope")
+	}
+
+	for _, s := range scopes {
+		known := false
+		for _, k := range domain.APIKeyScopes {
+			known = known || s == k
+		}
+		if !known {
+			return apperror.NewBadRequest(fmt.Sprintf("unknown scope %q", s))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIssueAPIKey(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(appmock.MockAPIKeyRepository)
+		mockRepo.On("CreateAPIKey", mock.Anything, mock.AnythingOfType("*domain.APIKey")).Return(nil).Once()
+
+		u := NewAPIKeyUseCase(mockRepo)
+
+		issued, err := u.IssueAPIKey(context.Background(), "import", []string{domain.ScopeBooksRead})
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(issued.Key, apiKeyPrefix))
+		assert.True(t, strings.HasPrefix(issued.Key, issued.Prefix))
+		// only the hash is stored
+		stored := mockRepo.Calls[0].Arguments.Get(1).(*domain.APIKey)
+		assert.Equal(t, hashAPIKey(issued.Key), stored.Hash)
+		assert.NotContains(t, stored.Hash, issued.Key)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown scope", func(t *testing.T) {
+		mockRepo := new(appmock.MockAPIKeyRepository)
+
+		u := NewAPIKeyUseCase(mockRepo)
+
+		_, err := u.IssueAPIKey(context.Background(), "import", []string{"books:everything"})
+
+		assert.Equal(t, http.StatusBadRequest, apperror.Status(err))
+		mockRepo.AssertNotCalled(t, "CreateAPIKey")
+	})
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	key := "bk_secret"
+	id := uuid.New()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(appmock.MockAPIKeyRepository)
+		mockRepo.On("GetAPIKeyByHash", mock.Anything, hashAPIKey(key)).Return(&domain.APIKey{ID: id}, nil).Once()
+		mockRepo.On("TouchAPIKey", mock.Anything, id.String(), now).Return(nil).Once()
+
+		u := NewAPIKeyUseCase(mockRepo)
+		u.(*apiKeyUseCase).now = func() time.Time { return now }
+
+		apiKey, err := u.AuthenticateAPIKey(context.Background(), key)
+
+		assert.NoError(t, err)
+		assert.Equal(t, id, apiKey.ID)
+		assert.Equal(t, &now, apiKey.LastUsedAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown key", func(t *testing.T) {
+		mockRepo := new(appmock.MockAPIKeyRepository)
+		mockRepo.On("GetAPIKeyByHash", mock.Anything, hashAPIKey(key)).Return(&domain.APIKey{}, apperror.NewNotFound("APIKey", "hash", "")).Once()
+
+		u := NewAPIKeyUseCase(mockRepo)
+
+		_, err := u.AuthenticateAPIKey(context.Background(), key)
+
+		assert.Equal(t, http.StatusUnauthorized, apperror.Status(err))
+	})
+
+	t.Run("Revoked key", func(t *testing.T) {
+		revokedAt := time.Now()
+		mockRepo := new(appmock.MockAPIKeyRepository)
+		mockRepo.On("GetAPIKeyByHash", mock.Anything, hashAPIKey(key)).Return(&domain.APIKey{ID: id, RevokedAt: &revokedAt}, nil).Once()
+
+		u := NewAPIKeyUseCase(mockRepo)
+
+		_, err := u.AuthenticateAPIKey(context.Background(), key)
+
+		assert.Equal(t, http.StatusUnauthorized, apperror.Status(err))
+		mockRepo.AssertNotCalled(t, "TouchAPIKey")
+	})
+}
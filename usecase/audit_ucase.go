@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/applog"
+)
+
+type auditUseCase struct {
+	options
+	auditRepository domain.AuditRepository
+}
+
+func NewAuditUseCase(auditRepository domain.AuditRepository, opts ...Option) domain.AuditUseCase {
+	return &auditUseCase{
+		options:         newOptions(opts),
+		auditRepository: auditRepository,
+	}
+}
+
+func (a *auditUseCase) FetchAuditEntries(ctx context.Context, filter domain.AuditFilter) (*[]domain.AuditEntry, error) {
+	if err := a.authorize(ctx, domain.PermissionAuditRead); err != nil {
+		return nil, err
+	}
+
+	return a.auditRepository.FetchAuditEntries(ctx, filter)
+}
+
+// auditedBookUseCase records an audit entry of every change made through
+// the wrapped use case
+type auditedBookUseCase struct {
+	domain.BookUseCase
+	bookRepository  domain.BookRepository
+	auditRepository domain.AuditRepository
+	now             func() time.Time
+}
+
+// NewAuditedBookUseCase records who created, updated, deleted, reverted
+// or restored which book through bu, and the fields changed, to
+// auditRepository. The state before a change is read from
+// bookRepository. Entries are appended after the change succeeded; an
+// entry which cannot be appended is logged as an error, the change is
+// not undone
+func NewAuditedBookUseCase(bu domain.BookUseCase, bookRepository domain.BookRepository, auditRepository domain.AuditRepository) domain.BookUseCase {
+	return &auditedBookUseCase{
+		BookUseCase:     bu,
+		bookRepository:  bookRepository,
+		auditRepository: auditRepository,
+		now:             time.Now,
+	}
+}
+
+func (a *auditedBookUseCase) CreateBook(ctx context.Context, book *domain.Book) error {
+	if err := a.BookUseCase.CreateBook(ctx, book); err != nil {
+		return err
+	}
+
+	a.record(ctx, domain.AuditActionCreate, book.ID, nil, book)
+	return nil
+}
+
+func (a *auditedBookUseCase) UpdateBook(ctx context.Context, id string, book *domain.Book) error {
+	// a missing book is reported by the wrapped use case
+	before, _ := a.bookRepository.GetBookByID(ctx, id)
+
+	if err := a.BookUseCase.UpdateBook(ctx, id, book); err != nil {
+		return err
+	}
+
+	a.record(ctx, domain.AuditActionUpdate, book.ID, before, book)
+	return nil
+}
+
+func (a *auditedBookUseCase) DeleteBook(ctx context.Context, id string) error {
+	before, _ := a.bookRepository.GetBookByID(ctx, id)
+
+	if err := a.BookUseCase.DeleteBook(ctx, id); err != nil {
+		return err
+	}
+
+	bookID, _ := uuid.Parse(id)
+	a.record(ctx, domain.AuditActionDelete, bookID, before, nil)
+	return nil
+}
+
+func (a *auditedBookUseCase) RevertBook(ctx context.Context, id string, revision int) (*domain.Book, error) {
+	// nil when the revert brings a deleted book back
+	before, _ := a.bookRepository.GetBookByID(ctx, id)
+
+	book, err := a.BookUseCase.RevertBook(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	a.record(ctx, domain.AuditActionRevert, book.ID, before, book)
+	return book, nil
+}
+
+func (a *auditedBookUseCase) RestoreBook(ctx context.Context, id string) (*domain.Book, error) {
+	book, err := a.BookUseCase.RestoreBook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	a.record(ctx, domain.AuditActionRestore, book.ID, nil, book)
+	return book, nil
+}
+
+func (a *auditedBookUseCase) record(ctx context.Context, action domain.AuditAction, bookID uuid.UUID, before *domain.Book, after *domain.Book) {
+	entry := domain.AuditEntry{
+		ID:        uuid.New(),
+		Time:      a.now().UTC(),
+		Action:    action,
+		BookID:    bookID,
+		Actor:     actor(ctx),
+		RequestID: applog.RequestID(ctx),
+		Changes:   diffBooks(before, after),
+	}
+	if p, ok := domain.PrincipalFromContext(ctx); ok {
+		entry.AuthMethod = p.Method
+	}
+
+	if err := a.auditRepository.AppendAuditEntry(ctx, &entry); err != nil {
+		slog.ErrorContext(ctx, "could not record an audit entry", "action", string(action), "book_id", bookID.String(), "error", err)
+	}
+}
+
+// diffBooks lists the fields of a book which differ between before and
+// after, by their JSON name, either may be nil. The ID and what a book is
+// sent back with but is never stored are left out
+func diffBooks(before *domain.Book, after *domain.Book) []domain.FieldChange {
+	changes := []domain.FieldChange{}
+	before, after = storedBook(before), storedBook(after)
+
+	t := reflect.TypeOf(domain.Book{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || name == "" || name == "id" || !f.IsExported() {
+			continue
+		}
+
+		// unset optional fields are left out, as they are in the JSON
+		omitEmpty := strings.Contains(opts, "omitempty")
+		b, a := fieldValue(before, i, omitEmpty), fieldValue(after, i, omitEmpty)
+		if reflect.DeepEqual(b, a) {
+			continue
+		}
+
+		changes = append(changes, domain.FieldChange{Field: name, Before: b, After: a})
+	}
+
+	return changes
+}
+
+// storedBook returns a copy of book without its copy counts and series
+// neighbours, nil when book is
+func storedBook(book *domain.Book) *domain.Book {
+	if book == nil {
+		return nil
+	}
+	stored := *book
+	stored.Copies = nil
+	if book.Series != nil {
+		stored.Series = make([]domain.SeriesEntry, len(book.Series))
+		for i, e := range book.Series {
+			e.Previous, e.Next = nil, nil
+			stored.Series[i] = e
+		}
+	}
+	return &stored
+}
+
+func fieldValue(book *domain.Book, i int, omitEmpty bool) interface{} {
+	if book == nil {
+		return nil
+	}
+	v := reflect.ValueOf(book).Elem().Field(i)
+	if omitEmpty && v.IsZero() {
+		return nil
+	}
+	return v.Interface()
+}
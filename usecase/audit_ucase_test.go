@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/applog"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuditedBookUseCase(t *testing.T) {
+	id := uuid.New()
+	before := &domain.Book{ID: id, Title: "Go", Author: "Rob", PublicationDate: "2015"}
+	alice := applog.WithRequestID(as(&domain.Principal{Subject: "alice", Method: domain.AuthMethodJWT}), "req-1")
+
+	t.Run("CreateBook", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("CreateBook", mock.Anything, mock.AnythingOfType("*domain.Book")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Book).ID = id
+		}).Return(nil).Once()
+		mockAuditRepo := new(appmock.MockAuditRepository)
+		mockAuditRepo.On("AppendAuditEntry", mock.Anything, mock.MatchedBy(func(e *domain.AuditEntry) bool {
+			return e.Action == domain.AuditActionCreate && e.BookID == id &&
+				e.Actor == "alice" && e.AuthMethod == domain.AuthMethodJWT && e.RequestID == "req-1" &&
+				assert.ElementsMatch(t, []domain.FieldChange{
+					{Field: "title", Before: nil, After: "Go"},
+					{Field: "author", Before: nil, After: "Rob"},
+					{Field: "publication_year", Before: nil, After: domain.PublicationDate("2015")},
+				}, e.Changes)
+		})).Return(nil).Once()
+
+		u := NewAuditedBookUseCase(NewBookUseCase(mockBookRepo), mockBookRepo, mockAuditRepo)
+
+		err := u.CreateBook(alice, &domain.Book{Title: "Go", Author: "Rob", PublicationDate: "2015"})
+
+		assert.NoError(t, err)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("UpdateBook", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("GetBookByID", mock.Anything, id.String()).Return(before, nil).Once()
+		mockBookRepo.On("UpdateBook", mock.Anything, mock.AnythingOfType("*domain.Book")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Book).ID = id
+		}).Return(nil).Once()
+		mockAuditRepo := new(appmock.MockAuditRepository)
+		mockAuditRepo.On("AppendAuditEntry", mock.Anything, mock.MatchedBy(func(e *domain.AuditEntry) bool {
+			return e.Action == domain.AuditActionUpdate && e.BookID == id &&
+				assert.Equal(t, []domain.FieldChange{{Field: "title", Before: "Go", After: "Go 2"}}, e.Changes)
+		})).Return(nil).Once()
+
+		u := NewAuditedBookUseCase(NewBookUseCase(mockBookRepo), mockBookRepo, mockAuditRepo)
+
+		err := u.UpdateBook(alice, id.String(), &domain.Book{Title: "Go 2", Author: "Rob", PublicationDate: "2015"})
+
+		assert.NoError(t, err)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("UpdateBook - Unchanged", func(t *testing.T) {
+		// the copy counts and series neighbours the book is sent back with
+		// are not changes
+		seriesID := uuid.New()
+		stored := &domain.Book{ID: id, Title: "Go", Author: "Rob", PublicationDate: "2015", Series: []domain.SeriesEntry{{SeriesID: seriesID, Position: 1}}}
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("GetBookByID", mock.Anything, id.String()).Return(stored, nil).Once()
+		mockBookRepo.On("UpdateBook", mock.Anything, mock.AnythingOfType("*domain.Book")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Book).ID = id
+		}).Return(nil).Once()
+		mockBookRepo.On("GetSeriesNeighbours", mock.Anything, id.String()).Return([]domain.SeriesEntry{
+			{SeriesID: seriesID, Position: 1, Next: &domain.SeriesNeighbour{ID: uuid.New(), Title: "Go 2", Position: 2}},
+		}, nil).Once()
+		mockCopyRepo := new(appmock.MockCopyRepository)
+		mockCopyRepo.On("CountCopies", mock.Anything, []uuid.UUID{id}).Return(map[uuid.UUID]domain.CopyCounts{id: {Total: 1, Available: 1}}, nil).Once()
+		mockAuditRepo := new(appmock.MockAuditRepository)
+		mockAuditRepo.On("AppendAuditEntry", mock.Anything, mock.MatchedBy(func(e *domain.AuditEntry) bool {
+			return e.Action == domain.AuditActionUpdate && assert.Empty(t, e.Changes)
+		})).Return(nil).Once()
+
+		u := NewAuditedBookUseCase(NewBookUseCase(mockBookRepo, WithCopyRepository(mockCopyRepo)), mockBookRepo, mockAuditRepo)
+
+		book := &domain.Book{Title: "Go", Author: "Rob", PublicationDate: "2015", Series: []domain.SeriesEntry{{SeriesID: seriesID, Position: 1}}}
+		err := u.UpdateBook(alice, id.String(), book)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, book.Copies)
+		assert.NotNil(t, book.Series[0].Next)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("DeleteBook - Anonymous", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("GetBookByID", mock.Anything, id.String()).Return(before, nil).Once()
+		mockBookRepo.On("DeleteBook", mock.Anything, id.String(), domain.ActorAnonymous).Return(nil).Once()
+		mockAuditRepo := new(appmock.MockAuditRepository)
+		mockAuditRepo.On("AppendAuditEntry", mock.Anything, mock.MatchedBy(func(e *domain.AuditEntry) bool {
+			return e.Action == domain.AuditActionDelete && e.BookID == id && e.Actor == domain.ActorAnonymous &&
+				len(e.Changes) == 3 && e.Changes[0].After == nil
+		})).Return(nil).Once()
+
+		u := NewAuditedBookUseCase(NewBookUseCase(mockBookRepo), mockBookRepo, mockAuditRepo)
+
+		assert.NoError(t, u.DeleteBook(context.Background(), id.String()))
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("RevertBook - Deleted book", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("GetBookByID", mock.Anything, id.String()).Return((*domain.Book)(nil), apperror.NewNotFound("Book", "ID", id.String())).Once()
+		mockBookRepo.On("FetchBookHistory", mock.Anything, id.String()).Return(&[]domain.BookRevision{{Revision: 1, Book: *before}}, nil).Once()
+		mockBookRepo.On("RevertBook", mock.Anything, id.String(), 1).Return(before, nil).Once()
+		mockAuditRepo := new(appmock.MockAuditRepository)
+		mockAuditRepo.On("AppendAuditEntry", mock.Anything, mock.MatchedBy(func(e *domain.AuditEntry) bool {
+			return e.Action == domain.AuditActionRevert && e.BookID == id && len(e.Changes) == 3 && e.Changes[0].Before == nil
+		})).Return(nil).Once()
+
+		u := NewAuditedBookUseCase(NewBookUseCase(mockBookRepo), mockBookRepo, mockAuditRepo)
+
+		_, err := u.RevertBook(alice, id.String(), 1)
+
+		assert.NoError(t, err)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("RestoreBook", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("FetchTrash", mock.Anything).Return(&[]domain.TrashedBook{{Book: *before}}, nil).Once()
+		mockBookRepo.On("RestoreBook", mock.Anything, id.String()).Return(before, nil).Once()
+		mockAuditRepo := new(appmock.MockAuditRepository)
+		mockAuditRepo.On("AppendAuditEntry", mock.Anything, mock.MatchedBy(func(e *domain.AuditEntry) bool {
+			return e.Action == domain.AuditActionRestore && e.BookID == id && e.Actor == "alice"
+		})).Return(nil).Once()
+
+		u := NewAuditedBookUseCase(NewBookUseCase(mockBookRepo), mockBookRepo, mockAuditRepo)
+
+		_, err := u.RestoreBook(alice, id.String())
+
+		assert.NoError(t, err)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failed change is not recorded", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("GetBookByID", mock.Anything, "missing").Return((*domain.Book)(nil), apperror.NewNotFound("Book", "ID", "missing")).Once()
+		mockBookRepo.On("DeleteBook", mock.Anything, "missing", "alice").Return(apperror.NewNotFound("Book", "ID", "missing")).Once()
+		mockAuditRepo := new(appmock.MockAuditRepository)
+
+		u := NewAuditedBookUseCase(NewBookUseCase(mockBookRepo), mockBookRepo, mockAuditRepo)
+
+		err := u.DeleteBook(alice, "missing")
+
+		assert.Equal(t, http.StatusNotFound, apperror.Status(err))
+		mockAuditRepo.AssertNotCalled(t, "AppendAuditEntry")
+	})
+
+	t.Run("Audit failure does not fail the change", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("CreateBook", mock.Anything, mock.AnythingOfType("*domain.Book")).Return(nil).Once()
+		mockAuditRepo := new(appmock.MockAuditRepository)
+		mockAuditRepo.On("AppendAuditEntry", mock.Anything, mock.Anything).Return(errors.New("disk full")).Once()
+
+		u := NewAuditedBookUseCase(NewBookUseCase(mockBookRepo), mockBookRepo, mockAuditRepo)
+
+		assert.NoError(t, u.CreateBook(alice, &domain.Book{Title: "Go", Author: "Rob", PublicationDate: "2015"}))
+		mockAuditRepo.AssertExpectations(t)
+	})
+}
+
+func TestFetchAuditEntries(t *testing.T) {
+	filter := domain.AuditFilter{Actor: "alice", From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	t.Run("Success", func(t *testing.T) {
+		mockAuditRepo := new(appmock.MockAuditRepository)
+		mockAuditRepo.On("FetchAuditEntries", mock.Anything, filter).Return(&[]domain.AuditEntry{}, nil).Once()
+
+		u := NewAuditUseCase(mockAuditRepo, WithAuthorizer(NewRBAC(DefaultPolicy())))
+
+		_, err := u.FetchAuditEntries(as(&domain.Principal{Subject: "root", Method: domain.AuthMethodJWT, Roles: []string{domain.RoleAdmin}}), filter)
+
+		assert.NoError(t, err)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		mockAuditRepo := new(appmock.MockAuditRepository)
+
+		u := NewAuditUseCase(mockAuditRepo, WithAuthorizer(NewRBAC(DefaultPolicy())))
+
+		_, err := u.FetchAuditEntries(as(&domain.Principal{Subject: "editor", Method: domain.AuthMethodJWT, Roles: []string{domain.RoleEditor}}), filter)
+
+		assert.Equal(t, http.StatusForbidden, apperror.Status(err))
+		mockAuditRepo.AssertNotCalled(t, "FetchAuditEntries")
+	})
+}
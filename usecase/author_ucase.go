@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/apptrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// authorIDKey is the span attribute of the author an operation is about
+const authorIDKey = attribute.Key("author.id")
+
+type authorUseCase struct {
+	options
+	authorRepository domain.AuthorRepository
+	bookUseCase      domain.BookUseCase
+}
+
+// NewAuthorUseCase manages the authors of authorRepository, the books
+// linked to them are read and, by MigrateAuthors, updated through bu so
+// the changes are authorized and audited like any other
+func NewAuthorUseCase(authorRepository domain.AuthorRepository, bu domain.BookUseCase, opts ...Option) domain.AuthorUseCase {
+	return &authorUseCase{
+		options:          newOptions(opts),
+		authorRepository: authorRepository,
+		bookUseCase:      bu,
+	}
+}
+
+func (a *authorUseCase) FetchAuthors(ctx context.Context, filter domain.AuthorFilter) (authors *[]domain.Author, err error) {
+	ctx, span := a.tracer.Start(ctx, "authorUseCase.FetchAuthors")
+	defer func() { apptrace.End(span, err) }()
+
+	if err := a.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	return a.authorRepository.FetchAuthors(ctx, filter)
+}
+
+func (a *authorUseCase) GetAuthorByID(ctx context.Context, id string) (author *domain.Author, err error) {
+	ctx, span := a.tracer.Start(ctx, "authorUseCase.GetAuthorByID", trace.WithAttributes(authorIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := a.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	return a.authorRepository.GetAuthorByID(ctx, id)
+}
+
+func (a *authorUseCase) CreateAuthor(ctx context.Context, author *domain.Author) (err error) {
+	ctx, span := a.tracer.Start(ctx, "authorUseCase.CreateAuthor")
+	defer func() { apptrace.End(span, err) }()
+
+	if err := a.authorize(ctx, domain.PermissionBooksWrite); err != nil {
+		return err
+	}
+
+	if err := a.authorRepository.CreateAuthor(ctx, author); err != nil {
+		return err
+	}
+
+	span.SetAttributes(authorIDKey.String(author.ID.String()))
+	slog.InfoContext(ctx, "author created", "author_id", author.ID.String())
+	return nil
+}
+
+func (a *authorUseCase) UpdateAuthor(ctx context.Context, id string, author *domain.Author) (err error) {
+	ctx, span := a.tracer.Start(ctx, "authorUseCase.UpdateAuthor", trace.WithAttributes(authorIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := a.authorize(ctx, domain.PermissionBooksWrite); err != nil {
+		return err
+	}
+
+	if err := a.authorRepository.UpdateAuthor(ctx, id, author); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "author updated", "author_id", id)
+	return nil
+}
+
+func (a *authorUseCase) DeleteAuthor(ctx context.Context, id string) (err error) {
+	ctx, span := a.tracer.Start(ctx, "authorUseCase.DeleteAuthor", trace.WithAttributes(authorIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := a.authorize(ctx, domain.PermissionBooksDelete); err != nil {
+		return err
+	}
+
+	author, err := a.authorRepository.GetAuthorByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	books, err := listBooks(ctx, a.bookUseCase, domain.BookFilter{AuthorID: author.ID})
+	if err != nil {
+		return err
+	}
+	if len(books) > 0 {
+		return apperror.NewConflictCode("books_of_author", apperror.Params{"author": author.Name})
+	}
+	// a trashed book may be restored, keep its authors
+	trash, err := a.bookUseCase.FetchTrash(ctx)
+	if err != nil {
+		return err
+	}
+	for _, book := range *trash {
+		if book.HasContributor(author.ID) {
+			return apperror.NewConflictCode("trashed_books_of_author", apperror.Params{"author": author.Name})
+		}
+	}
+
+	if err := a.authorRepository.DeleteAuthor(ctx, id); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "author deleted", "author_id", id)
+	return nil
+}
+
+func (a *authorUseCase) FetchAuthorBooks(ctx context.Context, id string) (books *[]domain.Book, err error) {
+	ctx, span := a.tracer.Start(ctx, "authorUseCase.FetchAuthorBooks", trace.WithAttributes(authorIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := a.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	author, err := a.authorRepository.GetAuthorByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	found, err := listBooks(ctx, a.bookUseCase, domain.BookFilter{AuthorID: author.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &found, nil
+}
+
+func (a *authorUseCase) MigrateAuthors(ctx context.Context) (migration *domain.AuthorMigration, err error) {
+	ctx, span := a.tracer.Start(ctx, "authorUseCase.MigrateAuthors")
+	defer func() { apptrace.End(span, err) }()
+
+	// the books are updated through the book use case, which checks
+	// books:write again for each of them
+	if err := a.authorize(ctx, domain.PermissionBooksWrite); err != nil {
+		return nil, err
+	}
+
+	books, err := listBooks(ctx, a.bookUseCase, domain.BookFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	migration = &domain.AuthorMigration{Failed: []domain.AuthorMigrationFailure{}}
+	for _, book := range books {
+		if len(book.Contributors) > 0 {
+			continue
+		}
+
+		for _, name := range SplitAuthorNames(book.Author) {
+			author, created, err := a.authorByName(ctx, name)
+			if err != nil {
+				return migration, err
+			}
+			if created {
+				migration.Authors++
+			}
+			book.Contributors = append(book.Contributors, domain.Contributor{AuthorID: author.ID, Role: domain.ContributorAuthor})
+		}
+		if len(book.Contributors) == 0 {
+			continue
+		}
+
+		if err := a.bookUseCase.UpdateBook(ctx, book.ID.String(), &book); err != nil {
+			// eg. a book stored before its fields were validated
+			migration.Failed = append(migration.Failed, domain.AuthorMigrationFailure{BookID: book.ID, Error: err.Error()})
+			continue
+		}
+		migration.Books++
+	}
+
+	slog.InfoContext(ctx, "authors migrated", "books", migration.Books, "authors", migration.Authors, "failed", len(migration.Failed))
+	return migration, nil
+}
+
+// authorByName returns the author with the AuthorKey of name, creating
+// it when there is none
+func (a *authorUseCase) authorByName(ctx context.Context, name string) (*domain.Author, bool, error) {
+	author, err := a.authorRepository.GetAuthorByName(ctx, name)
+	if err == nil {
+		return author, false, nil
+	}
+	if !errors.Is(err, &apperror.Error{Type: apperror.NotFound}) {
+		return nil, false, err
+	}
+
+	author = &domain.Author{Name: name}
+	if err := a.authorRepository.CreateAuthor(ctx, author); err != nil {
+		return nil, false, err
+	}
+	return author, true, nil
+}
+
+// listBooks lists the books of bu matching filter, none rather than a
+// not found error
+func listBooks(ctx context.Context, bu domain.BookUseCase, filter domain.BookFilter) ([]domain.Book, error) {
+	books, err := bu.FetchBooks(ctx, filter)
+	if errors.Is(err, &apperror.Error{Type: apperror.NotFound}) {
+		return []domain.Book{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return *books, nil
+}
+
+// SplitAuthorNames splits an author string naming several people, eg.
+// "Brian Kernighan & Dennis Ritchie", at ";", "&" and " and "
+func SplitAuthorNames(s string) []string {
+	s = strings.NewReplacer(" and ", ";", "&", ";").Replace(s)
+
+	var names []string
+	for _, name := range strings.Split(s, ";") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeleteAuthor(t *testing.T) {
+	author := &domain.Author{ID: uuid.New(), Name: "Jane Austen"}
+	linked := domain.Book{ID: uuid.New(), Contributors: []domain.Contributor{{AuthorID: author.ID, Role: domain.ContributorAuthor}}}
+
+	tests := []struct {
+		name  string
+		books []domain.Book
+		trash []domain.TrashedBook
+		code  int
+	}{
+		{name: "No books", code: http.StatusOK},
+		{name: "Linked to a book", books: []domain.Book{linked}, code: http.StatusConflict},
+		{name: "Linked to a trashed book", trash: []domain.TrashedBook{{Book: linked}}, code: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthorRepo := new(appmock.MockAuthorRepository)
+			mockAuthorRepo.On("GetAuthorByID", mock.Anything, author.ID.String()).Return(author, nil)
+			mockAuthorRepo.On("DeleteAuthor", mock.Anything, author.ID.String()).Return(nil).Maybe()
+			mockBookUseCase := new(appmock.MockBookUseCase)
+			if tt.books == nil {
+				mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{AuthorID: author.ID}).Return((*[]domain.Book)(nil), apperror.NewNotFound("Book", "author", author.ID.String()))
+			} else {
+				mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{AuthorID: author.ID}).Return(&tt.books, nil)
+			}
+			mockBookUseCase.On("FetchTrash", mock.Anything).Return(&tt.trash, nil).Maybe()
+
+			u := NewAuthorUseCase(mockAuthorRepo, mockBookUseCase)
+
+			err := u.DeleteAuthor(context.Background(), author.ID.String())
+
+			if tt.code == http.StatusOK {
+				assert.NoError(t, err)
+				mockAuthorRepo.AssertCalled(t, "DeleteAuthor", mock.Anything, author.ID.String())
+				return
+			}
+			assert.Equal(t, tt.code, apperror.Status(err))
+			mockAuthorRepo.AssertNotCalled(t, "DeleteAuthor", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestFetchAuthorBooks(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		author := &domain.Author{ID: uuid.New(), Name: "Jane Austen"}
+		books := []domain.Book{{ID: uuid.New(), Title: "Emma"}}
+		mockAuthorRepo := new(appmock.MockAuthorRepository)
+		mockAuthorRepo.On("GetAuthorByID", mock.Anything, author.ID.String()).Return(author, nil).Once()
+		mockBookUseCase := new(appmock.MockBookUseCase)
+		mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{AuthorID: author.ID}).Return(&books, nil).Once()
+
+		u := NewAuthorUseCase(mockAuthorRepo, mockBookUseCase)
+
+		found, err := u.FetchAuthorBooks(context.Background(), author.ID.String())
+
+		assert.NoError(t, err)
+		assert.Equal(t, books, *found)
+	})
+
+	t.Run("Unknown author", func(t *testing.T) {
+		id := uuid.New().String()
+		mockAuthorRepo := new(appmock.MockAuthorRepository)
+		mockAuthorRepo.On("GetAuthorByID", mock.Anything, id).Return((*domain.Author)(nil), apperror.NewNotFound("Author", "ID", id)).Once()
+		mockBookUseCase := new(appmock.MockBookUseCase)
+
+		u := NewAuthorUseCase(mockAuthorRepo, mockBookUseCase)
+
+		_, err := u.FetchAuthorBooks(context.Background(), id)
+
+		assert.Equal(t, http.StatusNotFound, apperror.Status(err))
+		mockBookUseCase.AssertNotCalled(t, "FetchBooks", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMigrateAuthors(t *testing.T) {
+	existing := &domain.Author{ID: uuid.New(), Name: "Dennis Ritchie"}
+	migrated := domain.Book{ID: uuid.New(), Author: "Brian Kernighan & Dennis M. Ritchie"}
+	linked := domain.Book{ID: uuid.New(), Author: "Jane Austen", Contributors: []domain.Contributor{{AuthorID: uuid.New(), Role: domain.ContributorAuthor}}}
+	failing := domain.Book{ID: uuid.New(), Author: "Kernighan"}
+	books := []domain.Book{migrated, linked, failing}
+
+	mockAuthorRepo := new(appmock.MockAuthorRepository)
+	mockAuthorRepo.On("GetAuthorByName", mock.Anything, "Brian Kernighan").Return((*domain.Author)(nil), apperror.NewNotFound("Author", "name", "Brian Kernighan")).Once()
+	mockAuthorRepo.On("CreateAuthor", mock.Anything, mock.MatchedBy(func(a *domain.Author) bool { return a.Name == "Brian Kernighan" })).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Author).ID = uuid.New()
+	}).Return(nil).Once()
+	// the repository decides which spellings name the same author
+	mockAuthorRepo.On("GetAuthorByName", mock.Anything, "Dennis M. Ritchie").Return(existing, nil).Once()
+	mockAuthorRepo.On("GetAuthorByName", mock.Anything, "Kernighan").Return(&domain.Author{ID: uuid.New(), Name: "Kernighan"}, nil).Once()
+	mockBookUseCase := new(appmock.MockBookUseCase)
+	mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{}).Return(&books, nil).Once()
+	mockBookUseCase.On("UpdateBook", mock.Anything, migrated.ID.String(), mock.MatchedBy(func(b *domain.Book) bool {
+		return len(b.Contributors) == 2 && b.Contributors[1] == domain.Contributor{AuthorID: existing.ID, Role: domain.ContributorAuthor}
+	})).Return(nil).Once()
+	mockBookUseCase.On("UpdateBook", mock.Anything, failing.ID.String(), mock.Anything).Return(apperror.NewBadRequest("invalid book")).Once()
+
+	u := NewAuthorUseCase(mockAuthorRepo, mockBookUseCase)
+
+	migration, err := u.MigrateAuthors(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, migration.Books)
+	assert.Equal(t, 1, migration.Authors)
+	assert.Equal(t, []domain.AuthorMigrationFailure{{BookID: failing.ID, Error: "Bad request. Reason: invalid book"}}, migration.Failed)
+	mockAuthorRepo.AssertExpectations(t)
+	mockBookUseCase.AssertExpectations(t)
+}
+
+func TestSplitAuthorNames(t *testing.T) {
+	assert.Equal(t, []string{"Brian Kernighan", "Dennis Ritchie"}, SplitAuthorNames("Brian Kernighan & Dennis Ritchie"))
+	assert.Equal(t, []string{"A", "B", "C"}, SplitAuthorNames("A; B and C"))
+	assert.Equal(t, []string{"J. K. Rowling"}, SplitAuthorNames(" J. K. Rowling "))
+	assert.Empty(t, SplitAuthorNames(" ; "))
+}
+
+func TestCreateBook_Contributors(t *testing.T) {
+	author := uuid.New()
+
+	tests := []struct {
+		name         string
+		contributors []domain.Contributor
+		code         string // of the error, none when accepted
+	}{
+		{name: "Known author", contributors: []domain.Contributor{{AuthorID: author, Role: domain.ContributorAuthor}}},
+		{name: "Several roles", contributors: []domain.Contributor{{AuthorID: author, Role: domain.ContributorAuthor}, {AuthorID: author, Role: domain.ContributorIllustrator}}},
+		{name: "Same role twice", contributors: []domain.Contributor{{AuthorID: author, Role: domain.ContributorAuthor}, {AuthorID: author, Role: domain.ContributorAuthor}}, code: "duplicate_contributor"},
+		{name: "Unknown author", contributors: []domain.Contributor{{AuthorID: uuid.New(), Role: domain.ContributorEditor}}, code: "unknown_author"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBookRepo := new(appmock.MockBookRepository)
+			mockBookRepo.On("CreateBook", mock.Anything, mock.AnythingOfType("*domain.Book")).Return(nil).Maybe()
+			mockAuthorRepo := new(appmock.MockAuthorRepository)
+			mockAuthorRepo.On("GetAuthorByID", mock.Anything, author.String()).Return(&domain.Author{ID: author}, nil).Maybe()
+			mockAuthorRepo.On("GetAuthorByID", mock.Anything, mock.Anything).Return((*domain.Author)(nil), apperror.NewNotFound("Author", "ID", "")).Maybe()
+
+			u := NewBookUseCase(mockBookRepo, WithAuthorRepository(mockAuthorRepo))
+
+			err := u.CreateBook(context.Background(), &domain.Book{Title: "Test Book", Author: "Test Author", PublicationDate: "2015", Contributors: tt.contributors})
+
+			if tt.code == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, &apperror.Error{Type: apperror.BadRequest, Code: tt.code})
+			mockBookRepo.AssertNotCalled(t, "CreateBook", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -2,36 +2,494 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/apptrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// bookIDKey is the span attribute of the book an operation is about
+const bookIDKey = attribute.Key("book.id")
+
 type bookUseCase struct {
+	options
 	bookRepository domain.BookRepository
 }
 
-func NewBookUseCase(bookRepository domain.BookRepository) domain.BookUseCase {
+func NewBookUseCase(bookRepository domain.BookRepository, opts ...Option) domain.BookUseCase {
 	return &bookUseCase{
+		options:        newOptions(opts),
 		bookRepository: bookRepository,
 	}
 }
 
-func (b *bookUseCase) FetchBooks(ctx context.Context) (*[]domain.Book, error) {
-	return b.bookRepository.FetchBooks(ctx)
+func (b *bookUseCase) FetchBooks(ctx context.Context, filter domain.BookFilter) (books *[]domain.Book, err error) {
+	ctx, span := b.tracer.Start(ctx, "bookUseCase.FetchBooks")
+	defer func() { apptrace.End(span, err) }()
+
+	if err := b.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	// an invalid ISBN is kept as it is, matching no book
+	if isbn, ok := domain.NormalizeISBN(filter.ISBN); ok {
+		filter.ISBN = isbn
+	}
+
+	books, err = b.bookRepository.FetchBooks(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]*domain.Book, len(*books))
+	for i := range *books {
+		refs[i] = &(*books)[i]
+	}
+	if err := b.setCopies(ctx, refs...); err != nil {
+		return nil, err
+	}
+
+	return books, nil
 }
 
-func (b *bookUseCase) GetBookByID(ctx context.Context, id string) (*domain.Book, error) {
-	return b.bookRepository.GetBookByID(ctx, id)
+func (b *bookUseCase) GetBookByID(ctx context.Context, id string) (book *domain.Book, err error) {
+	ctx, span := b.tracer.Start(ctx, "bookUseCase.GetBookByID", trace.WithAttributes(bookIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := b.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	book, err = b.bookRepository.GetBookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.complete(ctx, book); err != nil {
+		return nil, err
+	}
+
+	return book, nil
+}
+
+func (b *bookUseCase) GetBookByISBN(ctx context.Context, isbn string) (book *domain.Book, err error) {
+	ctx, span := b.tracer.Start(ctx, "bookUseCase.GetBookByISBN", trace.WithAttributes(attribute.String("book.isbn", isbn)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := b.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	normalized, ok := domain.NormalizeISBN(isbn)
+	if !ok {
+		return nil, invalidISBN(isbn)
+	}
+
+	book, err = b.bookRepository.GetBookByISBN(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.complete(ctx, book); err != nil {
+		return nil, err
+	}
+
+	return book, nil
 }
 
-func (b *bookUseCase) CreateBook(ctx context.Context, book *domain.Book) error {
-	return b.bookRepository.CreateBook(ctx, book)
+func (b *bookUseCase) CreateBook(ctx context.Context, book *domain.Book) (err error) {
+	ctx, span := b.tracer.Start(ctx, "bookUseCase.CreateBook")
+	defer func() { apptrace.End(span, err) }()
+
+	if err := b.authorize(ctx, domain.PermissionBooksWrite); err != nil {
+		return err
+	}
+
+	// counted on every read
+	book.Copies = nil
+	if err := normalizeISBN(book); err != nil {
+		return err
+	}
+	if err := b.checkPublicationDate(book); err != nil {
+		return err
+	}
+	if err := b.checkReferences(ctx, book); err != nil {
+		return err
+	}
+
+	if err := b.bookRepository.CreateBook(ctx, book); err != nil {
+		return err
+	}
+
+	span.SetAttributes(bookIDKey.String(book.ID.String()))
+	slog.InfoContext(ctx, "book created", "book_id", book.ID.String())
+	return nil
 }
 
-func (b *bookUseCase) UpdateBook(ctx context.Context, id string, book *domain.Book) error {
-	return b.bookRepository.UpdateBook(ctx, id, book)
+func (b *bookUseCase) UpdateBook(ctx context.Context, id string, book *domain.Book) (err error) {
+	ctx, span := b.tracer.Start(ctx, "bookUseCase.UpdateBook", trace.WithAttributes(bookIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := b.authorize(ctx, domain.PermissionBooksWrite); err != nil {
+		return err
+	}
+
+	// counted on every read
+	book.Copies = nil
+	if err := normalizeISBN(book); err != nil {
+		return err
+	}
+	if err := b.checkPublicationDate(book); err != nil {
+		return err
+	}
+	if err := b.checkReferences(ctx, book); err != nil {
+		return err
+	}
+
+	if err := b.bookRepository.UpdateBook(ctx, id, book); err != nil {
+		return err
+	}
+	if err := b.complete(ctx, book); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "book updated", "book_id", id)
+	return nil
+}
+
+func (b *bookUseCase) DeleteBook(ctx context.Context, id string) (err error) {
+	ctx, span := b.tracer.Start(ctx, "bookUseCase.DeleteBook", trace.WithAttributes(bookIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := b.authorize(ctx, domain.PermissionBooksDelete); err != nil {
+		return err
+	}
+
+	if err := b.checkCopies(ctx, id); err != nil {
+		return err
+	}
+	if err := b.bookRepository.DeleteBook(ctx, id, actor(ctx)); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "book deleted", "book_id", id)
+	return nil
+}
+
+func (b *bookUseCase) FetchBookHistory(ctx context.Context, id string) (history *[]domain.BookRevision, err error) {
+	ctx, span := b.tracer.Start(ctx, "bookUseCase.FetchBookHistory", trace.WithAttributes(bookIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := b.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	return b.bookRepository.FetchBookHistory(ctx, id)
+}
+
+func (b *bookUseCase) GetBookAsOf(ctx context.Context, id string, at time.Time) (book *domain.Book, err error) {
+	ctx, span := b.tracer.Start(ctx, "bookUseCase.GetBookAsOf", trace.WithAttributes(bookIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := b.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	return b.bookRepository.GetBookAsOf(ctx, id, at)
+}
+
+func (b *bookUseCase) RevertBook(ctx context.Context, id string, revision int) (book *domain.Book, err error) {
+	ctx, span := b.tracer.Start(ctx, "bookUseCase.RevertBook", trace.WithAttributes(bookIDKey.String(id), attribute.Int("book.revision", revision)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := b.authorize(ctx, domain.PermissionBooksWrite); err != nil {
+		return nil, err
+	}
+
+	// the authors, publisher, series or work of the revision may have
+	// been deleted since
+	history, err := b.bookRepository.FetchBookHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if revision >= 1 && revision <= len(*history) {
+		if err := b.checkReferences(ctx, &(*history)[revision-1].Book); err != nil {
+			return nil, err
+		}
+	}
+
+	book, err = b.bookRepository.RevertBook(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.complete(ctx, book); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "book reverted", "book_id", id, "revision", revision)
+	return book, nil
+}
+
+func (b *bookUseCase) FetchTrash(ctx context.Context) (trash *[]domain.TrashedBook, err error) {
+	ctx, span := b.tracer.Start(ctx, "bookUseCase.FetchTrash")
+	defer func() { apptrace.End(span, err) }()
+
+	// the trash is for those who may empty it
+	if err := b.authorize(ctx, domain.PermissionBooksDelete); err != nil {
+		return nil, err
+	}
+
+	return b.bookRepository.FetchTrash(ctx)
+}
+
+func (b *bookUseCase) HasTrashedBooks(ctx context.Context, filter domain.BookFilter) (found bool, err error) {
+	ctx, span := b.tracer.Start(ctx, "bookUseCase.HasTrashedBooks")
+	defer func() { apptrace.End(span, err) }()
+
+	if err := b.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return false, err
+	}
+
+	trash, err := b.bookRepository.FetchTrash(ctx)
+	if err != nil {
+		return false, err
+	}
+	for i := range *trash {
+		if filter.Match(&(*trash)[i].Book) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (b *bookUseCase) RestoreBook(ctx context.Context, id string) (book *domain.Book, err error) {
+	ctx, span := b.tracer.Start(ctx, "bookUseCase.RestoreBook", trace.WithAttributes(bookIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := b.authorize(ctx, domain.PermissionBooksDelete); err != nil {
+		return nil, err
+	}
+
+	// the authors, publisher, series or work of the book may have been
+	// deleted while it was in the trash
+	trash, err := b.bookRepository.FetchTrash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range *trash {
+		if (*trash)[i].ID.String() == id {
+			if err := b.checkReferences(ctx, &(*trash)[i].Book); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	book, err = b.bookRepository.RestoreBook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.complete(ctx, book); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "book restored", "book_id", id)
+	return book, nil
+}
+
+// actor names the caller of ctx in audit entries and the trash
+func actor(ctx context.Context) string {
+	if p, ok := domain.PrincipalFromContext(ctx); ok {
+		return p.Subject
+	}
+	return domain.ActorAnonymous
+}
+
+// normalizeISBN stores the ISBN of book as 13 digits, whichever form it
+// was given in
+func normalizeISBN(book *domain.Book) error {
+	if book.ISBN == "" {
+		return nil
+	}
+
+	isbn, ok := domain.NormalizeISBN(book.ISBN)
+	if !ok {
+		return invalidISBN(book.ISBN)
+	}
+	book.ISBN = isbn
+	return nil
+}
+
+// checkPublicationDate rejects a malformed publication date, and one
+// later than the grace period from now
+func (b *bookUseCase) checkPublicationDate(book *domain.Book) error {
+	if !book.PublicationDate.Valid() {
+		return apperror.NewBadRequestCode("invalid_publication_date", apperror.Params{"date": strconv.Quote(string(book.PublicationDate)), "min": domain.MinPublicationYear})
+	}
+
+	latest := b.now().Add(b.publicationGrace)
+	if book.PublicationDate.Start().After(latest) {
+		return apperror.NewBadRequestCode("publication_date_too_late", apperror.Params{"date": string(book.PublicationDate), "latest": latest.Format(time.DateOnly)})
+	}
+	return nil
+}
+
+// checkReferences rejects a book whose contributors, publisher, series
+// or work are not in the catalog
+func (b *bookUseCase) checkReferences(ctx context.Context, book *domain.Book) error {
+	if err := b.checkContributors(ctx, book); err != nil {
+		return err
+	}
+	if err := b.checkPublisher(ctx, book); err != nil {
+		return err
+	}
+	if err := b.checkSeries(ctx, book); err != nil {
+		return err
+	}
+	return b.checkWork(ctx, book)
+}
+
+// checkContributors rejects contributors listed twice in the same role,
+// and those who are not authors of the author repository when there is
+// one
+func (b *bookUseCase) checkContributors(ctx context.Context, book *domain.Book) error {
+	seen := map[domain.Contributor]bool{}
+	for _, c := range book.Contributors {
+		if seen[c] {
+			return apperror.NewBadRequestCode("duplicate_contributor", apperror.Params{"author_id": c.AuthorID, "role": c.Role})
+		}
+		seen[c] = true
+
+		if b.authorRepository == nil {
+			continue
+		}
+		_, err := b.authorRepository.GetAuthorByID(ctx, c.AuthorID.String())
+		if errors.Is(err, &apperror.Error{Type: apperror.NotFound}) {
+			return apperror.NewBadRequestCode("unknown_author", apperror.Params{"author_id": c.AuthorID})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPublisher rejects a publisher which is not in the publisher
+// repository when there is one
+func (b *bookUseCase) checkPublisher(ctx context.Context, book *domain.Book) error {
+	if book.PublisherID == nil || b.publisherRepository == nil {
+		return nil
+	}
+	_, err := b.publisherRepository.GetPublisherByID(ctx, book.PublisherID.String())
+	if errors.Is(err, &apperror.Error{Type: apperror.NotFound}) {
+		return apperror.NewBadRequestCode("unknown_publisher", apperror.Params{"publisher_id": *book.PublisherID})
+	}
+	return err
+}
+
+// checkSeries rejects a book listed twice in the same series, and series
+// which are not in the series repository when there is one. The
+// neighbours sent back with a book read earlier are dropped
+func (b *bookUseCase) checkSeries(ctx context.Context, book *domain.Book) error {
+	seen := map[uuid.UUID]bool{}
+	for i := range book.Series {
+		e := &book.Series[i]
+		e.Previous, e.Next = nil, nil
+		if seen[e.SeriesID] {
+			return apperror.NewBadRequestCode("duplicate_series", apperror.Params{"series_id": e.SeriesID})
+		}
+		seen[e.SeriesID] = true
+
+		if b.seriesRepository == nil {
+			continue
+		}
+		_, err := b.seriesRepository.GetSeriesByID(ctx, e.SeriesID.String())
+		if errors.Is(err, &apperror.Error{Type: apperror.NotFound}) {
+			return apperror.NewBadRequestCode("unknown_series", apperror.Params{"series_id": e.SeriesID})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkWork rejects a work which is not in the work repository when
+// there is one
+func (b *bookUseCase) checkWork(ctx context.Context, book *domain.Book) error {
+	if book.WorkID == nil || b.workRepository == nil {
+		return nil
+	}
+	_, err := b.workRepository.GetWorkByID(ctx, book.WorkID.String())
+	if errors.Is(err, &apperror.Error{Type: apperror.NotFound}) {
+		return apperror.NewBadRequestCode("unknown_work", apperror.Params{"work_id": *book.WorkID})
+	}
+	return err
+}
+
+// complete sets what a single book read or written is sent back with
+// but which is never stored: its series neighbours and copy counts
+func (b *bookUseCase) complete(ctx context.Context, book *domain.Book) error {
+	if err := b.setSeriesNeighbours(ctx, book); err != nil {
+		return err
+	}
+	return b.setCopies(ctx, book)
+}
+
+// setSeriesNeighbours sets the books read before and after book in each
+// of its series
+func (b *bookUseCase) setSeriesNeighbours(ctx context.Context, book *domain.Book) error {
+	if len(book.Series) == 0 {
+		return nil
+	}
+	entries, err := b.bookRepository.GetSeriesNeighbours(ctx, book.ID.String())
+	if err != nil {
+		return err
+	}
+	book.Series = entries
+	return nil
+}
+
+// setCopies counts the copies of each of books
+func (b *bookUseCase) setCopies(ctx context.Context, books ...*domain.Book) error {
+	if b.copyRepository == nil || len(books) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(books))
+	for i, book := range books {
+		ids[i] = book.ID
+	}
+	counts, err := b.copyRepository.CountCopies(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, book := range books {
+		n := counts[book.ID]
+		book.Copies = &n
+	}
+	return nil
+}
+
+// checkCopies rejects the delete of a book which has active copies, lost
+// copies are left with the trashed book
+func (b *bookUseCase) checkCopies(ctx context.Context, id string) error {
+	bookID, err := uuid.Parse(id)
+	if b.copyRepository == nil || err != nil {
+		return nil
+	}
+	counts, err := b.copyRepository.CountCopies(ctx, []uuid.UUID{bookID})
+	if err != nil {
+		return err
+	}
+	if counts[bookID].Total > 0 {
+		return apperror.NewConflictCode("active_copies", apperror.Params{"book_id": id})
+	}
+	return nil
 }
 
-func (b *bookUseCase) DeleteBook(ctx context.Context, id string) error {
-	return b.bookRepository.DeleteBook(ctx, id)
+func invalidISBN(isbn string) error {
+	return apperror.NewBadRequestCode("invalid_isbn", apperror.Params{"isbn": strconv.Quote(isbn)})
 }
@@ -2,29 +2,36 @@ package usecase
 
 import (
 	"context"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
 	"github.com/krittawatcode/books/domain/appmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestFetchBooks(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockBookRepo := new(appmock.MockBookRepository)
 		mockBooks := &[]domain.Book{
-			{Title: "Test Book 1", Author: "Test Author 1", PublicationYear: "2021"},
-			{Title: "Test Book 2", Author: "Test Author 2", PublicationYear: "2022"},
+			{Title: "Test Book 1", Author: "Test Author 1", PublicationDate: "2021"},
+			{Title: "Test Book 2", Author: "Test Author 2", PublicationDate: "2022"},
 		}
 
-		mockBookRepo.On("FetchBooks", mock.Anything).Return(mockBooks, nil).Once()
+		mockBookRepo.On("FetchBooks", mock.Anything, mock.Anything).Return(mockBooks, nil).Once()
 
 		u := NewBookUseCase(mockBookRepo)
 
 		// Call the FetchBooks method on the use case
-		books, err := u.FetchBooks(context.Background())
+		books, err := u.FetchBooks(context.Background(), domain.BookFilter{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, books)
@@ -36,7 +43,7 @@ func TestFetchBooks(t *testing.T) {
 func TestCreateBook(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockBookRepo := new(appmock.MockBookRepository)
-		mockBook := &domain.Book{Title: "Test Book", Author: "Test Author", PublicationYear: "2021"}
+		mockBook := &domain.Book{Title: "Test Book", Author: "Test Author", PublicationDate: "2021"}
 
 		mockBookRepo.On("CreateBook", mock.Anything, mock.AnythingOfType("*domain.Book")).Return(nil).Once()
 
@@ -48,13 +55,112 @@ func TestCreateBook(t *testing.T) {
 		assert.NoError(t, err)
 		mockBookRepo.AssertExpectations(t)
 	})
+
+	t.Run("ISBN normalized", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBook := &domain.Book{Title: "Test Book", Author: "Test Author", PublicationDate: "2021", ISBN: "0-13-419044-0"}
+
+		mockBookRepo.On("CreateBook", mock.Anything, mock.MatchedBy(func(b *domain.Book) bool {
+			return b.ISBN == "9780134190440"
+		})).Return(nil).Once()
+
+		u := NewBookUseCase(mockBookRepo)
+
+		err := u.CreateBook(context.Background(), mockBook)
+
+		assert.NoError(t, err)
+		mockBookRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid ISBN", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+
+		u := NewBookUseCase(mockBookRepo)
+
+		err := u.CreateBook(context.Background(), &domain.Book{Title: "Test Book", Author: "Test Author", PublicationDate: "2021", ISBN: "9780134190441"})
+
+		assert.ErrorIs(t, err, &apperror.Error{Type: apperror.BadRequest, Code: "invalid_isbn"})
+		mockBookRepo.AssertNotCalled(t, "CreateBook")
+	})
+}
+
+func TestCreateBook_PublicationDate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		date  domain.PublicationDate
+		grace time.Duration
+		code  string // of the error, none when accepted
+	}{
+		{name: "Past day", date: "1994-05-17"},
+		{name: "Forthcoming within the default grace period", date: "2025-09"},
+		{name: "Beyond the default grace period", date: "3024", code: "publication_date_too_late"},
+		{name: "Beyond a shorter grace period", date: "2024-09", grace: 30 * 24 * time.Hour, code: "publication_date_too_late"},
+		{name: "Within a shorter grace period", date: "2024-06-20", grace: 30 * 24 * time.Hour},
+		{name: "Malformed", date: "abc", code: "invalid_publication_date"},
+		{name: "Too early", date: "1200", code: "invalid_publication_date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBookRepo := new(appmock.MockBookRepository)
+			mockBookRepo.On("CreateBook", mock.Anything, mock.AnythingOfType("*domain.Book")).Return(nil).Maybe()
+
+			var opts []Option
+			if tt.grace != 0 {
+				opts = append(opts, WithPublicationGracePeriod(tt.grace))
+			}
+			u := NewBookUseCase(mockBookRepo, opts...)
+			u.(*bookUseCase).now = func() time.Time { return now }
+
+			err := u.CreateBook(context.Background(), &domain.Book{Title: "Test Book", Author: "Test Author", PublicationDate: tt.date})
+
+			if tt.code == "" {
+				assert.NoError(t, err)
+				mockBookRepo.AssertCalled(t, "CreateBook", mock.Anything, mock.Anything)
+				return
+			}
+			assert.ErrorIs(t, err, &apperror.Error{Type: apperror.BadRequest, Code: tt.code})
+			mockBookRepo.AssertNotCalled(t, "CreateBook")
+		})
+	}
+}
+
+func TestGetBookByISBN(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBook := &domain.Book{ID: uuid.New(), Title: "Test Book", Author: "Test Author", PublicationDate: "2021", ISBN: "9780134190440"}
+
+		mockBookRepo.On("GetBookByISBN", mock.Anything, "9780134190440").Return(mockBook, nil).Once()
+
+		u := NewBookUseCase(mockBookRepo)
+
+		book, err := u.GetBookByISBN(context.Background(), "0-13-419044-0")
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockBook, book)
+		mockBookRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid ISBN", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+
+		u := NewBookUseCase(mockBookRepo)
+
+		_, err := u.GetBookByISBN(context.Background(), "123")
+
+		assert.ErrorIs(t, err, &apperror.Error{Type: apperror.BadRequest, Code: "invalid_isbn"})
+		assert.Equal(t, apperror.Params{"isbn": `"123"`}, err.(*apperror.Error).Params)
+		mockBookRepo.AssertNotCalled(t, "GetBookByISBN")
+	})
 }
 
 func TestGetBookByID(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockBookRepo := new(appmock.MockBookRepository)
 		id := uuid.New()
-		mockBook := &domain.Book{ID: id, Title: "Test Book", Author: "Test Author", PublicationYear: "2021"}
+		mockBook := &domain.Book{ID: id, Title: "Test Book", Author: "Test Author", PublicationDate: "2021"}
 
 		mockBookRepo.On("GetBookByID", mock.Anything, mock.Anything).Return(mockBook, nil)
 
@@ -68,13 +174,132 @@ func TestGetBookByID(t *testing.T) {
 		assert.Equal(t, mockBook.ID, book.ID)
 		mockBookRepo.AssertExpectations(t)
 	})
+
+	t.Run("Series neighbours", func(t *testing.T) {
+		discworld := uuid.New()
+		book := domain.Book{ID: uuid.New(), Title: "Equal Rites", Series: []domain.SeriesEntry{{SeriesID: discworld, Position: 3}}}
+		entries := []domain.SeriesEntry{{SeriesID: discworld, Position: 3, Previous: &domain.SeriesNeighbour{ID: uuid.New(), Title: "The Light Fantastic", Position: 2}}}
+		stored := book
+
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("GetBookByID", mock.Anything, book.ID.String()).Return(&stored, nil).Once()
+		mockBookRepo.On("GetSeriesNeighbours", mock.Anything, book.ID.String()).Return(entries, nil).Once()
+
+		u := NewBookUseCase(mockBookRepo)
+
+		found, err := u.GetBookByID(context.Background(), book.ID.String())
+
+		assert.NoError(t, err)
+		assert.Equal(t, entries, found.Series)
+		// the stored entries are left as they were
+		assert.Nil(t, book.Series[0].Previous)
+		mockBookRepo.AssertExpectations(t)
+	})
+
+	t.Run("Series neighbours of an updated book", func(t *testing.T) {
+		discworld := uuid.New()
+		id := uuid.New()
+		entries := []domain.SeriesEntry{{SeriesID: discworld, Position: 3, Next: &domain.SeriesNeighbour{ID: uuid.New(), Title: "Mort", Position: 4}}}
+
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("UpdateBook", mock.Anything, mock.AnythingOfType("*domain.Book")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Book).ID = id
+		}).Return(nil).Once()
+		mockBookRepo.On("GetSeriesNeighbours", mock.Anything, id.String()).Return(entries, nil).Once()
+
+		u := NewBookUseCase(mockBookRepo)
+
+		book := &domain.Book{Title: "Equal Rites", Author: "Terry Pratchett", PublicationDate: "1987", Series: []domain.SeriesEntry{{SeriesID: discworld, Position: 3}}}
+		err := u.UpdateBook(context.Background(), id.String(), book)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entries, book.Series)
+		mockBookRepo.AssertExpectations(t)
+	})
+}
+
+func TestCreateBook_Series(t *testing.T) {
+	series := uuid.New()
+	unknown := uuid.New()
+
+	tests := []struct {
+		name   string
+		series []domain.SeriesEntry
+		code   string // of the error, none when accepted
+	}{
+		{name: "Known series", series: []domain.SeriesEntry{{SeriesID: series, Position: 2.5}}},
+		{name: "Neighbours sent back", series: []domain.SeriesEntry{{SeriesID: series, Position: 2, Next: &domain.SeriesNeighbour{ID: uuid.New()}}}},
+		{name: "Same series twice", series: []domain.SeriesEntry{{SeriesID: series, Position: 1}, {SeriesID: series, Position: 2}}, code: "duplicate_series"},
+		{name: "Unknown series", series: []domain.SeriesEntry{{SeriesID: unknown, Position: 1}}, code: "unknown_series"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBookRepo := new(appmock.MockBookRepository)
+			mockBookRepo.On("CreateBook", mock.Anything, mock.AnythingOfType("*domain.Book")).Return(nil).Maybe()
+			mockSeriesRepo := new(appmock.MockSeriesRepository)
+			mockSeriesRepo.On("GetSeriesByID", mock.Anything, series.String()).Return(&domain.Series{ID: series}, nil).Maybe()
+			mockSeriesRepo.On("GetSeriesByID", mock.Anything, unknown.String()).Return((*domain.Series)(nil), apperror.NewNotFound("Series", "ID", unknown.String())).Maybe()
+
+			u := NewBookUseCase(mockBookRepo, WithSeriesRepository(mockSeriesRepo))
+
+			book := &domain.Book{Title: "Test Book", Author: "Test Author", PublicationDate: "2015", Series: tt.series}
+			err := u.CreateBook(context.Background(), book)
+
+			if tt.code == "" {
+				assert.NoError(t, err)
+				stored := mockBookRepo.Calls[0].Arguments.Get(1).(*domain.Book)
+				assert.Nil(t, stored.Series[0].Next)
+				return
+			}
+			assert.ErrorIs(t, err, &apperror.Error{Type: apperror.BadRequest, Code: tt.code})
+			mockBookRepo.AssertNotCalled(t, "CreateBook", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateBook_Work(t *testing.T) {
+	work := uuid.New()
+	unknown := uuid.New()
+
+	tests := []struct {
+		name string
+		work *uuid.UUID
+		code string // of the error, none when accepted
+	}{
+		{name: "No work"},
+		{name: "Known work", work: &work},
+		{name: "Unknown work", work: &unknown, code: "unknown_work"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBookRepo := new(appmock.MockBookRepository)
+			mockBookRepo.On("CreateBook", mock.Anything, mock.AnythingOfType("*domain.Book")).Return(nil).Maybe()
+			mockWorkRepo := new(appmock.MockWorkRepository)
+			mockWorkRepo.On("GetWorkByID", mock.Anything, work.String()).Return(&domain.Work{ID: work}, nil).Maybe()
+			mockWorkRepo.On("GetWorkByID", mock.Anything, unknown.String()).Return((*domain.Work)(nil), apperror.NewNotFound("Work", "ID", unknown.String())).Maybe()
+
+			u := NewBookUseCase(mockBookRepo, WithWorkRepository(mockWorkRepo))
+
+			book := &domain.Book{Title: "Test Book", Author: "Test Author", PublicationDate: "2015", WorkID: tt.work}
+			err := u.CreateBook(context.Background(), book)
+
+			if tt.code == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, &apperror.Error{Type: apperror.BadRequest, Code: tt.code})
+			mockBookRepo.AssertNotCalled(t, "CreateBook", mock.Anything, mock.Anything)
+		})
+	}
 }
 
 func TestUpdateBook(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockBookRepo := new(appmock.MockBookRepository)
 		id := uuid.New()
-		mockBook := &domain.Book{ID: id, Title: "Updated Book", Author: "Updated Author", PublicationYear: "2022"}
+		mockBook := &domain.Book{ID: id, Title: "Updated Book", Author: "Updated Author", PublicationDate: "2022"}
 
 		mockBookRepo.On("UpdateBook", mock.Anything, mock.AnythingOfType("*domain.Book")).Return(nil).Once()
 
@@ -93,7 +318,7 @@ func TestDeleteBook(t *testing.T) {
 		mockBookRepo := new(appmock.MockBookRepository)
 		mockBookID := "1"
 
-		mockBookRepo.On("DeleteBook", mock.Anything, mockBookID).Return(nil).Once()
+		mockBookRepo.On("DeleteBook", mock.Anything, mockBookID, domain.ActorAnonymous).Return(nil).Once()
 
 		u := NewBookUseCase(mockBookRepo)
 
@@ -103,4 +328,306 @@ func TestDeleteBook(t *testing.T) {
 		assert.NoError(t, err)
 		mockBookRepo.AssertExpectations(t)
 	})
+
+	t.Run("Records the actor", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("DeleteBook", mock.Anything, "1", "alice").Return(nil).Once()
+
+		u := NewBookUseCase(mockBookRepo)
+
+		err := u.DeleteBook(as(&domain.Principal{Subject: "alice", Method: domain.AuthMethodJWT}), "1")
+
+		assert.NoError(t, err)
+		mockBookRepo.AssertExpectations(t)
+	})
+
+	copiesTests := []struct {
+		name   string
+		status []domain.CopyStatus
+		active bool // whether the delete is refused for active copies
+	}{
+		{name: "Without copies"},
+		{name: "With lost copies only", status: []domain.CopyStatus{domain.CopyLost}},
+		{name: "With a copy on loan", status: []domain.CopyStatus{domain.CopyLost, domain.CopyOnLoan}, active: true},
+		{name: "With a copy in repair", status: []domain.CopyStatus{domain.CopyRepair}, active: true},
+	}
+
+	for _, tt := range copiesTests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := uuid.New()
+			counts := map[uuid.UUID]domain.CopyCounts{}
+			for _, s := range tt.status {
+				n := counts[id]
+				n.Add(&domain.Copy{BookID: id, Status: s})
+				counts[id] = n
+			}
+			mockBookRepo := new(appmock.MockBookRepository)
+			mockBookRepo.On("DeleteBook", mock.Anything, id.String(), domain.ActorAnonymous).Return(nil).Maybe()
+			mockCopyRepo := new(appmock.MockCopyRepository)
+			mockCopyRepo.On("CountCopies", mock.Anything, []uuid.UUID{id}).Return(counts, nil).Once()
+
+			u := NewBookUseCase(mockBookRepo, WithCopyRepository(mockCopyRepo))
+
+			err := u.DeleteBook(context.Background(), id.String())
+
+			if !tt.active {
+				assert.NoError(t, err)
+				mockBookRepo.AssertExpectations(t)
+				return
+			}
+			assert.ErrorIs(t, err, &apperror.Error{Type: apperror.Conflict, Code: "active_copies"})
+			mockBookRepo.AssertNotCalled(t, "DeleteBook", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBookCopies(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	counts := map[uuid.UUID]domain.CopyCounts{first: {Available: 1, Total: 2}}
+
+	t.Run("Fetch", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("FetchBooks", mock.Anything, domain.BookFilter{}).Return(&[]domain.Book{{ID: first}, {ID: second}}, nil).Once()
+		mockCopyRepo := new(appmock.MockCopyRepository)
+		// only the copies of the listed books are counted
+		mockCopyRepo.On("CountCopies", mock.Anything, []uuid.UUID{first, second}).Return(counts, nil).Once()
+
+		u := NewBookUseCase(mockBookRepo, WithCopyRepository(mockCopyRepo))
+
+		books, err := u.FetchBooks(context.Background(), domain.BookFilter{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.CopyCounts{Available: 1, Total: 2}, (*books)[0].Copies)
+		assert.Equal(t, &domain.CopyCounts{}, (*books)[1].Copies)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("GetBookByID", mock.Anything, first.String()).Return(&domain.Book{ID: first}, nil).Once()
+		mockCopyRepo := new(appmock.MockCopyRepository)
+		mockCopyRepo.On("CountCopies", mock.Anything, []uuid.UUID{first}).Return(counts, nil).Once()
+
+		u := NewBookUseCase(mockBookRepo, WithCopyRepository(mockCopyRepo))
+
+		book, err := u.GetBookByID(context.Background(), first.String())
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.CopyCounts{Available: 1, Total: 2}, book.Copies)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		var stored *domain.CopyCounts
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("UpdateBook", mock.Anything, mock.AnythingOfType("*domain.Book")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.Book).Copies
+		}).Return(nil).Once()
+		mockCopyRepo := new(appmock.MockCopyRepository)
+		mockCopyRepo.On("CountCopies", mock.Anything, []uuid.UUID{first}).Return(counts, nil).Once()
+
+		u := NewBookUseCase(mockBookRepo, WithCopyRepository(mockCopyRepo))
+
+		book := &domain.Book{ID: first, Title: "Test Book", Author: "Test Author", PublicationDate: "2021", Copies: &domain.CopyCounts{Available: 9, Total: 9}}
+		err := u.UpdateBook(context.Background(), first.String(), book)
+
+		assert.NoError(t, err)
+		// not stored, but counted in the response
+		assert.Nil(t, stored)
+		assert.Equal(t, &domain.CopyCounts{Available: 1, Total: 2}, book.Copies)
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("FetchTrash", mock.Anything).Return(&[]domain.TrashedBook{}, nil).Once()
+		mockBookRepo.On("RestoreBook", mock.Anything, first.String()).Return(&domain.Book{ID: first}, nil).Once()
+		mockCopyRepo := new(appmock.MockCopyRepository)
+		mockCopyRepo.On("CountCopies", mock.Anything, []uuid.UUID{first}).Return(counts, nil).Once()
+
+		u := NewBookUseCase(mockBookRepo, WithCopyRepository(mockCopyRepo))
+
+		book, err := u.RestoreBook(context.Background(), first.String())
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.CopyCounts{Available: 1, Total: 2}, book.Copies)
+	})
+
+	t.Run("Revert", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("FetchBookHistory", mock.Anything, first.String()).Return(&[]domain.BookRevision{}, nil).Once()
+		mockBookRepo.On("RevertBook", mock.Anything, first.String(), 1).Return(&domain.Book{ID: first}, nil).Once()
+		mockCopyRepo := new(appmock.MockCopyRepository)
+		mockCopyRepo.On("CountCopies", mock.Anything, []uuid.UUID{first}).Return(counts, nil).Once()
+
+		u := NewBookUseCase(mockBookRepo, WithCopyRepository(mockCopyRepo))
+
+		book, err := u.RevertBook(context.Background(), first.String(), 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.CopyCounts{Available: 1, Total: 2}, book.Copies)
+	})
+}
+
+func TestRestoreBook(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBook := &domain.Book{ID: uuid.New(), Title: "Test Book", Author: "Test Author", PublicationDate: "2021"}
+		mockBookRepo.On("FetchTrash", mock.Anything).Return(&[]domain.TrashedBook{{Book: *mockBook}}, nil).Once()
+		mockBookRepo.On("RestoreBook", mock.Anything, mockBook.ID.String()).Return(mockBook, nil).Once()
+
+		u := NewBookUseCase(mockBookRepo)
+
+		book, err := u.RestoreBook(context.Background(), mockBook.ID.String())
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockBook, book)
+		mockBookRepo.AssertExpectations(t)
+	})
+
+	t.Run("Deleted publisher", func(t *testing.T) {
+		publisherID := uuid.New()
+		trashed := domain.Book{ID: uuid.New(), Title: "Test Book", Author: "Test Author", PublicationDate: "2021", PublisherID: &publisherID}
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("FetchTrash", mock.Anything).Return(&[]domain.TrashedBook{{Book: trashed}}, nil).Once()
+		mockPublisherRepo := new(appmock.MockPublisherRepository)
+		mockPublisherRepo.On("GetPublisherByID", mock.Anything, publisherID.String()).Return((*domain.Publisher)(nil), apperror.NewNotFound("Publisher", "ID", publisherID.String())).Once()
+
+		u := NewBookUseCase(mockBookRepo, WithPublisherRepository(mockPublisherRepo))
+
+		_, err := u.RestoreBook(context.Background(), trashed.ID.String())
+
+		assert.ErrorIs(t, err, &apperror.Error{Type: apperror.BadRequest, Code: "unknown_publisher"})
+		mockBookRepo.AssertNotCalled(t, "RestoreBook", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Requires the delete permission", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+
+		u := NewBookUseCase(mockBookRepo, WithAuthorizer(NewRBAC(DefaultPolicy())))
+		editor := as(&domain.Principal{Subject: "editor", Method: domain.AuthMethodJWT, Roles: []string{domain.RoleEditor}})
+
+		_, err := u.RestoreBook(editor, "1")
+		assert.Equal(t, http.StatusForbidden, apperror.Status(err))
+
+		_, err = u.FetchTrash(editor)
+		assert.Equal(t, http.StatusForbidden, apperror.Status(err))
+
+		mockBookRepo.AssertNotCalled(t, "RestoreBook")
+		mockBookRepo.AssertNotCalled(t, "FetchTrash")
+	})
+}
+
+func TestHasTrashedBooks(t *testing.T) {
+	workID := uuid.New()
+	mockBookRepo := new(appmock.MockBookRepository)
+	mockBookRepo.On("FetchTrash", mock.Anything).Return(&[]domain.TrashedBook{{Book: domain.Book{ID: uuid.New(), WorkID: &workID}}}, nil)
+
+	u := NewBookUseCase(mockBookRepo, WithAuthorizer(NewRBAC(DefaultPolicy())))
+	// the trash is not listed, reading the books is enough
+	viewer := as(&domain.Principal{Subject: "viewer", Method: domain.AuthMethodJWT, Roles: []string{domain.RoleViewer}})
+
+	found, err := u.HasTrashedBooks(viewer, domain.BookFilter{WorkID: workID})
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	found, err = u.HasTrashedBooks(viewer, domain.BookFilter{WorkID: uuid.New()})
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestWithTracerProvider(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		sr := tracetest.NewSpanRecorder()
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("DeleteBook", mock.Anything, "1", domain.ActorAnonymous).Return(nil).Once()
+
+		u := NewBookUseCase(mockBookRepo, WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))))
+
+		assert.NoError(t, u.DeleteBook(context.Background(), "1"))
+
+		spans := sr.Ended()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "bookUseCase.DeleteBook", spans[0].Name())
+		assert.Contains(t, spans[0].Attributes(), attribute.String("book.id", "1"))
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		sr := tracetest.NewSpanRecorder()
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("GetBookByID", mock.Anything, "1").Return((*domain.Book)(nil), apperror.NewNotFound("Book", "ID", "1")).Once()
+
+		u := NewBookUseCase(mockBookRepo, WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))))
+
+		_, err := u.GetBookByID(context.Background(), "1")
+		assert.Error(t, err)
+
+		spans := sr.Ended()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Equal(t, string(apperror.NotFound), spans[0].Status().Description)
+	})
+}
+
+func TestRevertBook(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBook := &domain.Book{ID: uuid.New(), Title: "Test Book", Author: "Test Author", PublicationDate: "2021"}
+		mockBookRepo.On("FetchBookHistory", mock.Anything, mockBook.ID.String()).Return(&[]domain.BookRevision{{Revision: 1}, {Revision: 2, Book: *mockBook}}, nil).Once()
+		mockBookRepo.On("RevertBook", mock.Anything, mockBook.ID.String(), 2).Return(mockBook, nil).Once()
+
+		u := NewBookUseCase(mockBookRepo)
+
+		book, err := u.RevertBook(context.Background(), mockBook.ID.String(), 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockBook, book)
+		mockBookRepo.AssertExpectations(t)
+	})
+
+	t.Run("Deleted series", func(t *testing.T) {
+		seriesID := uuid.New()
+		revision := domain.Book{ID: uuid.New(), Title: "Test Book", Author: "Test Author", PublicationDate: "2021", Series: []domain.SeriesEntry{{SeriesID: seriesID, Position: 1}}}
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("FetchBookHistory", mock.Anything, revision.ID.String()).Return(&[]domain.BookRevision{{Revision: 1, Book: revision}, {Revision: 2}}, nil).Once()
+		mockSeriesRepo := new(appmock.MockSeriesRepository)
+		mockSeriesRepo.On("GetSeriesByID", mock.Anything, seriesID.String()).Return((*domain.Series)(nil), apperror.NewNotFound("Series", "ID", seriesID.String())).Once()
+
+		u := NewBookUseCase(mockBookRepo, WithSeriesRepository(mockSeriesRepo))
+
+		_, err := u.RevertBook(context.Background(), revision.ID.String(), 1)
+
+		assert.ErrorIs(t, err, &apperror.Error{Type: apperror.BadRequest, Code: "unknown_series"})
+		mockBookRepo.AssertNotCalled(t, "RevertBook", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Requires the write permission", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+
+		u := NewBookUseCase(mockBookRepo, WithAuthorizer(NewRBAC(DefaultPolicy())))
+
+		_, err := u.RevertBook(as(&domain.Principal{Subject: "viewer", Method: domain.AuthMethodJWT, Roles: []string{domain.RoleViewer}}), "1", 1)
+
+		assert.Equal(t, http.StatusForbidden, apperror.Status(err))
+		mockBookRepo.AssertNotCalled(t, "RevertBook")
+	})
+}
+
+func TestGetBookAsOf(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		mockBook := &domain.Book{ID: uuid.New(), Title: "Test Book", Author: "Test Author", PublicationDate: "2021"}
+		mockBookRepo.On("GetBookAsOf", mock.Anything, mockBook.ID.String(), at).Return(mockBook, nil).Once()
+		mockBookRepo.On("FetchBookHistory", mock.Anything, mockBook.ID.String()).Return(&[]domain.BookRevision{{Revision: 1, Book: *mockBook}}, nil).Once()
+
+		u := NewBookUseCase(mockBookRepo)
+
+		book, err := u.GetBookAsOf(context.Background(), mockBook.ID.String(), at)
+		assert.NoError(t, err)
+		assert.Equal(t, mockBook, book)
+
+		history, err := u.FetchBookHistory(context.Background(), mockBook.ID.String())
+		assert.NoError(t, err)
+		assert.Len(t, *history, 1)
+		mockBookRepo.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/apptrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// copyIDKey is the span attribute of the copy an operation is about
+const copyIDKey = attribute.Key("copy.id")
+
+type copyUseCase struct {
+	options
+	copyRepository domain.CopyRepository
+	bookUseCase    domain.BookUseCase
+}
+
+// NewCopyUseCase manages the copies of copyRepository, the books they are
+// copies of are read through bu, so copies of a trashed book are not
+// found by their book
+func NewCopyUseCase(copyRepository domain.CopyRepository, bu domain.BookUseCase, opts ...Option) domain.CopyUseCase {
+	return &copyUseCase{
+		options:        newOptions(opts),
+		copyRepository: copyRepository,
+		bookUseCase:    bu,
+	}
+}
+
+func (c *copyUseCase) FetchCopies(ctx context.Context, bookID string, filter domain.CopyFilter) (copies *[]domain.Copy, err error) {
+	ctx, span := c.tracer.Start(ctx, "copyUseCase.FetchCopies", trace.WithAttributes(bookIDKey.String(bookID)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := c.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	book, err := c.bookUseCase.GetBookByID(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+	filter.BookID = book.ID
+
+	return c.copyRepository.FetchCopies(ctx, filter)
+}
+
+func (c *copyUseCase) GetCopyByID(ctx context.Context, bookID string, id string) (cp *domain.Copy, err error) {
+	ctx, span := c.tracer.Start(ctx, "copyUseCase.GetCopyByID", trace.WithAttributes(bookIDKey.String(bookID), copyIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := c.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	return c.getCopy(ctx, bookID, id)
+}
+
+func (c *copyUseCase) GetCopyByBarcode(ctx context.Context, barcode string) (cp *domain.Copy, err error) {
+	ctx, span := c.tracer.Start(ctx, "copyUseCase.GetCopyByBarcode", trace.WithAttributes(attribute.String("copy.barcode", barcode)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := c.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	return c.copyRepository.GetCopyByBarcode(ctx, strings.TrimSpace(barcode))
+}
+
+func (c *copyUseCase) CreateCopy(ctx context.Context, bookID string, cp *domain.Copy) (err error) {
+	ctx, span := c.tracer.Start(ctx, "copyUseCase.CreateCopy", trace.WithAttributes(bookIDKey.String(bookID)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := c.authorize(ctx, domain.PermissionBooksWrite); err != nil {
+		return err
+	}
+
+	book, err := c.bookUseCase.GetBookByID(ctx, bookID)
+	if err != nil {
+		return err
+	}
+	cp.BookID = book.ID
+	if err := c.checkCopy(cp); err != nil {
+		return err
+	}
+
+	if err := c.copyRepository.CreateCopy(ctx, cp); err != nil {
+		return err
+	}
+
+	span.SetAttributes(copyIDKey.String(cp.ID.String()))
+	slog.InfoContext(ctx, "copy created", "book_id", bookID, "copy_id", cp.ID.String())
+	return nil
+}
+
+func (c *copyUseCase) UpdateCopy(ctx context.Context, bookID string, id string, cp *domain.Copy) (err error) {
+	ctx, span := c.tracer.Start(ctx, "copyUseCase.UpdateCopy", trace.WithAttributes(bookIDKey.String(bookID), copyIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := c.authorize(ctx, domain.PermissionBooksWrite); err != nil {
+		return err
+	}
+
+	stored, err := c.getCopy(ctx, bookID, id)
+	if err != nil {
+		return err
+	}
+	cp.BookID = stored.BookID
+	if err := c.checkCopy(cp); err != nil {
+		return err
+	}
+
+	if err := c.copyRepository.UpdateCopy(ctx, id, cp); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "copy updated", "book_id", bookID, "copy_id", id, "status", string(cp.Status))
+	return nil
+}
+
+func (c *copyUseCase) DeleteCopy(ctx context.Context, bookID string, id string) (err error) {
+	ctx, span := c.tracer.Start(ctx, "copyUseCase.DeleteCopy", trace.WithAttributes(bookIDKey.String(bookID), copyIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := c.authorize(ctx, domain.PermissionBooksDelete); err != nil {
+		return err
+	}
+
+	if _, err := c.getCopy(ctx, bookID, id); err != nil {
+		return err
+	}
+	if err := c.copyRepository.DeleteCopy(ctx, id); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "copy deleted", "book_id", bookID, "copy_id", id)
+	return nil
+}
+
+// getCopy returns the copy id of the book bookID, a copy of another book
+// is not found
+func (c *copyUseCase) getCopy(ctx context.Context, bookID string, id string) (*domain.Copy, error) {
+	book, err := c.bookUseCase.GetBookByID(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+	cp, err := c.copyRepository.GetCopyByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cp.BookID != book.ID {
+		return nil, apperror.NewNotFound("Copy", "ID", id)
+	}
+	return cp, nil
+}
+
+// checkCopy trims the barcode, makes a copy without a status available
+// and rejects an acquisition date which is invalid or in the future
+func (c *copyUseCase) checkCopy(cp *domain.Copy) error {
+	cp.Barcode = strings.TrimSpace(cp.Barcode)
+	if cp.Barcode == "" {
+		return apperror.NewBadRequestCode("barcode_required", apperror.Params{})
+	}
+
+	if cp.Status == "" {
+		cp.Status = domain.CopyAvailable
+	}
+	if !cp.Status.Valid() {
+		return apperror.NewBadRequestCode("invalid_copy_status", apperror.Params{"status": strconv.Quote(string(cp.Status))})
+	}
+
+	if cp.AcquisitionDate == "" {
+		return nil
+	}
+	acquired, err := time.Parse(time.DateOnly, cp.AcquisitionDate)
+	if err != nil {
+		return apperror.NewBadRequestCode("invalid_acquisition_date", apperror.Params{"date": strconv.Quote(cp.AcquisitionDate)})
+	}
+	if acquired.After(c.now()) {
+		return apperror.NewBadRequestCode("acquisition_date_too_late", apperror.Params{"date": cp.AcquisitionDate})
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateCopy(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	book := &domain.Book{ID: uuid.New(), Title: "Dune"}
+
+	tests := []struct {
+		name   string
+		cp     domain.Copy
+		status domain.CopyStatus
+		code   string // of the error, none when accepted
+	}{
+		{name: "Available by default", cp: domain.Copy{Barcode: " B001 "}, status: domain.CopyAvailable},
+		{name: "With a status", cp: domain.Copy{Barcode: "B001", Status: domain.CopyRepair}, status: domain.CopyRepair},
+		{name: "Acquired today", cp: domain.Copy{Barcode: "B001", AcquisitionDate: "2024-06-01"}, status: domain.CopyAvailable},
+		{name: "Acquired tomorrow", cp: domain.Copy{Barcode: "B001", AcquisitionDate: "2024-06-02"}, code: "acquisition_date_too_late"},
+		{name: "Malformed acquisition date", cp: domain.Copy{Barcode: "B001", AcquisitionDate: "2024-6-1"}, code: "invalid_acquisition_date"},
+		{name: "Unknown status", cp: domain.Copy{Barcode: "B001", Status: "borrowed"}, code: "invalid_copy_status"},
+		{name: "Blank barcode", cp: domain.Copy{Barcode: "  "}, code: "barcode_required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCopyRepo := new(appmock.MockCopyRepository)
+			mockCopyRepo.On("CreateCopy", mock.Anything, mock.AnythingOfType("*domain.Copy")).Return(nil).Maybe()
+			mockBookUseCase := new(appmock.MockBookUseCase)
+			mockBookUseCase.On("GetBookByID", mock.Anything, book.ID.String()).Return(book, nil)
+
+			u := NewCopyUseCase(mockCopyRepo, mockBookUseCase)
+			u.(*copyUseCase).now = func() time.Time { return now }
+
+			cp := tt.cp
+			err := u.CreateCopy(context.Background(), book.ID.String(), &cp)
+
+			if tt.code == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, book.ID, cp.BookID)
+				assert.Equal(t, "B001", cp.Barcode)
+				assert.Equal(t, tt.status, cp.Status)
+				mockCopyRepo.AssertCalled(t, "CreateCopy", mock.Anything, &cp)
+				return
+			}
+			assert.ErrorIs(t, err, &apperror.Error{Type: apperror.BadRequest, Code: tt.code})
+			mockCopyRepo.AssertNotCalled(t, "CreateCopy", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("Unknown book", func(t *testing.T) {
+		id := uuid.NewString()
+		mockCopyRepo := new(appmock.MockCopyRepository)
+		mockBookUseCase := new(appmock.MockBookUseCase)
+		mockBookUseCase.On("GetBookByID", mock.Anything, id).Return((*domain.Book)(nil), apperror.NewNotFound("Book", "ID", id))
+
+		u := NewCopyUseCase(mockCopyRepo, mockBookUseCase)
+
+		err := u.CreateCopy(context.Background(), id, &domain.Copy{Barcode: "B001"})
+
+		assert.Equal(t, http.StatusNotFound, apperror.Status(err))
+		mockCopyRepo.AssertNotCalled(t, "CreateCopy", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFetchCopies(t *testing.T) {
+	book := &domain.Book{ID: uuid.New(), Title: "Dune"}
+	copies := []domain.Copy{{ID: uuid.New(), BookID: book.ID, Barcode: "B001", Status: domain.CopyOnLoan}}
+	mockCopyRepo := new(appmock.MockCopyRepository)
+	mockCopyRepo.On("FetchCopies", mock.Anything, domain.CopyFilter{BookID: book.ID, Status: domain.CopyOnLoan}).Return(&copies, nil).Once()
+	mockBookUseCase := new(appmock.MockBookUseCase)
+	mockBookUseCase.On("GetBookByID", mock.Anything, book.ID.String()).Return(book, nil).Once()
+
+	u := NewCopyUseCase(mockCopyRepo, mockBookUseCase)
+
+	// a filter naming another book is narrowed to the book
+	found, err := u.FetchCopies(context.Background(), book.ID.String(), domain.CopyFilter{BookID: uuid.New(), Status: domain.CopyOnLoan})
+
+	assert.NoError(t, err)
+	assert.Equal(t, copies, *found)
+}
+
+func TestCopyOfAnotherBook(t *testing.T) {
+	book := &domain.Book{ID: uuid.New(), Title: "Dune"}
+	other := &domain.Copy{ID: uuid.New(), BookID: uuid.New(), Barcode: "B001", Status: domain.CopyAvailable}
+
+	newUseCase := func() (domain.CopyUseCase, *appmock.MockCopyRepository) {
+		mockCopyRepo := new(appmock.MockCopyRepository)
+		mockCopyRepo.On("GetCopyByID", mock.Anything, other.ID.String()).Return(other, nil)
+		mockBookUseCase := new(appmock.MockBookUseCase)
+		mockBookUseCase.On("GetBookByID", mock.Anything, book.ID.String()).Return(book, nil)
+		return NewCopyUseCase(mockCopyRepo, mockBookUseCase), mockCopyRepo
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		u, _ := newUseCase()
+
+		_, err := u.GetCopyByID(context.Background(), book.ID.String(), other.ID.String())
+
+		assert.Equal(t, http.StatusNotFound, apperror.Status(err))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		u, mockCopyRepo := newUseCase()
+
+		err := u.UpdateCopy(context.Background(), book.ID.String(), other.ID.String(), &domain.Copy{Barcode: "B001", Status: domain.CopyLost})
+
+		assert.Equal(t, http.StatusNotFound, apperror.Status(err))
+		mockCopyRepo.AssertNotCalled(t, "UpdateCopy", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		u, mockCopyRepo := newUseCase()
+
+		err := u.DeleteCopy(context.Background(), book.ID.String(), other.ID.String())
+
+		assert.Equal(t, http.StatusNotFound, apperror.Status(err))
+		mockCopyRepo.AssertNotCalled(t, "DeleteCopy", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateCopy(t *testing.T) {
+	book := &domain.Book{ID: uuid.New(), Title: "Dune"}
+	stored := &domain.Copy{ID: uuid.New(), BookID: book.ID, Barcode: "B001", Status: domain.CopyAvailable}
+	mockCopyRepo := new(appmock.MockCopyRepository)
+	mockCopyRepo.On("GetCopyByID", mock.Anything, stored.ID.String()).Return(stored, nil).Once()
+	mockCopyRepo.On("UpdateCopy", mock.Anything, stored.ID.String(), mock.AnythingOfType("*domain.Copy")).Return(nil).Once()
+	mockBookUseCase := new(appmock.MockBookUseCase)
+	mockBookUseCase.On("GetBookByID", mock.Anything, book.ID.String()).Return(book, nil).Once()
+
+	u := NewCopyUseCase(mockCopyRepo, mockBookUseCase)
+
+	cp := &domain.Copy{BookID: uuid.New(), Barcode: "B001", Status: domain.CopyOnLoan}
+	err := u.UpdateCopy(context.Background(), book.ID.String(), stored.ID.String(), cp)
+
+	assert.NoError(t, err)
+	// a copy stays a copy of its book
+	assert.Equal(t, book.ID, cp.BookID)
+	mockCopyRepo.AssertExpectations(t)
+}
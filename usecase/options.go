@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apptrace"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option configures optional parts of the use cases
+type Option func(*options)
+
+type options struct {
+	authorizer          domain.Authorizer
+	tracer              trace.Tracer
+	publicationGrace    time.Duration
+	now                 func() time.Time
+	authorRepository    domain.AuthorRepository
+	publisherRepository domain.PublisherRepository
+	seriesRepository    domain.SeriesRepository
+	workRepository      domain.WorkRepository
+	copyRepository      domain.CopyRepository
+}
+
+// WithAuthorizer checks the permission of the caller before every
+// operation, without it every caller is allowed
+func WithAuthorizer(a domain.Authorizer) Option {
+	return func(o *options) {
+		o.authorizer = a
+	}
+}
+
+// WithTracerProvider traces the operations with tp instead of the
+// global provider
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracer = tp.Tracer(apptrace.InstrumentationName)
+	}
+}
+
+// WithPublicationGracePeriod allows books to be dated up to d in the
+// future instead of domain.DefaultPublicationGracePeriod
+func WithPublicationGracePeriod(d time.Duration) Option {
+	return func(o *options) {
+		o.publicationGrace = d
+	}
+}
+
+// WithAuthorRepository checks the contributors of the books created or
+// updated are authors of r, without it they are not checked
+func WithAuthorRepository(r domain.AuthorRepository) Option {
+	return func(o *options) {
+		o.authorRepository = r
+	}
+}
+
+// WithPublisherRepository checks the publishers of the books created or
+// updated are publishers of r, without it they are not checked
+func WithPublisherRepository(r domain.PublisherRepository) Option {
+	return func(o *options) {
+		o.publisherRepository = r
+	}
+}
+
+// WithSeriesRepository checks the series of the books created or updated
+// are series of r, without it they are not checked
+func WithSeriesRepository(r domain.SeriesRepository) Option {
+	return func(o *options) {
+		o.seriesRepository = r
+	}
+}
+
+// WithWorkRepository checks the works of the books created or updated
+// are works of r, without it they are not checked
+func WithWorkRepository(r domain.WorkRepository) Option {
+	return func(o *options) {
+		o.workRepository = r
+	}
+}
+
+// WithCopyRepository counts the copies of r in the books read and keeps
+// books with active copies from being deleted, without it books have no
+// copies
+func WithCopyRepository(r domain.CopyRepository) Option {
+	return func(o *options) {
+		o.copyRepository = r
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		tracer:           otel.Tracer(apptrace.InstrumentationName),
+		publicationGrace: domain.DefaultPublicationGracePeriod,
+		now:              time.Now,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+func (o options) authorize(ctx context.Context, permission string) error {
+	if o.authorizer == nil {
+		return nil
+	}
+	return o.authorizer.Authorize(ctx, permission)
+}
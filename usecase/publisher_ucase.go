@@ -0,0 +1,265 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/apptrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// publisherIDKey is the span attribute of the publisher an operation is
+// about
+const publisherIDKey = attribute.Key("publisher.id")
+
+type publisherUseCase struct {
+	options
+	publisherRepository domain.PublisherRepository
+	bookUseCase         domain.BookUseCase
+}
+
+// NewPublisherUseCase manages the publishers of publisherRepository, the
+// books of a publisher are read and, by a cascading delete, trashed
+// through bu
+func NewPublisherUseCase(publisherRepository domain.PublisherRepository, bu domain.BookUseCase, opts ...Option) domain.PublisherUseCase {
+	return &publisherUseCase{
+		options:             newOptions(opts),
+		publisherRepository: publisherRepository,
+		bookUseCase:         bu,
+	}
+}
+
+func (p *publisherUseCase) FetchPublishers(ctx context.Context, filter domain.PublisherFilter) (publishers *[]domain.Publisher, err error) {
+	ctx, span := p.tracer.Start(ctx, "publisherUseCase.FetchPublishers")
+	defer func() { apptrace.End(span, err) }()
+
+	if err := p.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	return p.publisherRepository.FetchPublishers(ctx, filter)
+}
+
+func (p *publisherUseCase) GetPublisherByID(ctx context.Context, id string) (publisher *domain.Publisher, err error) {
+	ctx, span := p.tracer.Start(ctx, "publisherUseCase.GetPublisherByID", trace.WithAttributes(publisherIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := p.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	return p.publisherRepository.GetPublisherByID(ctx, id)
+}
+
+func (p *publisherUseCase) CreatePublisher(ctx context.Context, publisher *domain.Publisher) (err error) {
+	ctx, span := p.tracer.Start(ctx, "publisherUseCase.CreatePublisher")
+	defer func() { apptrace.End(span, err) }()
+
+	if err := p.authorize(ctx, domain.PermissionBooksWrite); err != nil {
+		return err
+	}
+
+	if err := p.checkParent(ctx, uuid.Nil, publisher); err != nil {
+		return err
+	}
+	if err := p.publisherRepository.CreatePublisher(ctx, publisher); err != nil {
+		return err
+	}
+
+	span.SetAttributes(publisherIDKey.String(publisher.ID.String()))
+	slog.InfoContext(ctx, "publisher created", "publisher_id", publisher.ID.String())
+	return nil
+}
+
+func (p *publisherUseCase) UpdatePublisher(ctx context.Context, id string, publisher *domain.Publisher) (err error) {
+	ctx, span := p.tracer.Start(ctx, "publisherUseCase.UpdatePublisher", trace.WithAttributes(publisherIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := p.authorize(ctx, domain.PermissionBooksWrite); err != nil {
+		return err
+	}
+
+	existing, err := p.publisherRepository.GetPublisherByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := p.checkParent(ctx, existing.ID, publisher); err != nil {
+		return err
+	}
+	if err := p.publisherRepository.UpdatePublisher(ctx, id, publisher); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "publisher updated", "publisher_id", id)
+	return nil
+}
+
+func (p *publisherUseCase) DeletePublisher(ctx context.Context, id string, cascade bool) (err error) {
+	ctx, span := p.tracer.Start(ctx, "publisherUseCase.DeletePublisher", trace.WithAttributes(publisherIDKey.String(id), attribute.Bool("cascade", cascade)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := p.authorize(ctx, domain.PermissionBooksDelete); err != nil {
+		return err
+	}
+
+	publisher, err := p.publisherRepository.GetPublisherByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	// the publisher alone, or with its imprints when cascading
+	tree := []uuid.UUID{publisher.ID}
+	if cascade {
+		if tree, err = p.imprintTree(ctx, publisher.ID); err != nil {
+			return err
+		}
+	} else {
+		imprints, err := p.publisherRepository.FetchPublishers(ctx, domain.PublisherFilter{ParentID: publisher.ID})
+		if err != nil {
+			return err
+		}
+		if len(*imprints) > 0 {
+			return apperror.NewConflictCode("imprints_of_publisher", apperror.Params{"publisher": publisher.Name})
+		}
+	}
+
+	// everything is checked before the first change, so a delete which
+	// fails leaves the catalog as it was
+	var books []domain.Book
+	for _, publisherID := range tree {
+		found, err := listBooks(ctx, p.bookUseCase, domain.BookFilter{PublisherID: publisherID})
+		if err != nil {
+			return err
+		}
+		books = append(books, found...)
+	}
+	if len(books) > 0 && !cascade {
+		return apperror.NewConflictCode("books_of_publisher", apperror.Params{"publisher": publisher.Name})
+	}
+	for _, book := range books {
+		if book.Copies != nil && book.Copies.Total > 0 {
+			return apperror.NewConflictCode("active_copies", apperror.Params{"book_id": book.ID})
+		}
+	}
+	// a trashed book may be restored, keep its publisher
+	trash, err := p.bookUseCase.FetchTrash(ctx)
+	if err != nil {
+		return err
+	}
+	ids := publisherSet(tree)
+	for _, book := range *trash {
+		if book.PublisherID != nil && ids[*book.PublisherID] {
+			return apperror.NewConflictCode("trashed_books_of_publisher", apperror.Params{"publisher": publisher.Name})
+		}
+	}
+
+	// the books are changed through the book use case, which checks the
+	// permissions again and audits each of them. Their publisher is
+	// detached first so none is left in the trash
+	for _, book := range books {
+		book.PublisherID = nil
+		if err := p.bookUseCase.UpdateBook(ctx, book.ID.String(), &book); err != nil {
+			return err
+		}
+		if err := p.bookUseCase.DeleteBook(ctx, book.ID.String()); err != nil {
+			return err
+		}
+	}
+	// imprints before their parents
+	for i := len(tree) - 1; i >= 0; i-- {
+		if err := p.publisherRepository.DeletePublisher(ctx, tree[i].String()); err != nil {
+			return err
+		}
+	}
+
+	slog.InfoContext(ctx, "publisher deleted", "publisher_id", id, "cascade", cascade, "imprints", len(tree)-1, "books", len(books))
+	return nil
+}
+
+func (p *publisherUseCase) FetchPublisherBooks(ctx context.Context, id string) (books *[]domain.Book, err error) {
+	ctx, span := p.tracer.Start(ctx, "publisherUseCase.FetchPublisherBooks", trace.WithAttributes(publisherIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := p.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	publisher, err := p.publisherRepository.GetPublisherByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := p.imprintTree(ctx, publisher.ID)
+	if err != nil {
+		return nil, err
+	}
+	ids := publisherSet(tree)
+	all, err := listBooks(ctx, p.bookUseCase, domain.BookFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	found := []domain.Book{}
+	for _, book := range all {
+		if book.PublisherID != nil && ids[*book.PublisherID] {
+			found = append(found, book)
+		}
+	}
+
+	return &found, nil
+}
+
+// imprintTree returns the ID of the publisher and of its imprints, theirs
+// included, each publisher before its imprints
+func (p *publisherUseCase) imprintTree(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{id}
+	seen := map[uuid.UUID]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		imprints, err := p.publisherRepository.FetchPublishers(ctx, domain.PublisherFilter{ParentID: ids[i]})
+		if err != nil {
+			return nil, err
+		}
+		for _, imprint := range *imprints {
+			if !seen[imprint.ID] {
+				seen[imprint.ID] = true
+				ids = append(ids, imprint.ID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// publisherSet is the set of the publishers of ids
+func publisherSet(ids []uuid.UUID) map[uuid.UUID]bool {
+	set := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+// checkParent rejects the parent of publisher when it is not in the
+// catalog, or when id, the publisher being updated, is among its
+// ancestors
+func (p *publisherUseCase) checkParent(ctx context.Context, id uuid.UUID, publisher *domain.Publisher) error {
+	seen := map[uuid.UUID]bool{}
+	for parentID := publisher.ParentID; parentID != nil && !seen[*parentID]; {
+		if *parentID == id {
+			return apperror.NewBadRequestCode("imprint_cycle", apperror.Params{"publisher_id": id})
+		}
+		seen[*parentID] = true
+
+		parent, err := p.publisherRepository.GetPublisherByID(ctx, parentID.String())
+		if errors.Is(err, &apperror.Error{Type: apperror.NotFound}) {
+			return apperror.NewBadRequestCode("unknown_parent", apperror.Params{"parent_id": *parentID})
+		}
+		if err != nil {
+			return err
+		}
+		parentID = parent.ParentID
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreatePublisher(t *testing.T) {
+	penguin := &domain.Publisher{ID: uuid.New(), Name: "Penguin Books"}
+	unknown := uuid.New()
+
+	tests := []struct {
+		name     string
+		parentID *uuid.UUID
+		code     string // of the error, none when accepted
+	}{
+		{name: "Publisher"},
+		{name: "Imprint", parentID: &penguin.ID},
+		{name: "Unknown parent", parentID: &unknown, code: "unknown_parent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPublisherRepo := new(appmock.MockPublisherRepository)
+			mockPublisherRepo.On("GetPublisherByID", mock.Anything, penguin.ID.String()).Return(penguin, nil).Maybe()
+			mockPublisherRepo.On("GetPublisherByID", mock.Anything, unknown.String()).Return((*domain.Publisher)(nil), apperror.NewNotFound("Publisher", "ID", unknown.String())).Maybe()
+			mockPublisherRepo.On("CreatePublisher", mock.Anything, mock.AnythingOfType("*domain.Publisher")).Return(nil).Maybe()
+
+			u := NewPublisherUseCase(mockPublisherRepo, new(appmock.MockBookUseCase))
+
+			err := u.CreatePublisher(context.Background(), &domain.Publisher{Name: "Puffin", ParentID: tt.parentID})
+
+			if tt.code == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, &apperror.Error{Type: apperror.BadRequest, Code: tt.code})
+			mockPublisherRepo.AssertNotCalled(t, "CreatePublisher", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdatePublisher_Cycle(t *testing.T) {
+	// penguin <- puffin <- picture puffin
+	penguin := &domain.Publisher{ID: uuid.New(), Name: "Penguin Books"}
+	puffin := &domain.Publisher{ID: uuid.New(), Name: "Puffin", ParentID: &penguin.ID}
+	picture := &domain.Publisher{ID: uuid.New(), Name: "Picture Puffin", ParentID: &puffin.ID}
+
+	tests := []struct {
+		name   string
+		parent *domain.Publisher
+	}{
+		{name: "Itself", parent: penguin},
+		{name: "Its own imprint", parent: picture},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPublisherRepo := new(appmock.MockPublisherRepository)
+			for _, p := range []*domain.Publisher{penguin, puffin, picture} {
+				mockPublisherRepo.On("GetPublisherByID", mock.Anything, p.ID.String()).Return(p, nil).Maybe()
+			}
+
+			u := NewPublisherUseCase(mockPublisherRepo, new(appmock.MockBookUseCase))
+
+			err := u.UpdatePublisher(context.Background(), penguin.ID.String(), &domain.Publisher{Name: penguin.Name, ParentID: &tt.parent.ID})
+
+			assert.ErrorIs(t, err, &apperror.Error{Type: apperror.BadRequest, Code: "imprint_cycle"})
+			mockPublisherRepo.AssertNotCalled(t, "UpdatePublisher", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestDeletePublisher(t *testing.T) {
+	penguin := &domain.Publisher{ID: uuid.New(), Name: "Penguin Books"}
+	puffin := domain.Publisher{ID: uuid.New(), Name: "Puffin", ParentID: &penguin.ID}
+	book := domain.Book{ID: uuid.New(), PublisherID: &penguin.ID}
+	imprintBook := domain.Book{ID: uuid.New(), PublisherID: &puffin.ID}
+	lent := domain.Book{ID: uuid.New(), PublisherID: &puffin.ID, Copies: &domain.CopyCounts{Total: 1}}
+
+	tests := []struct {
+		name         string
+		cascade      bool
+		imprints     []domain.Publisher
+		books        []domain.Book
+		imprintBooks []domain.Book
+		trash        []domain.TrashedBook
+		code         int
+	}{
+		{name: "Nothing left", code: http.StatusOK},
+		{name: "With books", books: []domain.Book{book}, code: http.StatusConflict},
+		{name: "With trashed books", trash: []domain.TrashedBook{{Book: book}}, code: http.StatusConflict},
+		{name: "With imprints", imprints: []domain.Publisher{puffin}, code: http.StatusConflict},
+		{name: "Cascade", cascade: true, imprints: []domain.Publisher{puffin}, books: []domain.Book{book}, imprintBooks: []domain.Book{imprintBook}, code: http.StatusOK},
+		{name: "Cascade - Active copies", cascade: true, imprints: []domain.Publisher{puffin}, books: []domain.Book{book}, imprintBooks: []domain.Book{lent}, code: http.StatusConflict},
+		{name: "Cascade - Trashed books of an imprint", cascade: true, imprints: []domain.Publisher{puffin}, books: []domain.Book{book}, trash: []domain.TrashedBook{{Book: imprintBook}}, code: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPublisherRepo := new(appmock.MockPublisherRepository)
+			mockPublisherRepo.On("GetPublisherByID", mock.Anything, penguin.ID.String()).Return(penguin, nil)
+			mockPublisherRepo.On("FetchPublishers", mock.Anything, domain.PublisherFilter{ParentID: penguin.ID}).Return(&tt.imprints, nil)
+			mockPublisherRepo.On("FetchPublishers", mock.Anything, domain.PublisherFilter{ParentID: puffin.ID}).Return(&[]domain.Publisher{}, nil).Maybe()
+			mockPublisherRepo.On("DeletePublisher", mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockBookUseCase := new(appmock.MockBookUseCase)
+			mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{PublisherID: penguin.ID}).Return(&tt.books, nil).Maybe()
+			mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{PublisherID: puffin.ID}).Return(&tt.imprintBooks, nil).Maybe()
+			mockBookUseCase.On("FetchTrash", mock.Anything).Return(&tt.trash, nil).Maybe()
+			mockBookUseCase.On("UpdateBook", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockBookUseCase.On("DeleteBook", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			u := NewPublisherUseCase(mockPublisherRepo, mockBookUseCase)
+
+			err := u.DeletePublisher(context.Background(), penguin.ID.String(), tt.cascade)
+
+			if tt.code != http.StatusOK {
+				assert.Equal(t, tt.code, apperror.Status(err))
+				mockPublisherRepo.AssertNotCalled(t, "DeletePublisher", mock.Anything, mock.Anything)
+				mockBookUseCase.AssertNotCalled(t, "UpdateBook", mock.Anything, mock.Anything, mock.Anything)
+				mockBookUseCase.AssertNotCalled(t, "DeleteBook", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			mockPublisherRepo.AssertCalled(t, "DeletePublisher", mock.Anything, penguin.ID.String())
+			if tt.cascade {
+				mockPublisherRepo.AssertCalled(t, "DeletePublisher", mock.Anything, puffin.ID.String())
+				for _, b := range []domain.Book{book, imprintBook} {
+					mockBookUseCase.AssertCalled(t, "UpdateBook", mock.Anything, b.ID.String(), mock.MatchedBy(func(b *domain.Book) bool { return b.PublisherID == nil }))
+					mockBookUseCase.AssertCalled(t, "DeleteBook", mock.Anything, b.ID.String())
+				}
+				// the imprint goes first
+				assert.Equal(t, puffin.ID.String(), mockPublisherRepo.Calls[len(mockPublisherRepo.Calls)-2].Arguments.Get(1))
+			}
+		})
+	}
+}
+
+func TestFetchPublisherBooks(t *testing.T) {
+	penguin := &domain.Publisher{ID: uuid.New(), Name: "Penguin Books"}
+	puffin := domain.Publisher{ID: uuid.New(), Name: "Puffin", ParentID: &penguin.ID}
+	other := uuid.New()
+	books := []domain.Book{
+		{ID: uuid.New(), Title: "Penguin", PublisherID: &penguin.ID},
+		{ID: uuid.New(), Title: "Other", PublisherID: &other},
+		{ID: uuid.New(), Title: "Unpublished"},
+		{ID: uuid.New(), Title: "Puffin", PublisherID: &puffin.ID},
+	}
+
+	mockPublisherRepo := new(appmock.MockPublisherRepository)
+	mockPublisherRepo.On("GetPublisherByID", mock.Anything, penguin.ID.String()).Return(penguin, nil).Once()
+	mockPublisherRepo.On("FetchPublishers", mock.Anything, domain.PublisherFilter{ParentID: penguin.ID}).Return(&[]domain.Publisher{puffin}, nil).Once()
+	mockPublisherRepo.On("FetchPublishers", mock.Anything, domain.PublisherFilter{ParentID: puffin.ID}).Return(&[]domain.Publisher{}, nil).Once()
+	mockBookUseCase := new(appmock.MockBookUseCase)
+	mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{}).Return(&books, nil).Once()
+
+	u := NewPublisherUseCase(mockPublisherRepo, mockBookUseCase)
+
+	found, err := u.FetchPublisherBooks(context.Background(), penguin.ID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Book{books[0], books[3]}, *found)
+	mockPublisherRepo.AssertExpectations(t)
+}
+
+func TestCreateBook_Publisher(t *testing.T) {
+	publisher := uuid.New()
+	unknown := uuid.New()
+
+	tests := []struct {
+		name        string
+		publisherID *uuid.UUID
+		code        string // of the error, none when accepted
+	}{
+		{name: "No publisher"},
+		{name: "Known publisher", publisherID: &publisher},
+		{name: "Unknown publisher", publisherID: &unknown, code: "unknown_publisher"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBookRepo := new(appmock.MockBookRepository)
+			mockBookRepo.On("CreateBook", mock.Anything, mock.AnythingOfType("*domain.Book")).Return(nil).Maybe()
+			mockPublisherRepo := new(appmock.MockPublisherRepository)
+			mockPublisherRepo.On("GetPublisherByID", mock.Anything, publisher.String()).Return(&domain.Publisher{ID: publisher}, nil).Maybe()
+			mockPublisherRepo.On("GetPublisherByID", mock.Anything, unknown.String()).Return((*domain.Publisher)(nil), apperror.NewNotFound("Publisher", "ID", unknown.String())).Maybe()
+
+			u := NewBookUseCase(mockBookRepo, WithPublisherRepository(mockPublisherRepo))
+
+			err := u.CreateBook(context.Background(), &domain.Book{Title: "Test Book", Author: "Test Author", PublicationDate: "2015", PublisherID: tt.publisherID})
+
+			if tt.code == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, &apperror.Error{Type: apperror.BadRequest, Code: tt.code})
+			mockBookRepo.AssertNotCalled(t, "CreateBook", mock.Anything, mock.Anything)
+		})
+	}
+}
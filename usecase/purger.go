@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/krittawatcode/books/domain"
+)
+
+// DefaultTrashRetention is how long deleted books stay in the trash
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// TrashPurger removes the books kept in the trash for longer than a
+// retention period
+type TrashPurger struct {
+	bookRepository domain.BookRepository
+	copyRepository domain.CopyRepository
+	retention      time.Duration
+	interval       time.Duration
+	now            func() time.Time
+}
+
+// NewTrashPurger purges the books trashed for longer than retention,
+// with the lost copies left to them in copyRepository, every interval
+// once started with Run
+func NewTrashPurger(bookRepository domain.BookRepository, copyRepository domain.CopyRepository, retention time.Duration, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		bookRepository: bookRepository,
+		copyRepository: copyRepository,
+		retention:      retention,
+		interval:       interval,
+		now:            time.Now,
+	}
+}
+
+// Purge removes the books trashed for longer than the retention period
+// and their copies
+func (p *TrashPurger) Purge(ctx context.Context) (int, error) {
+	purged, err := p.bookRepository.PurgeTrash(ctx, p.now().Add(-p.retention))
+	if err != nil {
+		return 0, err
+	}
+	if len(purged) == 0 {
+		return 0, nil
+	}
+
+	copies, err := p.copyRepository.DeleteBookCopies(ctx, purged)
+	if err != nil {
+		return 0, err
+	}
+
+	slog.InfoContext(ctx, "trash purged", "books", len(purged), "copies", copies)
+	return len(purged), nil
+}
+
+// Run purges once right away and then every interval, until ctx is done
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Purge(ctx); err != nil {
+			slog.ErrorContext(ctx, "could not purge the trash", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTrashPurger(t *testing.T) {
+	now := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Purge", func(t *testing.T) {
+		ids := []uuid.UUID{uuid.New(), uuid.New()}
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("PurgeTrash", mock.Anything, now.Add(-48*time.Hour)).Return(ids, nil).Once()
+		mockCopyRepo := new(appmock.MockCopyRepository)
+		mockCopyRepo.On("DeleteBookCopies", mock.Anything, ids).Return(1, nil).Once()
+
+		p := NewTrashPurger(mockBookRepo, mockCopyRepo, 48*time.Hour, time.Hour)
+		p.now = func() time.Time { return now }
+
+		purged, err := p.Purge(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, purged)
+		mockBookRepo.AssertExpectations(t)
+		mockCopyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Nothing to purge", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("PurgeTrash", mock.Anything, mock.Anything).Return([]uuid.UUID{}, nil).Once()
+		mockCopyRepo := new(appmock.MockCopyRepository)
+
+		p := NewTrashPurger(mockBookRepo, mockCopyRepo, time.Hour, time.Hour)
+
+		purged, err := p.Purge(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, purged)
+		mockCopyRepo.AssertNotCalled(t, "DeleteBookCopies", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Run keeps going after a failure", func(t *testing.T) {
+		var calls atomic.Int32
+		count := func(mock.Arguments) { calls.Add(1) }
+		mockBookRepo := new(appmock.MockBookRepository)
+		mockBookRepo.On("PurgeTrash", mock.Anything, mock.Anything).Run(count).Return([]uuid.UUID(nil), errors.New("database is down")).Once()
+		mockBookRepo.On("PurgeTrash", mock.Anything, mock.Anything).Run(count).Return([]uuid.UUID{}, nil)
+
+		p := NewTrashPurger(mockBookRepo, new(appmock.MockCopyRepository), time.Hour, time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			p.Run(ctx)
+			close(done)
+		}()
+
+		assert.Eventually(t, func() bool {
+			return calls.Load() >= 3
+		}, time.Second, time.Millisecond)
+		cancel()
+		<-done
+	})
+}
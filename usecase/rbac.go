@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+)
+
+// Policy maps each role to the permissions it grants, the anonymous
+// role applies to every caller, authenticated or not
+type Policy struct {
+	Roles map[string][]string `json:"roles"`
+}
+
+// DefaultPolicy lets anyone read, editors write and admins do anything
+func DefaultPolicy() *Policy {
+	return &Policy{Roles: map[string][]string{
+		domain.RoleAnonymous: {domain.PermissionBooksRead},
+		domain.RoleViewer:    {domain.PermissionBooksRead},
+		domain.RoleEditor:    {domain.PermissionBooksRead, domain.PermissionBooksWrite},
+		domain.RoleAdmin:     domain.Permissions,
+	}}
+}
+
+// LoadPolicy reads a Policy from a JSON file, eg.
+// {"roles": {"editor": ["books:read", "books:write"]}}
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read policy: %w", err)
+	}
+
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("could not parse policy %s: %w", path, err)
+	}
+
+	for role, permissions := range p.Roles {
+		for _, permission := range permissions {
+			if !knownPermission(permission) {
+				return nil, fmt.Errorf("policy %s grants unknown permission %q to %s", path, permission, role)
+			}
+		}
+	}
+
+	return &p, nil
+}
+
+type rbac struct {
+	roles map[string]map[string]bool
+}
+
+// NewRBAC authorizes users by the permissions their roles are granted
+// by p, and API keys by their scopes
+func NewRBAC(p *Policy) domain.Authorizer {
+	roles := map[string]map[string]bool{}
+	for role, permissions := range p.Roles {
+		roles[role] = map[string]bool{}
+		for _, permission := range permissions {
+			roles[role][permission] = true
+		}
+	}
+
+	return &rbac{roles: roles}
+}
+
+func (r *rbac) Authorize(ctx context.Context, permission string) error {
+	if r.roles[domain.RoleAnonymous][permission] {
+		return nil
+	}
+
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return apperror.NewAuthorization("authentication is required")
+	}
+
+	if p.Method == domain.AuthMethodAPIKey {
+		for _, scope := range p.Scopes {
+			if scope == permission {
+				return nil
+			}
+		}
+	} else {
+		for _, role := range p.Roles {
+			if r.roles[role][permission] {
+				return nil
+			}
+		}
+	}
+
+	return apperror.NewForbidden(fmt.Sprintf("the %s permission is required", permission))
+}
+
+func knownPermission(permission string) bool {
+	for _, p := range domain.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func as(p *domain.Principal) context.Context {
+	return domain.ContextWithPrincipal(context.Background(), p)
+}
+
+func TestRBAC(t *testing.T) {
+	rbac := NewRBAC(DefaultPolicy())
+
+	viewer := as(&domain.Principal{Subject: "viewer", Method: domain.AuthMethodJWT, Roles: []string{domain.RoleViewer}})
+	editor := as(&domain.Principal{Subject: "editor", Method: domain.AuthMethodJWT, Roles: []string{domain.RoleEditor}})
+	admin := as(&domain.Principal{Subject: "admin", Method: domain.AuthMethodJWT, Roles: []string{domain.RoleAdmin}})
+	apiKey := as(&domain.Principal{Subject: "api-key:1", Method: domain.AuthMethodAPIKey, Roles: []string{domain.RoleAdmin}, Scopes: []string{domain.ScopeBooksWrite}})
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		permission string
+		code       int
+	}{
+		{name: "Anonymous can read", ctx: context.Background(), permission: domain.PermissionBooksRead, code: http.StatusOK},
+		{name: "Anonymous cannot write", ctx: context.Background(), permission: domain.PermissionBooksWrite, code: http.StatusUnauthorized},
+		{name: "Viewer cannot write", ctx: viewer, permission: domain.PermissionBooksWrite, code: http.StatusForbidden},
+		{name: "Editor can write", ctx: editor, permission: domain.PermissionBooksWrite, code: http.StatusOK},
+		{name: "Editor cannot delete", ctx: editor, permission: domain.PermissionBooksDelete, code: http.StatusForbidden},
+		{name: "Admin can delete", ctx: admin, permission: domain.PermissionBooksDelete, code: http.StatusOK},
+		{name: "Admin can manage API keys", ctx: admin, permission: domain.PermissionAPIKeysManage, code: http.StatusOK},
+		{name: "API key has its scopes", ctx: apiKey, permission: domain.PermissionBooksWrite, code: http.StatusOK},
+		{name: "API key has no roles", ctx: apiKey, permission: domain.PermissionBooksDelete, code: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rbac.Authorize(tt.ctx, tt.permission)
+
+			if tt.code == http.StatusOK {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tt.code, apperror.Status(err))
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "policy.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		p, err := LoadPolicy(write(t, `{"roles": {"librarian": ["books:read", "books:write", "books:delete"]}}`))
+
+		assert.NoError(t, err)
+		librarian := as(&domain.Principal{Roles: []string{"librarian"}})
+		assert.NoError(t, NewRBAC(p).Authorize(librarian, domain.PermissionBooksDelete))
+		// nothing is granted to anonymous callers unless the policy says so
+		assert.Equal(t, http.StatusUnauthorized, apperror.Status(NewRBAC(p).Authorize(context.Background(), domain.PermissionBooksRead)))
+	})
+
+	t.Run("Unknown permission", func(t *testing.T) {
+		_, err := LoadPolicy(write(t, `{"roles": {"librarian": ["books:burn"]}}`))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		_, err := LoadPolicy(write(t, `{"roles": [`))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := LoadPolicy(filepath.Join(t.TempDir(), "missing.json"))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestWithAuthorizer(t *testing.T) {
+	editor := as(&domain.Principal{Subject: "editor", Method: domain.AuthMethodJWT, Roles: []string{domain.RoleEditor}})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		mockBookRepo := new(appmock.MockBookRepository)
+
+		u := NewBookUseCase(mockBookRepo, WithAuthorizer(NewRBAC(DefaultPolicy())))
+
+		err := u.DeleteBook(editor, "1")
+
+		assert.Equal(t, http.StatusForbidden, apperror.Status(err))
+		mockBookRepo.AssertNotCalled(t, "DeleteBook")
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		mockAPIKeyRepo := new(appmock.MockAPIKeyRepository)
+
+		u := NewAPIKeyUseCase(mockAPIKeyRepo, WithAuthorizer(NewRBAC(DefaultPolicy())))
+
+		_, err := u.FetchAPIKeys(context.Background())
+
+		assert.Equal(t, http.StatusUnauthorized, apperror.Status(err))
+		mockAPIKeyRepo.AssertNotCalled(t, "FetchAPIKeys")
+	})
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/apptrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// seriesIDKey is the span attribute of the series an operation is about
+const seriesIDKey = attribute.Key("series.id")
+
+type seriesUseCase struct {
+	options
+	seriesRepository domain.SeriesRepository
+	bookUseCase      domain.BookUseCase
+}
+
+// NewSeriesUseCase manages the series of seriesRepository, the books of
+// a series are read through bu
+func NewSeriesUseCase(seriesRepository domain.SeriesRepository, bu domain.BookUseCase, opts ...Option) domain.SeriesUseCase {
+	return &seriesUseCase{
+		options:          newOptions(opts),
+		seriesRepository: seriesRepository,
+		bookUseCase:      bu,
+	}
+}
+
+func (s *seriesUseCase) FetchSeries(ctx context.Context, filter domain.SeriesFilter) (series *[]domain.Series, err error) {
+	ctx, span := s.tracer.Start(ctx, "seriesUseCase.FetchSeries")
+	defer func() { apptrace.End(span, err) }()
+
+	if err := s.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	return s.seriesRepository.FetchSeries(ctx, filter)
+}
+
+func (s *seriesUseCase) GetSeriesByID(ctx context.Context, id string) (series *domain.Series, err error) {
+	ctx, span := s.tracer.Start(ctx, "seriesUseCase.GetSeriesByID", trace.WithAttributes(seriesIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := s.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	return s.seriesRepository.GetSeriesByID(ctx, id)
+}
+
+func (s *seriesUseCase) CreateSeries(ctx context.Context, series *domain.Series) (err error) {
+	ctx, span := s.tracer.Start(ctx, "seriesUseCase.CreateSeries")
+	defer func() { apptrace.End(span, err) }()
+
+	if err := s.authorize(ctx, domain.PermissionBooksWrite); err != nil {
+		return err
+	}
+
+	if err := s.seriesRepository.CreateSeries(ctx, series); err != nil {
+		return err
+	}
+
+	span.SetAttributes(seriesIDKey.String(series.ID.String()))
+	slog.InfoContext(ctx, "series created", "series_id", series.ID.String())
+	return nil
+}
+
+func (s *seriesUseCase) UpdateSeries(ctx context.Context, id string, series *domain.Series) (err error) {
+	ctx, span := s.tracer.Start(ctx, "seriesUseCase.UpdateSeries", trace.WithAttributes(seriesIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := s.authorize(ctx, domain.PermissionBooksWrite); err != nil {
+		return err
+	}
+
+	if err := s.seriesRepository.UpdateSeries(ctx, id, series); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "series updated", "series_id", id)
+	return nil
+}
+
+func (s *seriesUseCase) DeleteSeries(ctx context.Context, id string) (err error) {
+	ctx, span := s.tracer.Start(ctx, "seriesUseCase.DeleteSeries", trace.WithAttributes(seriesIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := s.authorize(ctx, domain.PermissionBooksDelete); err != nil {
+		return err
+	}
+
+	series, err := s.seriesRepository.GetSeriesByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	books, err := listBooks(ctx, s.bookUseCase, domain.BookFilter{SeriesID: series.ID})
+	if err != nil {
+		return err
+	}
+	if len(books) > 0 {
+		return apperror.NewConflictCode("books_of_series", apperror.Params{"series": series.Name})
+	}
+	// a trashed book may be restored, keep its series
+	trash, err := s.bookUseCase.FetchTrash(ctx)
+	if err != nil {
+		return err
+	}
+	for _, book := range *trash {
+		if book.InSeries(series.ID) {
+			return apperror.NewConflictCode("trashed_books_of_series", apperror.Params{"series": series.Name})
+		}
+	}
+
+	if err := s.seriesRepository.DeleteSeries(ctx, id); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "series deleted", "series_id", id)
+	return nil
+}
+
+func (s *seriesUseCase) FetchSeriesBooks(ctx context.Context, id string) (books *[]domain.Book, err error) {
+	ctx, span := s.tracer.Start(ctx, "seriesUseCase.FetchSeriesBooks", trace.WithAttributes(seriesIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := s.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	series, err := s.seriesRepository.GetSeriesByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// the repository lists them in reading order
+	found, err := listBooks(ctx, s.bookUseCase, domain.BookFilter{SeriesID: series.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &found, nil
+}
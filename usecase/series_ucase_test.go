@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeleteSeries(t *testing.T) {
+	series := &domain.Series{ID: uuid.New(), Name: "Discworld"}
+	member := domain.Book{ID: uuid.New(), Series: []domain.SeriesEntry{{SeriesID: series.ID, Position: 1}}}
+
+	tests := []struct {
+		name  string
+		books []domain.Book
+		trash []domain.TrashedBook
+		code  int
+	}{
+		{name: "No books", code: http.StatusOK},
+		{name: "With books", books: []domain.Book{member}, code: http.StatusConflict},
+		{name: "With trashed books", trash: []domain.TrashedBook{{Book: member}}, code: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSeriesRepo := new(appmock.MockSeriesRepository)
+			mockSeriesRepo.On("GetSeriesByID", mock.Anything, series.ID.String()).Return(series, nil)
+			mockSeriesRepo.On("DeleteSeries", mock.Anything, series.ID.String()).Return(nil).Maybe()
+			mockBookUseCase := new(appmock.MockBookUseCase)
+			if tt.books == nil {
+				mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{SeriesID: series.ID}).Return((*[]domain.Book)(nil), apperror.NewNotFound("Book", "ID", ""))
+			} else {
+				mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{SeriesID: series.ID}).Return(&tt.books, nil)
+			}
+			mockBookUseCase.On("FetchTrash", mock.Anything).Return(&tt.trash, nil).Maybe()
+
+			u := NewSeriesUseCase(mockSeriesRepo, mockBookUseCase)
+
+			err := u.DeleteSeries(context.Background(), series.ID.String())
+
+			if tt.code == http.StatusOK {
+				assert.NoError(t, err)
+				mockSeriesRepo.AssertCalled(t, "DeleteSeries", mock.Anything, series.ID.String())
+				return
+			}
+			assert.Equal(t, tt.code, apperror.Status(err))
+			mockSeriesRepo.AssertNotCalled(t, "DeleteSeries", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestFetchSeriesBooks(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		series := &domain.Series{ID: uuid.New(), Name: "Discworld"}
+		books := []domain.Book{{ID: uuid.New(), Title: "The Colour of Magic"}}
+		mockSeriesRepo := new(appmock.MockSeriesRepository)
+		mockSeriesRepo.On("GetSeriesByID", mock.Anything, series.ID.String()).Return(series, nil).Once()
+		mockBookUseCase := new(appmock.MockBookUseCase)
+		mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{SeriesID: series.ID}).Return(&books, nil).Once()
+
+		u := NewSeriesUseCase(mockSeriesRepo, mockBookUseCase)
+
+		found, err := u.FetchSeriesBooks(context.Background(), series.ID.String())
+
+		assert.NoError(t, err)
+		assert.Equal(t, books, *found)
+	})
+
+	t.Run("No books yet", func(t *testing.T) {
+		series := &domain.Series{ID: uuid.New(), Name: "Discworld"}
+		mockSeriesRepo := new(appmock.MockSeriesRepository)
+		mockSeriesRepo.On("GetSeriesByID", mock.Anything, series.ID.String()).Return(series, nil).Once()
+		mockBookUseCase := new(appmock.MockBookUseCase)
+		mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{SeriesID: series.ID}).Return((*[]domain.Book)(nil), apperror.NewNotFound("Book", "ID", "")).Once()
+
+		u := NewSeriesUseCase(mockSeriesRepo, mockBookUseCase)
+
+		found, err := u.FetchSeriesBooks(context.Background(), series.ID.String())
+
+		assert.NoError(t, err)
+		assert.Empty(t, *found)
+	})
+}
@@ -0,0 +1,380 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/apptrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// workIDKey is the span attribute of the work an operation is about
+const workIDKey = attribute.Key("work.id")
+
+type workUseCase struct {
+	options
+	workRepository domain.WorkRepository
+	bookUseCase    domain.BookUseCase
+}
+
+// NewWorkUseCase manages the works of workRepository, the editions of a
+// work are read and, by merges and splits, updated through bu so the
+// changes are authorized and audited like any other
+func NewWorkUseCase(workRepository domain.WorkRepository, bu domain.BookUseCase, opts ...Option) domain.WorkUseCase {
+	return &workUseCase{
+		options:        newOptions(opts),
+		workRepository: workRepository,
+		bookUseCase:    bu,
+	}
+}
+
+func (w *workUseCase) FetchWorks(ctx context.Context, filter domain.WorkFilter) (works *[]domain.Work, err error) {
+	ctx, span := w.tracer.Start(ctx, "workUseCase.FetchWorks")
+	defer func() { apptrace.End(span, err) }()
+
+	if err := w.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	return w.workRepository.FetchWorks(ctx, filter)
+}
+
+func (w *workUseCase) GetWorkByID(ctx context.Context, id string) (work *domain.Work, err error) {
+	ctx, span := w.tracer.Start(ctx, "workUseCase.GetWorkByID", trace.WithAttributes(workIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := w.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	return w.workRepository.GetWorkByID(ctx, id)
+}
+
+func (w *workUseCase) CreateWork(ctx context.Context, work *domain.Work) (err error) {
+	ctx, span := w.tracer.Start(ctx, "workUseCase.CreateWork")
+	defer func() { apptrace.End(span, err) }()
+
+	if err := w.authorize(ctx, domain.PermissionBooksWrite); err != nil {
+		return err
+	}
+
+	if err := w.workRepository.CreateWork(ctx, work); err != nil {
+		return err
+	}
+
+	span.SetAttributes(workIDKey.String(work.ID.String()))
+	slog.InfoContext(ctx, "work created", "work_id", work.ID.String())
+	return nil
+}
+
+func (w *workUseCase) UpdateWork(ctx context.Context, id string, work *domain.Work) (err error) {
+	ctx, span := w.tracer.Start(ctx, "workUseCase.UpdateWork", trace.WithAttributes(workIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := w.authorize(ctx, domain.PermissionBooksWrite); err != nil {
+		return err
+	}
+
+	if err := w.workRepository.UpdateWork(ctx, id, work); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "work updated", "work_id", id)
+	return nil
+}
+
+func (w *workUseCase) DeleteWork(ctx context.Context, id string) (err error) {
+	ctx, span := w.tracer.Start(ctx, "workUseCase.DeleteWork", trace.WithAttributes(workIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := w.authorize(ctx, domain.PermissionBooksDelete); err != nil {
+		return err
+	}
+
+	work, err := w.workRepository.GetWorkByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	editions, err := listBooks(ctx, w.bookUseCase, domain.BookFilter{WorkID: work.ID})
+	if err != nil {
+		return err
+	}
+	if len(editions) > 0 {
+		return apperror.NewConflictCode("editions_of_work", apperror.Params{"work": work.Title})
+	}
+	if err := w.checkTrash(ctx, work); err != nil {
+		return err
+	}
+
+	if err := w.workRepository.DeleteWork(ctx, id); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "work deleted", "work_id", id)
+	return nil
+}
+
+func (w *workUseCase) FetchEditions(ctx context.Context, id string) (books *[]domain.Book, err error) {
+	ctx, span := w.tracer.Start(ctx, "workUseCase.FetchEditions", trace.WithAttributes(workIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := w.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	work, err := w.workRepository.GetWorkByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	editions, err := listBooks(ctx, w.bookUseCase, domain.BookFilter{WorkID: work.ID})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(editions, func(i, j int) bool {
+		return editions[i].PublicationDate.Compare(editions[j].PublicationDate) < 0
+	})
+
+	return &editions, nil
+}
+
+func (w *workUseCase) SuggestWorks(ctx context.Context, minScore float64) (suggestions *[]domain.WorkSuggestion, err error) {
+	ctx, span := w.tracer.Start(ctx, "workUseCase.SuggestWorks", trace.WithAttributes(attribute.Float64("min_score", minScore)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := w.authorize(ctx, domain.PermissionBooksRead); err != nil {
+		return nil, err
+	}
+
+	if minScore <= 0 || minScore > 1 || math.IsNaN(minScore) {
+		return nil, apperror.NewBadRequestCode("invalid_min_score", apperror.Params{"min_score": minScore})
+	}
+
+	books, err := listBooks(ctx, w.bookUseCase, domain.BookFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	// link every pair of books by the same author with alike titles, the
+	// groups are the connected books
+	group := make([]int, len(books))
+	for i := range group {
+		group[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if group[i] != i {
+			group[i] = find(group[i])
+		}
+		return group[i]
+	}
+	score := map[int]float64{}
+	for i := range books {
+		for j := i + 1; j < len(books); j++ {
+			if !books[i].SameAuthor(&books[j]) {
+				continue
+			}
+			s := domain.TitleSimilarity(books[i].Title, books[j].Title)
+			if s < minScore {
+				continue
+			}
+			gi, gj := find(i), find(j)
+			low := s
+			for _, g := range []int{gi, gj} {
+				if v, ok := score[g]; ok && v < low {
+					low = v
+				}
+			}
+			delete(score, gi)
+			delete(score, gj)
+			group[gi] = gj
+			score[gj] = low
+		}
+	}
+
+	found := []domain.WorkSuggestion{}
+	members := map[int][]domain.Book{}
+	var roots []int
+	for i := range books {
+		g := find(i)
+		if _, ok := members[g]; !ok {
+			roots = append(roots, g)
+		}
+		members[g] = append(members[g], books[i])
+	}
+	for _, g := range roots {
+		if len(members[g]) < 2 || sameWork(members[g]) {
+			continue
+		}
+		found = append(found, domain.WorkSuggestion{Score: score[g], Books: members[g], WorkIDs: workIDs(members[g])})
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Score > found[j].Score
+	})
+
+	return &found, nil
+}
+
+func (w *workUseCase) MergeWorks(ctx context.Context, id string, merge *domain.WorkMerge) (work *domain.Work, err error) {
+	ctx, span := w.tracer.Start(ctx, "workUseCase.MergeWorks", trace.WithAttributes(workIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	// the editions are updated through the book use case, which checks
+	// books:write again for each of them
+	if err := w.authorize(ctx, domain.PermissionBooksWrite); err != nil {
+		return nil, err
+	}
+
+	work, err = w.workRepository.GetWorkByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// every work is checked and its editions listed before the first
+	// change, a work listed twice is merged once
+	var merged []*domain.Work
+	editions := map[uuid.UUID][]domain.Book{}
+	for _, otherID := range merge.WorkIDs {
+		if otherID == work.ID {
+			return nil, apperror.NewBadRequestCode("merge_into_itself", apperror.Params{"work_id": work.ID})
+		}
+		if _, ok := editions[otherID]; ok {
+			continue
+		}
+		other, err := w.workRepository.GetWorkByID(ctx, otherID.String())
+		if errors.Is(err, &apperror.Error{Type: apperror.NotFound}) {
+			return nil, apperror.NewBadRequestCode("unknown_work", apperror.Params{"work_id": otherID})
+		}
+		if err != nil {
+			return nil, err
+		}
+		// a trashed edition could not be moved and would be restored
+		// to a deleted work
+		if err := w.checkTrash(ctx, other); err != nil {
+			return nil, err
+		}
+		if editions[other.ID], err = listBooks(ctx, w.bookUseCase, domain.BookFilter{WorkID: other.ID}); err != nil {
+			return nil, err
+		}
+		merged = append(merged, other)
+	}
+
+	moved := 0
+	for _, other := range merged {
+		if err := w.moveEditions(ctx, editions[other.ID], work.ID); err != nil {
+			return nil, err
+		}
+		moved += len(editions[other.ID])
+		if err := w.workRepository.DeleteWork(ctx, other.ID.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	slog.InfoContext(ctx, "works merged", "work_id", id, "works", len(merged), "editions", moved)
+	return work, nil
+}
+
+func (w *workUseCase) SplitWork(ctx context.Context, id string, split *domain.WorkSplit) (work *domain.Work, err error) {
+	ctx, span := w.tracer.Start(ctx, "workUseCase.SplitWork", trace.WithAttributes(workIDKey.String(id)))
+	defer func() { apptrace.End(span, err) }()
+
+	if err := w.authorize(ctx, domain.PermissionBooksWrite); err != nil {
+		return nil, err
+	}
+	if len(split.BookIDs) == 0 {
+		return nil, apperror.NewBadRequestCode("nothing_to_split", apperror.Params{"work_id": id})
+	}
+
+	from, err := w.workRepository.GetWorkByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	editions, err := listBooks(ctx, w.bookUseCase, domain.BookFilter{WorkID: from.ID})
+	if err != nil {
+		return nil, err
+	}
+	byID := map[uuid.UUID]domain.Book{}
+	for _, book := range editions {
+		byID[book.ID] = book
+	}
+	var moved []domain.Book
+	seen := map[uuid.UUID]bool{}
+	for _, bookID := range split.BookIDs {
+		book, ok := byID[bookID]
+		if !ok {
+			return nil, apperror.NewBadRequestCode("not_an_edition", apperror.Params{"book_id": bookID, "work_id": from.ID})
+		}
+		if !seen[bookID] {
+			seen[bookID] = true
+			moved = append(moved, book)
+		}
+	}
+
+	work = &domain.Work{Title: split.Title}
+	if work.Title == "" {
+		work.Title = moved[0].Title
+	}
+	if err := w.workRepository.CreateWork(ctx, work); err != nil {
+		return nil, err
+	}
+	if err := w.moveEditions(ctx, moved, work.ID); err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("work.new_id", work.ID.String()))
+	slog.InfoContext(ctx, "work split", "work_id", id, "new_work_id", work.ID.String(), "editions", len(moved))
+	return work, nil
+}
+
+// moveEditions makes the books editions of the work workID
+func (w *workUseCase) moveEditions(ctx context.Context, books []domain.Book, workID uuid.UUID) error {
+	for _, book := range books {
+		book.WorkID = &workID
+		if err := w.bookUseCase.UpdateBook(ctx, book.ID.String(), &book); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTrash fails with a conflict while trashed books are editions of
+// work, as they may be restored. The trash is not listed, merging works
+// does not require the permission to empty it
+func (w *workUseCase) checkTrash(ctx context.Context, work *domain.Work) error {
+	trashed, err := w.bookUseCase.HasTrashedBooks(ctx, domain.BookFilter{WorkID: work.ID})
+	if err != nil {
+		return err
+	}
+	if trashed {
+		return apperror.NewConflictCode("trashed_editions_of_work", apperror.Params{"work": work.Title})
+	}
+	return nil
+}
+
+// sameWork reports whether the books are all editions of one work
+func sameWork(books []domain.Book) bool {
+	for _, book := range books {
+		if book.WorkID == nil || *book.WorkID != *books[0].WorkID {
+			return false
+		}
+	}
+	return true
+}
+
+// workIDs lists the works of the books, each once
+func workIDs(books []domain.Book) []uuid.UUID {
+	ids := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{}
+	for _, book := range books {
+		if book.WorkID != nil && !seen[*book.WorkID] {
+			seen[*book.WorkID] = true
+			ids = append(ids, *book.WorkID)
+		}
+	}
+	return ids
+}
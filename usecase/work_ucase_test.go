@@ -0,0 +1,294 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/krittawatcode/books/domain"
+	"github.com/krittawatcode/books/domain/apperror"
+	"github.com/krittawatcode/books/domain/appmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeleteWork(t *testing.T) {
+	work := &domain.Work{ID: uuid.New(), Title: "Dune"}
+	edition := domain.Book{ID: uuid.New(), WorkID: &work.ID}
+
+	tests := []struct {
+		name    string
+		books   []domain.Book
+		trashed bool
+		code    int
+	}{
+		{name: "No editions", code: http.StatusOK},
+		{name: "With editions", books: []domain.Book{edition}, code: http.StatusConflict},
+		{name: "With trashed editions", trashed: true, code: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWorkRepo := new(appmock.MockWorkRepository)
+			mockWorkRepo.On("GetWorkByID", mock.Anything, work.ID.String()).Return(work, nil)
+			mockWorkRepo.On("DeleteWork", mock.Anything, work.ID.String()).Return(nil).Maybe()
+			mockBookUseCase := new(appmock.MockBookUseCase)
+			if tt.books == nil {
+				mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{WorkID: work.ID}).Return((*[]domain.Book)(nil), apperror.NewNotFound("Book", "ID", ""))
+			} else {
+				mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{WorkID: work.ID}).Return(&tt.books, nil)
+			}
+			mockBookUseCase.On("HasTrashedBooks", mock.Anything, domain.BookFilter{WorkID: work.ID}).Return(tt.trashed, nil).Maybe()
+
+			u := NewWorkUseCase(mockWorkRepo, mockBookUseCase)
+
+			err := u.DeleteWork(context.Background(), work.ID.String())
+
+			if tt.code == http.StatusOK {
+				assert.NoError(t, err)
+				mockWorkRepo.AssertCalled(t, "DeleteWork", mock.Anything, work.ID.String())
+				return
+			}
+			assert.Equal(t, tt.code, apperror.Status(err))
+			mockWorkRepo.AssertNotCalled(t, "DeleteWork", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestFetchEditions(t *testing.T) {
+	work := &domain.Work{ID: uuid.New(), Title: "Dune"}
+	books := []domain.Book{
+		{ID: uuid.New(), PublicationDate: "2005-08-02", WorkID: &work.ID},
+		{ID: uuid.New(), PublicationDate: "1965", WorkID: &work.ID},
+		{ID: uuid.New(), PublicationDate: "1990-09", WorkID: &work.ID},
+	}
+	mockWorkRepo := new(appmock.MockWorkRepository)
+	mockWorkRepo.On("GetWorkByID", mock.Anything, work.ID.String()).Return(work, nil).Once()
+	mockBookUseCase := new(appmock.MockBookUseCase)
+	mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{WorkID: work.ID}).Return(&books, nil).Once()
+
+	u := NewWorkUseCase(mockWorkRepo, mockBookUseCase)
+
+	editions, err := u.FetchEditions(context.Background(), work.ID.String())
+
+	assert.NoError(t, err)
+	var dates []domain.PublicationDate
+	for _, b := range *editions {
+		dates = append(dates, b.PublicationDate)
+	}
+	assert.Equal(t, []domain.PublicationDate{"1965", "1990-09", "2005-08-02"}, dates)
+}
+
+func TestSuggestWorks(t *testing.T) {
+	grouped := uuid.New()
+	books := []domain.Book{
+		{ID: uuid.New(), Title: "Dune", Author: "Frank Herbert"},
+		{ID: uuid.New(), Title: "Emma", Author: "Jane Austen", WorkID: &grouped},
+		{ID: uuid.New(), Title: "Dune (40th Anniversary Edition)", Author: "Frank Herbert"},
+		{ID: uuid.New(), Title: "Emma: A Novel", Author: "Jane Austen", WorkID: &grouped},
+		{ID: uuid.New(), Title: "Dune Messiah", Author: "Frank Herbert"},
+		{ID: uuid.New(), Title: "Dune", Author: "Brian Herbert"},
+		{ID: uuid.New(), Title: "Pride and Prejudice", Author: "Jane Austen"},
+		{ID: uuid.New(), Title: "Pride & Prejudice", Author: "Jane Austen", WorkID: &grouped},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockBookUseCase := new(appmock.MockBookUseCase)
+		mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{}).Return(&books, nil).Once()
+
+		u := NewWorkUseCase(new(appmock.MockWorkRepository), mockBookUseCase)
+
+		suggestions, err := u.SuggestWorks(context.Background(), domain.DefaultWorkSuggestionScore)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.WorkSuggestion{
+			{Score: 1, Books: []domain.Book{books[0], books[2]}, WorkIDs: []uuid.UUID{}},
+			{Score: 2.0 / 3.0, Books: []domain.Book{books[6], books[7]}, WorkIDs: []uuid.UUID{grouped}},
+		}, *suggestions)
+	})
+
+	t.Run("Lower score", func(t *testing.T) {
+		mockBookUseCase := new(appmock.MockBookUseCase)
+		mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{}).Return(&books, nil).Once()
+
+		u := NewWorkUseCase(new(appmock.MockWorkRepository), mockBookUseCase)
+
+		suggestions, err := u.SuggestWorks(context.Background(), 0.5)
+
+		assert.NoError(t, err)
+		assert.Len(t, *suggestions, 2)
+		assert.Equal(t, 0.5, (*suggestions)[1].Score)
+		assert.Equal(t, []domain.Book{books[0], books[2], books[4]}, (*suggestions)[1].Books)
+	})
+
+	t.Run("No books", func(t *testing.T) {
+		mockBookUseCase := new(appmock.MockBookUseCase)
+		mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{}).Return((*[]domain.Book)(nil), apperror.NewNotFound("Book", "ID", "")).Once()
+
+		u := NewWorkUseCase(new(appmock.MockWorkRepository), mockBookUseCase)
+
+		suggestions, err := u.SuggestWorks(context.Background(), domain.DefaultWorkSuggestionScore)
+
+		assert.NoError(t, err)
+		assert.Empty(t, *suggestions)
+	})
+
+	for _, score := range []float64{0, -0.5, 1.5} {
+		t.Run("Invalid score", func(t *testing.T) {
+			u := NewWorkUseCase(new(appmock.MockWorkRepository), new(appmock.MockBookUseCase))
+
+			_, err := u.SuggestWorks(context.Background(), score)
+
+			assert.ErrorIs(t, err, &apperror.Error{Type: apperror.BadRequest, Code: "invalid_min_score"})
+		})
+	}
+}
+
+func TestMergeWorks(t *testing.T) {
+	work := &domain.Work{ID: uuid.New(), Title: "Dune"}
+	other := &domain.Work{ID: uuid.New(), Title: "Dune (Paperback)"}
+	unknown := uuid.New()
+
+	for name, ids := range map[string][]uuid.UUID{
+		"Success":      {other.ID},
+		"Listed twice": {other.ID, other.ID},
+	} {
+		t.Run(name, func(t *testing.T) {
+			editions := []domain.Book{{ID: uuid.New(), Title: "Dune", WorkID: &other.ID}, {ID: uuid.New(), Title: "Dune", WorkID: &other.ID}}
+			mockWorkRepo := new(appmock.MockWorkRepository)
+			mockWorkRepo.On("GetWorkByID", mock.Anything, work.ID.String()).Return(work, nil)
+			mockWorkRepo.On("GetWorkByID", mock.Anything, other.ID.String()).Return(other, nil)
+			mockWorkRepo.On("DeleteWork", mock.Anything, other.ID.String()).Return(nil).Once()
+			mockBookUseCase := new(appmock.MockBookUseCase)
+			mockBookUseCase.On("HasTrashedBooks", mock.Anything, domain.BookFilter{WorkID: other.ID}).Return(false, nil)
+			mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{WorkID: other.ID}).Return(&editions, nil).Once()
+			for _, b := range editions {
+				mockBookUseCase.On("UpdateBook", mock.Anything, b.ID.String(), mock.MatchedBy(func(b *domain.Book) bool {
+					return *b.WorkID == work.ID
+				})).Return(nil).Once()
+			}
+
+			u := NewWorkUseCase(mockWorkRepo, mockBookUseCase)
+
+			merged, err := u.MergeWorks(context.Background(), work.ID.String(), &domain.WorkMerge{WorkIDs: ids})
+
+			assert.NoError(t, err)
+			assert.Equal(t, work, merged)
+			mockBookUseCase.AssertExpectations(t)
+			mockWorkRepo.AssertExpectations(t)
+		})
+	}
+
+	tests := []struct {
+		name    string
+		ids     []uuid.UUID
+		trashed bool
+		err     *apperror.Error
+	}{
+		{name: "Into itself", ids: []uuid.UUID{work.ID}, err: &apperror.Error{Type: apperror.BadRequest, Code: "merge_into_itself"}},
+		{name: "Unknown work", ids: []uuid.UUID{unknown}, err: &apperror.Error{Type: apperror.BadRequest, Code: "unknown_work"}},
+		{name: "Unknown work after a known one", ids: []uuid.UUID{other.ID, unknown}, err: &apperror.Error{Type: apperror.BadRequest, Code: "unknown_work"}},
+		{name: "Trashed editions", ids: []uuid.UUID{other.ID}, trashed: true, err: &apperror.Error{Type: apperror.Conflict}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWorkRepo := new(appmock.MockWorkRepository)
+			mockWorkRepo.On("GetWorkByID", mock.Anything, work.ID.String()).Return(work, nil)
+			mockWorkRepo.On("GetWorkByID", mock.Anything, other.ID.String()).Return(other, nil).Maybe()
+			mockWorkRepo.On("GetWorkByID", mock.Anything, unknown.String()).Return((*domain.Work)(nil), apperror.NewNotFound("Work", "ID", unknown.String())).Maybe()
+			mockBookUseCase := new(appmock.MockBookUseCase)
+			mockBookUseCase.On("HasTrashedBooks", mock.Anything, domain.BookFilter{WorkID: other.ID}).Return(tt.trashed, nil).Maybe()
+			mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{WorkID: other.ID}).Return(&[]domain.Book{{ID: uuid.New(), WorkID: &other.ID}}, nil).Maybe()
+
+			u := NewWorkUseCase(mockWorkRepo, mockBookUseCase)
+
+			_, err := u.MergeWorks(context.Background(), work.ID.String(), &domain.WorkMerge{WorkIDs: tt.ids})
+
+			assert.ErrorIs(t, err, tt.err)
+			mockBookUseCase.AssertNotCalled(t, "UpdateBook", mock.Anything, mock.Anything, mock.Anything)
+			mockWorkRepo.AssertNotCalled(t, "DeleteWork", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("By an editor", func(t *testing.T) {
+		// the trash is checked without the permission to empty it
+		for _, trashed := range []bool{false, true} {
+			trash := []domain.TrashedBook{{Book: domain.Book{ID: uuid.New(), WorkID: &work.ID}}}
+			if trashed {
+				trash = append(trash, domain.TrashedBook{Book: domain.Book{ID: uuid.New(), WorkID: &other.ID}})
+			}
+			mockWorkRepo := new(appmock.MockWorkRepository)
+			mockWorkRepo.On("GetWorkByID", mock.Anything, work.ID.String()).Return(work, nil)
+			mockWorkRepo.On("GetWorkByID", mock.Anything, other.ID.String()).Return(other, nil)
+			mockWorkRepo.On("DeleteWork", mock.Anything, other.ID.String()).Return(nil).Maybe()
+			mockBookRepo := new(appmock.MockBookRepository)
+			mockBookRepo.On("FetchTrash", mock.Anything).Return(&trash, nil)
+			mockBookRepo.On("FetchBooks", mock.Anything, domain.BookFilter{WorkID: other.ID}).Return(&[]domain.Book{}, nil).Maybe()
+
+			authorizer := WithAuthorizer(NewRBAC(DefaultPolicy()))
+			u := NewWorkUseCase(mockWorkRepo, NewBookUseCase(mockBookRepo, authorizer), authorizer)
+			editor := as(&domain.Principal{Subject: "editor", Method: domain.AuthMethodJWT, Roles: []string{domain.RoleEditor}})
+
+			_, err := u.MergeWorks(editor, work.ID.String(), &domain.WorkMerge{WorkIDs: []uuid.UUID{other.ID}})
+
+			if trashed {
+				assert.ErrorIs(t, err, &apperror.Error{Type: apperror.Conflict, Code: "trashed_editions_of_work"})
+				mockWorkRepo.AssertNotCalled(t, "DeleteWork", mock.Anything, mock.Anything)
+				continue
+			}
+			assert.NoError(t, err)
+			mockWorkRepo.AssertCalled(t, "DeleteWork", mock.Anything, other.ID.String())
+		}
+	})
+}
+
+func TestSplitWork(t *testing.T) {
+	work := &domain.Work{ID: uuid.New(), Title: "Dune"}
+	editions := []domain.Book{
+		{ID: uuid.New(), Title: "Dune", WorkID: &work.ID},
+		{ID: uuid.New(), Title: "Dune Messiah", WorkID: &work.ID},
+	}
+
+	tests := []struct {
+		name  string
+		split domain.WorkSplit
+		title string
+		code  string // of the error, none when accepted
+	}{
+		{name: "Titled after the first book", split: domain.WorkSplit{BookIDs: []uuid.UUID{editions[1].ID, editions[1].ID}}, title: "Dune Messiah"},
+		{name: "Titled", split: domain.WorkSplit{BookIDs: []uuid.UUID{editions[1].ID}, Title: "Messiah"}, title: "Messiah"},
+		{name: "Not an edition", split: domain.WorkSplit{BookIDs: []uuid.UUID{uuid.New()}}, code: "not_an_edition"},
+		{name: "No books", split: domain.WorkSplit{}, code: "nothing_to_split"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWorkRepo := new(appmock.MockWorkRepository)
+			mockWorkRepo.On("GetWorkByID", mock.Anything, work.ID.String()).Return(work, nil)
+			mockWorkRepo.On("CreateWork", mock.Anything, mock.AnythingOfType("*domain.Work")).Run(func(args mock.Arguments) {
+				args.Get(1).(*domain.Work).ID = uuid.New()
+			}).Return(nil).Maybe()
+			mockBookUseCase := new(appmock.MockBookUseCase)
+			mockBookUseCase.On("FetchBooks", mock.Anything, domain.BookFilter{WorkID: work.ID}).Return(&editions, nil)
+			mockBookUseCase.On("UpdateBook", mock.Anything, editions[1].ID.String(), mock.AnythingOfType("*domain.Book")).Return(nil).Maybe()
+
+			u := NewWorkUseCase(mockWorkRepo, mockBookUseCase)
+
+			created, err := u.SplitWork(context.Background(), work.ID.String(), &tt.split)
+
+			if tt.code == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.title, created.Title)
+				mockBookUseCase.AssertNumberOfCalls(t, "UpdateBook", 1)
+				moved := mockBookUseCase.Calls[1].Arguments.Get(2).(*domain.Book)
+				assert.Equal(t, created.ID, *moved.WorkID)
+				return
+			}
+			assert.ErrorIs(t, err, &apperror.Error{Type: apperror.BadRequest, Code: tt.code})
+			mockWorkRepo.AssertNotCalled(t, "CreateWork", mock.Anything, mock.Anything)
+			mockBookUseCase.AssertNotCalled(t, "UpdateBook", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}